@@ -20,11 +20,34 @@ const (
 	DatabricksGoogleCredentials    = "databricks-google-credentials" //nolint:gosec
 	DatabricksGoogleServiceAccount = "databricks-google-service-account"
 
+	// Unified authentication
+	DatabricksConfigProfile = "databricks-config-profile"
+	DatabricksConfigFile    = "databricks-config-file"
+	DatabricksAuthType      = "databricks-auth-type"
+	DatabricksOidcTokenEnv  = "databricks-oidc-token-env"
+	DatabricksOidcTokenFile = "databricks-oidc-token-file"
+	DatabricksTokenAudience = "databricks-token-audience"
+
+	// Custom endpoints
+	DatabricksAccountHost           = "databricks-account-host"
+	DatabricksWorkspaceHostTemplate = "databricks-workspace-host-template"
+	DatabricksWorkspaceHosts        = "databricks-workspace-hosts"
+
+	DatabricksWorkspaceCredentials = "databricks-workspace-credentials"
+
 	DatabricksSqlWarehouses = "databricks-sql-warehouses"
 	DatabricksPlatform      = "databricks-platform"
 
+	DatabricksRequestsPerSecond = "databricks-requests-per-second"
+	DatabricksRetryTimeout      = "databricks-retry-timeout"
+
 	DatabricksDataUsageWindow = "databricks-data-usage-window"
 
+	DatabricksMissingEmailStrategy     = "databricks-missing-email-strategy"
+	DatabricksLinkByExternalId         = "databricks-link-by-external-id"
+	DatabricksIdentityStoreMaster      = "databricks-identity-store-master"
+	DatabricksWorkspaceLocalIdentities = "databricks-workspace-local-identities"
+
 	DatabricksExcludeWorkspaces = "databricks-exclude-workspaces"
 	DatabricksIncludeWorkspaces = "databricks-include-workspaces"
 	DatabricksExcludeMetastores = "databricks-exclude-metastores"
@@ -35,8 +58,30 @@ const (
 	DatabricksIncludeSchemas    = "databricks-include-schemas"
 	DatabricksExcludeTables     = "databricks-exclude-tables"
 	DatabricksIncludeTables     = "databricks-include-tables"
+	DatabricksDataObjectFilter  = "databricks-data-object-filter"
+
+	DatabricksTableDetails = "databricks-table-details"
+
+	DatabricksCatalogParallelism  = "databricks-catalog-parallelism"
+	DatabricksSchemaParallelism   = "databricks-schema-parallelism"
+	DatabricksCheckpointFile      = "databricks-checkpoint-file"
+	DatabricksMetadataCacheFile   = "databricks-metadata-cache-file"
+	DatabricksMetadataCacheMaxAge = "databricks-metadata-cache-max-age"
 
 	DatabricksIncludeMetastoreInGrantName = "databricks-include-metastore-in-grant-name"
+	DatabricksImportEffectivePermissions  = "databricks-import-effective-permissions"
+	DatabricksGrantGrouping               = "databricks-grant-grouping"
+	DatabricksUsageGrantStateFile         = "databricks-usage-grant-state-file"
+	DatabricksAbacFunctionSchema          = "databricks-abac-function-schema"
+	DatabricksAbacFilterColumnTags        = "databricks-abac-filter-column-tags"
+	DatabricksManageAccountRoles          = "databricks-manage-account-roles"
+
+	DatabricksTagLoading         = "databricks-tag-loading"
+	DatabricksTagExportFile      = "databricks-tag-export-file"
+	DatabricksTagExportStateFile = "databricks-tag-export-state-file"
+
+	DatabricksLineageFile   = "databricks-lineage-file"
+	DatabricksLineageWindow = "databricks-lineage-window"
 
 	WorkspaceType        = "workspace"
 	MetastoreType        = "metastore"
@@ -45,4 +90,7 @@ const (
 	MaterializedViewType = "materializedview"
 
 	TagSource = "Databricks"
+
+	DefaultRequestsPerSecond = 15
+	DefaultRetryTimeout      = 300
 )
@@ -52,6 +52,16 @@ type dataAccessAccountRepository interface {
 	ListServicePrincipals(ctx context.Context, optFn ...func(options *types2.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal]
 	ListWorkspaceAssignments(ctx context.Context, workspaceId int64) ([]iam.PermissionAssignment, error)
 	UpdateWorkspaceAssignment(ctx context.Context, workspaceId int64, principalId int64, permission []iam.WorkspacePermission) error
+	CreateGroup(ctx context.Context, displayName string) (*iam.Group, error)
+	DeleteGroup(ctx context.Context, groupId string) error
+	UpdateGroupExternalId(ctx context.Context, groupId string, externalId string) error
+	UpdateGroupMembers(ctx context.Context, groupId string, add []string, remove []string) error
+	UpdateUserRoles(ctx context.Context, userId string, add []string, remove []string) error
+	UpdateServicePrincipalRoles(ctx context.Context, servicePrincipalId string, add []string, remove []string) error
+	UpdateGroupRoles(ctx context.Context, groupId string, add []string, remove []string) error
+	GetAccountRuleSet(ctx context.Context) (*iam.RuleSetResponse, error)
+	UpdateAccountRuleSet(ctx context.Context, etag string, grantRules []iam.GrantRule) error
+	UpdateMetastoreOwner(ctx context.Context, metastoreId string, owner string) error
 	accountRepository
 }
 
@@ -59,10 +69,18 @@ type dataAccessAccountRepository interface {
 type dataAccessWorkspaceRepository interface {
 	Ping(ctx context.Context) error
 	GetPermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string) (*catalog.PermissionsList, error)
+	GetEffectivePermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string) (*catalog.EffectivePermissionsList, error)
 	SetPermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string, changes ...catalog.PermissionsChange) error
 	SqlWarehouseRepository(warehouseId string) repo.WarehouseRepository
 	GetOwner(ctx context.Context, securableType catalog.SecurableType, fullName string) (string, error)
 	GetCatalogWorkspaceBinding(ctx context.Context, catalogName string) (*catalog.WorkspaceBinding, error)
+	ListUsers(ctx context.Context, optFn ...func(options *types2.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User]
+	ListGroups(ctx context.Context, optFn ...func(options *types2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group]
+	ListServicePrincipals(ctx context.Context, optFn ...func(options *types2.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal]
+	UpdateUserEntitlements(ctx context.Context, userId string, add []string, remove []string) error
+	UpdateServicePrincipalEntitlements(ctx context.Context, servicePrincipalId string, add []string, remove []string) error
+	UpdateGroupEntitlements(ctx context.Context, groupId string, add []string, remove []string) error
+	Me(ctx context.Context) (*iam.User, error)
 	workspaceRepository
 }
 
@@ -75,6 +93,8 @@ type AccessSyncer struct {
 	privilegeCache types.PrivilegeCache
 
 	apFeedbackObjects map[string]sync_to_target.AccessProviderSyncFeedback // Cache apFeedback objects
+	roleGroups        map[string]string                                    // Raito role id to account group name
+	usageGrants       *types.UsageGrantState                               // Nil if implicit usage grants are not cleaned up
 }
 
 func NewAccessSyncer() *AccessSyncer {
@@ -93,6 +113,8 @@ func NewAccessSyncer() *AccessSyncer {
 }
 
 func (a *AccessSyncer) SyncAccessProvidersFromTarget(ctx context.Context, accessProviderHandler wrappers.AccessProviderHandler, configMap *config.ConfigMap) (err error) {
+	defer repo.LogThrottlingStats()
+
 	defer func() {
 		if err != nil {
 			logger.Error(fmt.Sprintf("SyncAccessProvidersFromTarget failed: %s", err.Error()))
@@ -119,20 +141,32 @@ func (a *AccessSyncer) SyncAccessProvidersFromTarget(ctx context.Context, access
 		return fmt.Errorf("data object traverser: %w", err)
 	}
 
-	groups, err := repo.ChannelToSet(func(ctx context.Context) <-chan repo.ChannelItem[iam.Group] {
-		return accountRepo.ListGroups(ctx)
-	}, func(group iam.Group) string {
-		return group.DisplayName
-	})
+	// The principals of the account are listed once, for the grants, the roles and the account roles
+	identities, err := listAccountIdentities(ctx, accountRepo)
 	if err != nil {
-		return fmt.Errorf("list groups: %w", err)
+		return err
 	}
 
-	servicePrincipals, err := repo.ChannelToSet(func(ctx context.Context) <-chan repo.ChannelItem[iam.ServicePrincipal] {
-		return accountRepo.ListServicePrincipals(ctx)
-	}, func(servicePrincipal iam.ServicePrincipal) string {
-		return servicePrincipal.ApplicationId
-	})
+	groups := set.NewSet[string]()
+	roleWhat := make(map[string]map[data_source.DataObjectReference]set.Set[string])
+
+	for _, group := range identities.groups {
+		groups.Add(group.DisplayName)
+
+		if isRoleGroup(group) {
+			roleWhat[group.DisplayName] = make(map[data_source.DataObjectReference]set.Set[string])
+		}
+	}
+
+	servicePrincipals := set.NewSet[string]()
+	for _, servicePrincipal := range identities.servicePrincipals {
+		servicePrincipals.Add(servicePrincipal.ApplicationId)
+	}
+
+	grouping, err := parseGrantGrouping(configMap.GetString(constants.DatabricksGrantGrouping))
+	if err != nil {
+		return err
+	}
 
 	apDataObjectVisitor := AccessProviderVisitor{
 		syncer:                a,
@@ -145,7 +179,18 @@ func (a *AccessSyncer) SyncAccessProvidersFromTarget(ctx context.Context, access
 
 		groups:                        groups,
 		servicePrincipals:             servicePrincipals,
+		roleWhat:                      roleWhat,
 		includeMetastoreInExternalAps: configMap.GetBoolWithDefault(constants.DatabricksIncludeMetastoreInGrantName, false),
+		importEffectivePermissions:    configMap.GetBoolWithDefault(constants.DatabricksImportEffectivePermissions, false),
+		manageAccountRoles:            configMap.GetBoolWithDefault(constants.DatabricksManageAccountRoles, false),
+	}
+
+	if grouping != grantGroupingNone {
+		apDataObjectVisitor.grantGrouper = newGrantGrouper(grouping)
+	}
+
+	if apDataObjectVisitor.importEffectivePermissions {
+		apDataObjectVisitor.inheritedGrants = newGrantGrouper(grantGroupingPrincipalSet)
 	}
 
 	err = traverser.Traverse(ctx, &apDataObjectVisitor, func(traverserOptions *DataObjectTraverserOptions) {
@@ -155,10 +200,36 @@ func (a *AccessSyncer) SyncAccessProvidersFromTarget(ctx context.Context, access
 		return err
 	}
 
+	if apDataObjectVisitor.grantGrouper != nil {
+		err = accessProviderHandler.AddAccessProviders(apDataObjectVisitor.grantGrouper.AccessProviders(apDataObjectVisitor.principalsToWhoItem)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if apDataObjectVisitor.inheritedGrants != nil {
+		err = accessProviderHandler.AddAccessProviders(apDataObjectVisitor.inheritedGrants.AccessProviders(apDataObjectVisitor.principalsToWhoItem)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = a.syncRolesFromTarget(accessProviderHandler, identities, roleWhat)
+	if err != nil {
+		return fmt.Errorf("sync roles from target: %w", err)
+	}
+
+	err = a.syncAccountRolesFromTarget(ctx, accessProviderHandler, accountRepo, identities, accountId, apDataObjectVisitor.manageAccountRoles)
+	if err != nil {
+		return fmt.Errorf("sync account roles from target: %w", err)
+	}
+
 	return nil
 }
 
 func (a *AccessSyncer) SyncAccessProviderToTarget(ctx context.Context, accessProviders *sync_to_target.AccessProviderImport, accessProviderFeedbackHandler wrappers.AccessProviderFeedbackHandler, configMap *config.ConfigMap) (err error) {
+	defer repo.LogThrottlingStats()
+
 	defer func() {
 		if err != nil {
 			logger.Error(fmt.Sprintf("SyncAccessProviderToTarget failed: %s", err.Error()))
@@ -180,7 +251,17 @@ func (a *AccessSyncer) SyncAccessProviderToTarget(ctx context.Context, accessPro
 
 	permissionsChanges := types.NewPrivilegesChangeCollection()
 	a.apFeedbackObjects = make(map[string]sync_to_target.AccessProviderSyncFeedback)
+	a.roleGroups = make(map[string]string)
+	a.usageGrants = nil
 
+	if stateFile := configMap.GetString(constants.DatabricksUsageGrantStateFile); stateFile != "" {
+		a.usageGrants, err = types.LoadUsageGrantState(stateFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	roles := make([]*sync_to_target.AccessProvider, 0, len(accessProviders.AccessProviders))
 	grants := make([]*sync_to_target.AccessProvider, 0, len(accessProviders.AccessProviders))
 	masksAps := make([]*sync_to_target.AccessProvider, 0, len(accessProviders.AccessProviders))
 	filters := make([]*sync_to_target.AccessProvider, 0, len(accessProviders.AccessProviders))
@@ -188,7 +269,11 @@ func (a *AccessSyncer) SyncAccessProviderToTarget(ctx context.Context, accessPro
 	for i := range accessProviders.AccessProviders {
 		switch accessProviders.AccessProviders[i].Action {
 		case aptypes.Grant, aptypes.Purpose:
-			grants = append(grants, accessProviders.AccessProviders[i])
+			if isRoleAccessProvider(accessProviders.AccessProviders[i]) {
+				roles = append(roles, accessProviders.AccessProviders[i])
+			} else {
+				grants = append(grants, accessProviders.AccessProviders[i])
+			}
 		case aptypes.Mask:
 			masksAps = append(masksAps, accessProviders.AccessProviders[i])
 		case aptypes.Filtered:
@@ -208,7 +293,9 @@ func (a *AccessSyncer) SyncAccessProviderToTarget(ctx context.Context, accessPro
 
 	a.syncFiltersToTarget(ctx, filters, configMap, &repoCache)
 	a.syncMasksToTarget(ctx, masksAps, configMap, &repoCache)
-	a.syncGrantsToTarget(ctx, grants, &permissionsChanges)
+	roleGroupsToDelete := a.syncRolesToTarget(ctx, roles, accountRepo, &permissionsChanges)
+	a.syncGrantsToTarget(ctx, grants, accountRepo, &permissionsChanges)
+	a.cleanupUsageGrants(&permissionsChanges)
 
 	defer func() {
 		for _, feedbackItem := range a.apFeedbackObjects {
@@ -221,20 +308,90 @@ func (a *AccessSyncer) SyncAccessProviderToTarget(ctx context.Context, accessPro
 		a.apFeedbackObjects = nil
 	}()
 
+	computePlaneRepoFn := func(workspaceId int64) (dataAccessWorkspaceRepository, error) {
+		workspaces, wErr := accountRepo.GetWorkspaces(ctx)
+		if wErr != nil {
+			return nil, fmt.Errorf("get workspaces: %w", wErr)
+		}
+
+		for i := range workspaces {
+			if workspaces[i].WorkspaceId == workspaceId {
+				return utils.InitWorkspaceRepo(ctx, repoCredentials, pltfrm, &workspaces[i], a.workspaceRepoFactory)
+			}
+		}
+
+		return nil, fmt.Errorf("workspace %d not found", workspaceId)
+	}
+
+	manageAccountRoles := configMap.GetBoolWithDefault(constants.DatabricksManageAccountRoles, false)
+	pluginPrincipalFn := func() (string, error) {
+		return a.resolvePluginPrincipal(ctx, accountRepo, repoCredentials, pltfrm)
+	}
+
 	for item, principlePrivilegesMap := range permissionsChanges.Iterator() {
 		utils.MemoryUsage(logger.Debug)
 
-		if item.Type == constants.WorkspaceType {
-			a.storePrivilegesInComputePlane(ctx, item, principlePrivilegesMap, accountRepo)
+		if item.Type == constants.MetastoreType {
+			a.storeMetastoreAdmin(ctx, item, principlePrivilegesMap, accountRepo, manageAccountRoles)
+
+			if len(principlePrivilegesMap) == 0 {
+				continue
+			}
+		}
+
+		if item.Type == data_source.Datasource {
+			a.storeAccountRoles(ctx, principlePrivilegesMap, accountRepo, manageAccountRoles, pluginPrincipalFn)
+		} else if item.Type == constants.WorkspaceType {
+			a.storePrivilegesInComputePlane(ctx, item, principlePrivilegesMap, accountRepo, computePlaneRepoFn)
 		} else {
 			a.storePrivilegesInDataplane(ctx, item, &repoCache, principlePrivilegesMap)
 		}
 	}
 
+	// Groups of deleted roles are only removed after all their privileges are revoked
+	a.deleteRoleGroups(ctx, roleGroupsToDelete, accountRepo)
+
+	if a.usageGrants != nil {
+		err = a.usageGrants.Save()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (a *AccessSyncer) syncGrantsToTarget(ctx context.Context, grants []*sync_to_target.AccessProvider, permissionsChanges *types.PrivilegesChangeCollection) {
+// cleanupUsageGrants keeps usage grants that are still required by other access providers and revokes the usage grants created by Raito that are no longer required
+func (a *AccessSyncer) cleanupUsageGrants(permissionsChanges *types.PrivilegesChangeCollection) {
+	if a.usageGrants == nil {
+		return
+	}
+
+	for item, principlePrivilegesMap := range permissionsChanges.Iterator() {
+		for principal, privilegesChanges := range principlePrivilegesMap {
+			for _, privilege := range privilegesChanges.Remove.Slice() {
+				if a.usageGrants.IsReferenced(types.UsageGrantKey{Item: item, Principal: principal, Privilege: privilege}) {
+					logger.Debug(fmt.Sprintf("Usage privilege %s on %q is still required for %q and will not be revoked", privilege, item.FullName, principal))
+					privilegesChanges.Remove.Remove(privilege)
+				}
+			}
+		}
+	}
+
+	owned, notOwned := a.usageGrants.Unreferenced()
+
+	for _, key := range owned {
+		logger.Info(fmt.Sprintf("Revoke usage privilege %s on %q for %q as it is no longer required", key.Privilege, key.Item.FullName, key.Principal))
+		permissionsChanges.RemovePrivilege(key.Item, key.Principal, key.Privilege)
+	}
+
+	// Usage grants that existed before Raito are never revoked
+	for _, key := range notOwned {
+		a.usageGrants.Delete(key)
+	}
+}
+
+func (a *AccessSyncer) syncGrantsToTarget(ctx context.Context, grants []*sync_to_target.AccessProvider, accountRepo dataAccessAccountRepository, permissionsChanges *types.PrivilegesChangeCollection) {
 	for _, grant := range grants {
 		feedbackElement := sync_to_target.AccessProviderSyncFeedback{
 			AccessProvider: grant.Id,
@@ -243,14 +400,15 @@ func (a *AccessSyncer) syncGrantsToTarget(ctx context.Context, grants []*sync_to
 		feedbackElement.ActualName = grant.Id
 		feedbackElement.Type = ptr.String(access_provider.AclSet)
 
-		apErr := a.syncGrantToTarget(ctx, grant, permissionsChanges)
+		apErr := a.syncGrantToTarget(ctx, grant, accountRepo, permissionsChanges)
 		if apErr != nil {
 			feedbackElement.Errors = append(feedbackElement.Errors, apErr.Error())
 		} else {
 			feedbackElement.State = &sync_to_target.AccessProviderFeedbackState{
 				Who: sync_to_target.AccessProviderWhoFeedbackState{
-					Users:  grant.Who.Users,
-					Groups: grant.Who.Groups,
+					Users:       grant.Who.Users,
+					Groups:      grant.Who.Groups,
+					InheritFrom: grant.Who.InheritFrom,
 				},
 			}
 		}
@@ -265,7 +423,14 @@ func (a *AccessSyncer) syncMasksToTarget(ctx context.Context, maskAps []*sync_to
 			AccessProvider: mask.Id,
 		}
 
-		maskName, apErr := a.syncMaskToTarget(ctx, mask, configMap, repoCache)
+		var maskName string
+		var apErr error
+
+		if isAbacAccessProvider(mask) {
+			maskName, apErr = a.syncAbacMaskToTarget(ctx, mask, configMap, repoCache)
+		} else {
+			maskName, apErr = a.syncMaskToTarget(ctx, mask, configMap, repoCache)
+		}
 
 		feedbackElement.ExternalId = &maskName
 		feedbackElement.ActualName = maskName
@@ -293,6 +458,28 @@ func (a *AccessSyncer) syncFiltersToTarget(ctx context.Context, filters []*sync_
 			AccessProvider: filter.Id,
 		}
 
+		if isAbacAccessProvider(filter) {
+			policyName, err := a.syncAbacFilterToTarget(ctx, filter, configMap, repoCache)
+
+			feedbackElement.ExternalId = &policyName
+			feedbackElement.ActualName = policyName
+
+			if err != nil {
+				feedbackElement.Errors = append(feedbackElement.Errors, err.Error())
+			} else {
+				feedbackElement.State = &sync_to_target.AccessProviderFeedbackState{
+					Who: sync_to_target.AccessProviderWhoFeedbackState{
+						Users:  filter.Who.Users,
+						Groups: filter.Who.Groups,
+					},
+				}
+			}
+
+			a.apFeedbackObjects[filter.Id] = feedbackElement
+
+			continue
+		}
+
 		if len(filter.What) != 1 || filter.What[0].DataObject.Type != data_source.Table && filter.What[0].DataObject.Type != data_source.View {
 			feedbackElement.Errors = append(feedbackElement.Errors, "Unsupported what item(s)")
 			a.apFeedbackObjects[filter.Id] = feedbackElement
@@ -561,7 +748,7 @@ func (a *AccessSyncer) deleteRowFilter(ctx context.Context, sqlClient repo.Wareh
 	return nil
 }
 
-func (a *AccessSyncer) storePrivilegesInComputePlane(ctx context.Context, item types.SecurableItemKey, principlePrivilegesMap map[string]*types.PrivilegesChanges, repo dataAccessAccountRepository) {
+func (a *AccessSyncer) storePrivilegesInComputePlane(ctx context.Context, item types.SecurableItemKey, principlePrivilegesMap map[string]*types.PrivilegesChanges, repo dataAccessAccountRepository, workspaceRepoFn func(workspaceId int64) (dataAccessWorkspaceRepository, error)) {
 	workspaceId, err := strconv.ParseInt(item.FullName, 10, 64)
 	if err != nil {
 		for _, privilegesChanges := range principlePrivilegesMap {
@@ -571,12 +758,33 @@ func (a *AccessSyncer) storePrivilegesInComputePlane(ctx context.Context, item t
 		return
 	}
 
+	var workspaceRepo dataAccessWorkspaceRepository
+
+	for _, privilegesChanges := range principlePrivilegesMap {
+		if !hasEntitlementChanges(privilegesChanges) {
+			continue
+		}
+
+		workspaceRepo, err = workspaceRepoFn(workspaceId)
+		if err != nil {
+			err = fmt.Errorf("workspace repository for workspace %d: %w", workspaceId, err)
+		}
+
+		break
+	}
+
 	for principal, privilegesChanges := range principlePrivilegesMap {
-		a.storePrivilegesInComputePlaneForPrincipal(ctx, principal, repo, workspaceId, privilegesChanges)
+		if err != nil && hasEntitlementChanges(privilegesChanges) {
+			a.handleAccessProviderError(privilegesChanges, err)
+
+			continue
+		}
+
+		a.storePrivilegesInComputePlaneForPrincipal(ctx, principal, repo, workspaceRepo, workspaceId, privilegesChanges)
 	}
 }
 
-func (a *AccessSyncer) storePrivilegesInComputePlaneForPrincipal(ctx context.Context, principal string, repo dataAccessAccountRepository, workspaceId int64, privilegesChanges *types.PrivilegesChanges) {
+func (a *AccessSyncer) storePrivilegesInComputePlaneForPrincipal(ctx context.Context, principal string, repo dataAccessAccountRepository, workspaceRepo dataAccessWorkspaceRepository, workspaceId int64, privilegesChanges *types.PrivilegesChanges) {
 	var err error
 
 	defer func() {
@@ -586,6 +794,7 @@ func (a *AccessSyncer) storePrivilegesInComputePlaneForPrincipal(ctx context.Con
 	}()
 
 	var principalId int64
+	var updateEntitlements func(ctx context.Context, id string, add []string, remove []string) error
 
 	if strings.Contains(principal, "@") {
 		var user *iam.User
@@ -599,23 +808,72 @@ func (a *AccessSyncer) storePrivilegesInComputePlaneForPrincipal(ctx context.Con
 		if err != nil {
 			return
 		}
+
+		if workspaceRepo != nil {
+			updateEntitlements = workspaceRepo.UpdateUserEntitlements
+		}
 	} else {
 		var group *iam.Group
 
-		group, err = a.getGroupIdFromName(ctx, principal, repo)
+		group, err = a.findGroupByName(ctx, principal, repo)
 		if err != nil {
 			return
 		}
 
-		principalId, err = strconv.ParseInt(group.Id, 10, 64)
+		if group != nil {
+			principalId, err = strconv.ParseInt(group.Id, 10, 64)
+			if err != nil {
+				return
+			}
+
+			if workspaceRepo != nil {
+				updateEntitlements = workspaceRepo.UpdateGroupEntitlements
+			}
+		} else {
+			// Service principals are referenced by their application id
+			var servicePrincipal *iam.ServicePrincipal
+
+			servicePrincipal, err = a.getServicePrincipalFromApplicationId(ctx, principal, repo)
+			if err != nil {
+				return
+			}
+
+			principalId, err = strconv.ParseInt(servicePrincipal.Id, 10, 64)
+			if err != nil {
+				return
+			}
+
+			if workspaceRepo != nil {
+				updateEntitlements = workspaceRepo.UpdateServicePrincipalEntitlements
+			}
+		}
+	}
+
+	assignmentsToAdd, entitlementsToAdd := splitWorkspacePrivileges(privilegesChanges.Add.Slice())
+	assignmentsToRemove, entitlementsToRemove := splitWorkspacePrivileges(privilegesChanges.Remove.Slice())
+
+	if len(assignmentsToAdd) > 0 || len(assignmentsToRemove) > 0 || len(entitlementsToAdd)+len(entitlementsToRemove) == 0 {
+		err = repo.UpdateWorkspaceAssignment(ctx, workspaceId, principalId, workspacePermissionsToDatabricksPermissions(assignmentsToAdd))
 		if err != nil {
 			return
 		}
 	}
 
-	err = repo.UpdateWorkspaceAssignment(ctx, workspaceId, principalId, workspacePermissionsToDatabricksPermissions(privilegesChanges.Add.Slice()))
-	if err != nil {
-		return
+	if len(entitlementsToAdd) > 0 || len(entitlementsToRemove) > 0 {
+		if updateEntitlements == nil {
+			err = fmt.Errorf("no workspace repository available to update entitlements of %q", principal)
+
+			return
+		}
+
+		entitlementsToRemove = slices.DeleteFunc(entitlementsToRemove, func(e string) bool { return slices.Contains(entitlementsToAdd, e) })
+
+		err = updateEntitlements(ctx, strconv.FormatInt(principalId, 10), entitlementsToAdd, entitlementsToRemove)
+		if err != nil {
+			err = fmt.Errorf("update entitlements of %q: %w", principal, err)
+
+			return
+		}
 	}
 }
 
@@ -627,6 +885,38 @@ func (a *AccessSyncer) handleAccessProviderError(privilegeChanges *types.Privile
 	}
 }
 
+// accountIdentities contains all users, service principals and groups of the account
+type accountIdentities struct {
+	users             []*iam.User
+	servicePrincipals []*iam.ServicePrincipal
+	groups            []*iam.Group
+}
+
+type accountIdentityRepository interface {
+	ListUsers(ctx context.Context, optFn ...func(options *types2.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User]
+	ListServicePrincipals(ctx context.Context, optFn ...func(options *types2.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal]
+	ListGroups(ctx context.Context, optFn ...func(options *types2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group]
+}
+
+func listAccountIdentities(ctx context.Context, accountRepo accountIdentityRepository) (*accountIdentities, error) {
+	users, err := collectChannelItems(accountRepo.ListUsers(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+
+	servicePrincipals, err := collectChannelItems(accountRepo.ListServicePrincipals(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list service principals: %w", err)
+	}
+
+	groups, err := collectChannelItems(accountRepo.ListGroups(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+
+	return &accountIdentities{users: users, servicePrincipals: servicePrincipals, groups: groups}, nil
+}
+
 func (a *AccessSyncer) getUserFromEmail(ctx context.Context, email string, accountRepo dataAccessAccountRepository) (*iam.User, error) {
 	cancelCtx, cancelFn := context.WithCancel(ctx)
 	defer cancelFn()
@@ -643,7 +933,37 @@ func (a *AccessSyncer) getUserFromEmail(ctx context.Context, email string, accou
 	return nil, fmt.Errorf("no user found for email %q", email)
 }
 
+func (a *AccessSyncer) getServicePrincipalFromApplicationId(ctx context.Context, applicationId string, accountRepo dataAccessAccountRepository) (*iam.ServicePrincipal, error) {
+	cancelCtx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	servicePrincipals := accountRepo.ListServicePrincipals(cancelCtx, func(options *types2.DatabricksServicePrincipalFilter) { options.ApplicationId = &applicationId })
+	for servicePrincipal := range servicePrincipals {
+		if servicePrincipal.HasError() {
+			return nil, fmt.Errorf("list service principal item: %w", servicePrincipal.Error())
+		} else {
+			return servicePrincipal.I, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no service principal found with application id %q", applicationId)
+}
+
 func (a *AccessSyncer) getGroupIdFromName(ctx context.Context, groupname string, accountRepo dataAccessAccountRepository) (*iam.Group, error) {
+	group, err := a.findGroupByName(ctx, groupname, accountRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	if group == nil {
+		return nil, fmt.Errorf("no groupe found with name %q", groupname)
+	}
+
+	return group, nil
+}
+
+// findGroupByName returns the account group with the given name or nil if no such group exists
+func (a *AccessSyncer) findGroupByName(ctx context.Context, groupname string, accountRepo dataAccessAccountRepository) (*iam.Group, error) {
 	cancelCtx, cancelFn := context.WithCancel(ctx)
 	defer cancelFn()
 
@@ -656,7 +976,7 @@ func (a *AccessSyncer) getGroupIdFromName(ctx context.Context, groupname string,
 		}
 	}
 
-	return nil, fmt.Errorf("no groupe found with name %q", groupname)
+	return nil, nil
 }
 
 func (a *AccessSyncer) storePrivilegesInDataplane(ctx context.Context, item types.SecurableItemKey, repoCache *MetastoreRepoCache, principlePrivilegesMap map[string]*types.PrivilegesChanges) {
@@ -694,6 +1014,18 @@ func (a *AccessSyncer) storePrivilegesInDataplane(ctx context.Context, item type
 
 	logger.Debug(fmt.Sprintf("sync privileges for %s %q via workspace %q", item.Type, fullname, workspaceDeploymentName))
 
+	securableType, err := typeToSecurableType(item.Type)
+	if err != nil {
+		return
+	}
+
+	if a.usageGrants != nil && a.usageGrants.HasPending(item) {
+		err = a.resolvePendingUsageGrants(ctx, repo, item, securableType, fullname, principlePrivilegesMap)
+		if err != nil {
+			return
+		}
+	}
+
 	changes := make([]catalog.PermissionsChange, 0, len(principlePrivilegesMap))
 
 	for principal, privilegesChanges := range principlePrivilegesMap {
@@ -711,16 +1043,52 @@ func (a *AccessSyncer) storePrivilegesInDataplane(ctx context.Context, item type
 		})
 	}
 
-	securableType, err := typeToSecurableType(item.Type)
+	err = repo.SetPermissionsOnResource(ctx, securableType, fullname, changes...)
 	if err != nil {
+		err = fmt.Errorf("set permissions on %s %q via workspace %q: %w", securableType.String(), fullname, workspaceDeploymentName, err)
 		return
 	}
 
-	err = repo.SetPermissionsOnResource(ctx, securableType, fullname, changes...)
+	if a.usageGrants != nil {
+		// Revoked usage grants are no longer tracked
+		for _, change := range changes {
+			for _, privilege := range change.Remove {
+				key := types.UsageGrantKey{Item: item, Principal: change.Principal, Privilege: string(privilege)}
+				if !a.usageGrants.IsReferenced(key) {
+					a.usageGrants.Delete(key)
+				}
+			}
+		}
+	}
+}
+
+// resolvePendingUsageGrants determines whether new usage grants already existed before Raito granted them
+func (a *AccessSyncer) resolvePendingUsageGrants(ctx context.Context, repo dataAccessWorkspaceRepository, item types.SecurableItemKey, securableType catalog.SecurableType, fullname string, principlePrivilegesMap map[string]*types.PrivilegesChanges) error {
+	permissionsList, err := repo.GetPermissionsOnResource(ctx, securableType, fullname)
 	if err != nil {
-		err = fmt.Errorf("set permissions on %s %q via workspace %q: %w", securableType.String(), fullname, workspaceDeploymentName, err)
-		return
+		return fmt.Errorf("get permissions on %s %q: %w", securableType.String(), fullname, err)
 	}
+
+	existingGrants := set.NewSet[types.UsageGrantKey]()
+
+	if permissionsList != nil {
+		for _, assignment := range permissionsList.PrivilegeAssignments {
+			for _, privilege := range assignment.Privileges {
+				existingGrants.Add(types.UsageGrantKey{Item: item, Principal: assignment.Principal, Privilege: string(privilege)})
+			}
+		}
+	}
+
+	for principal, privilegesChanges := range principlePrivilegesMap {
+		for privilege := range privilegesChanges.Add {
+			key := types.UsageGrantKey{Item: item, Principal: principal, Privilege: privilege}
+			if a.usageGrants.IsPending(key) {
+				a.usageGrants.SetOwned(key, !existingGrants.Contains(key))
+			}
+		}
+	}
+
+	return nil
 }
 
 func (a *AccessSyncer) syncMaskToTarget(ctx context.Context, ap *sync_to_target.AccessProvider, configMap *config.ConfigMap, repoCache *MetastoreRepoCache) (maskName string, _ error) {
@@ -941,27 +1309,63 @@ func (a *AccessSyncer) syncMasksGetSchema(ap *sync_to_target.AccessProvider) map
 	return schemas
 }
 
-func (a *AccessSyncer) syncGrantToTarget(_ context.Context, ap *sync_to_target.AccessProvider, changeCollection *types.PrivilegesChangeCollection) error {
+func (a *AccessSyncer) syncGrantToTarget(ctx context.Context, ap *sync_to_target.AccessProvider, accountRepo dataAccessAccountRepository, changeCollection *types.PrivilegesChangeCollection) error {
 	logger.Debug(fmt.Sprintf("Syncing access provider %q to target", ap.Name))
 
-	principals := make([]string, 0, len(ap.Who.Users)+len(ap.Who.Groups))
+	inheritedGroups, err := a.resolveInheritedRoles(ctx, ap.Who.InheritFrom, accountRepo)
+	if err != nil {
+		return err
+	}
+
+	principals := make([]string, 0, len(ap.Who.Users)+len(ap.Who.Groups)+len(inheritedGroups))
 	principals = append(principals, ap.Who.Users...)
 	principals = append(principals, ap.Who.Groups...)
+	principals = append(principals, inheritedGroups...)
 
 	var deletedPrincipals []string
 
 	if ap.DeletedWho != nil {
-		deletedPrincipals = make([]string, 0, len(ap.DeletedWho.Users)+len(ap.DeletedWho.Groups))
+		deletedInheritedGroups, err := a.resolveInheritedRoles(ctx, ap.DeletedWho.InheritFrom, accountRepo)
+		if err != nil {
+			return err
+		}
+
+		deletedPrincipals = make([]string, 0, len(ap.DeletedWho.Users)+len(ap.DeletedWho.Groups)+len(deletedInheritedGroups))
 		deletedPrincipals = append(deletedPrincipals, ap.DeletedWho.Users...)
 		deletedPrincipals = append(deletedPrincipals, ap.DeletedWho.Groups...)
+		deletedPrincipals = append(deletedPrincipals, deletedInheritedGroups...)
 	}
 
+	// All privileges are converted first, so nothing is changed if the access provider contains unsupported permissions
+	removePrivilegesMaps := make([]map[data_source.DataObjectReference]set.Set[string], 0, len(ap.What))
+	addPrivilegesMaps := make([]map[data_source.DataObjectReference]set.Set[string], 0, len(ap.What))
+
 	for i := range ap.What {
 		removePrivilegesMap, addPrivilegesMap, err := permissionsToDatabricksPrivileges(&ap.What[i])
 		if err != nil {
 			return err
 		}
 
+		removePrivilegesMaps = append(removePrivilegesMaps, removePrivilegesMap)
+		addPrivilegesMaps = append(addPrivilegesMaps, addPrivilegesMap)
+	}
+
+	deletePrivilegesMaps := make([]map[data_source.DataObjectReference]set.Set[string], 0, len(ap.DeleteWhat))
+
+	for i := range ap.DeleteWhat {
+		privilegesMap, _, err := permissionsToDatabricksPrivileges(&ap.DeleteWhat[i])
+		if err != nil {
+			return err
+		}
+
+		deletePrivilegesMaps = append(deletePrivilegesMaps, privilegesMap)
+	}
+
+	if a.usageGrants != nil {
+		a.usageGrants.Release(ap.Id)
+	}
+
+	for _, removePrivilegesMap := range removePrivilegesMaps {
 		for do, privileges := range removePrivilegesMap {
 			itemKey := types.SecurableItemKey{
 				Type:     do.Type,
@@ -980,7 +1384,9 @@ func (a *AccessSyncer) syncGrantToTarget(_ context.Context, ap *sync_to_target.A
 				changeCollection.RemovePrivilege(itemKey, deletedPrincipal, privilegesSlice...)
 			}
 		}
+	}
 
+	for _, addPrivilegesMap := range addPrivilegesMaps {
 		for do, privileges := range addPrivilegesMap {
 			itemKey := types.SecurableItemKey{
 				Type:     do.Type,
@@ -995,17 +1401,14 @@ func (a *AccessSyncer) syncGrantToTarget(_ context.Context, ap *sync_to_target.A
 
 					// Add to cache, it must be ignored in sync from target
 					a.privilegeCache.AddPrivilege(do, principal, privilegesSlice...)
+
+					a.referenceUsageGrants(itemKey, ap.Id, principal, privilegesSlice)
 				}
 			}
 		}
 	}
 
-	for i := range ap.DeleteWhat {
-		privilegesMap, _, err := permissionsToDatabricksPrivileges(&ap.DeleteWhat[i])
-		if err != nil {
-			return err
-		}
-
+	for _, privilegesMap := range deletePrivilegesMaps {
 		for do, privileges := range privilegesMap {
 			itemKey := types.SecurableItemKey{
 				Type:     do.Type,
@@ -1027,6 +1430,22 @@ func (a *AccessSyncer) syncGrantToTarget(_ context.Context, ap *sync_to_target.A
 	return nil
 }
 
+func (a *AccessSyncer) referenceUsageGrants(item types.SecurableItemKey, apId string, principal string, privileges []string) {
+	if a.usageGrants == nil {
+		return
+	}
+
+	for _, privilege := range privileges {
+		if isUsagePrivilege(privilege) {
+			a.usageGrants.Reference(types.UsageGrantKey{Item: item, Principal: principal, Privilege: privilege}, apId)
+		}
+	}
+}
+
+func isUsagePrivilege(privilege string) bool {
+	return privilege == string(catalog.PrivilegeUseCatalog) || privilege == string(catalog.PrivilegeUseSchema)
+}
+
 func (a *AccessSyncer) loadMetastores(ctx context.Context, configMap *config.ConfigMap) ([]catalog.MetastoreInfo, []provisioning.Workspace, map[string][]*provisioning.Workspace, error) {
 	pltfrm, accountId, repoCredentials, err := utils.GetAndValidateParameters(configMap)
 	if err != nil {
@@ -1106,7 +1525,7 @@ func permissionToDatabricksPrivilege(p string) string {
 
 func addUsageToUpperDataObjects(result map[data_source.DataObjectReference]set.Set[string], object data_source.DataObjectReference) error {
 	switch object.Type {
-	case constants.MetastoreType, constants.WorkspaceType:
+	case data_source.Datasource, constants.MetastoreType, constants.WorkspaceType:
 		return nil
 	case constants.CatalogType:
 		utils.AddToSetInMap(result, object, string(catalog.PrivilegeUseCatalog))
@@ -1211,6 +1630,45 @@ func workspacePermissionsToDatabricksPermissions(p []string) []iam.WorkspacePerm
 	return result
 }
 
+// workspaceEntitlements contains all Databricks entitlements that can be managed as workspace permission
+var workspaceEntitlements = []string{"workspace-access", "databricks-sql-access", "allow-cluster-create", "allow-instance-pool-create"}
+
+func entitlementToPrivilege(entitlement string) string {
+	return strings.ToUpper(strings.ReplaceAll(entitlement, "-", "_"))
+}
+
+func privilegeToEntitlement(privilege string) (string, bool) {
+	for _, entitlement := range workspaceEntitlements {
+		if entitlementToPrivilege(entitlement) == privilege {
+			return entitlement, true
+		}
+	}
+
+	return "", false
+}
+
+// splitWorkspacePrivileges splits the privileges on a workspace in workspace assignment permissions and entitlements
+func splitWorkspacePrivileges(privileges []string) (assignments []string, entitlements []string) {
+	assignments = make([]string, 0, len(privileges))
+
+	for _, privilege := range privileges {
+		if entitlement, isEntitlement := privilegeToEntitlement(privilege); isEntitlement {
+			entitlements = append(entitlements, entitlement)
+		} else {
+			assignments = append(assignments, privilege)
+		}
+	}
+
+	return assignments, entitlements
+}
+
+func hasEntitlementChanges(privilegesChanges *types.PrivilegesChanges) bool {
+	_, added := splitWorkspacePrivileges(privilegesChanges.Add.Slice())
+	_, removed := splitWorkspacePrivileges(privilegesChanges.Remove.Slice())
+
+	return len(added) > 0 || len(removed) > 0
+}
+
 func raitoPrefixName(name string) string {
 	return strings.ToLower(fmt.Sprintf("%s%s", raitoPrefix, strings.ReplaceAll(strings.ToUpper(name), " ", "_")))
 }
@@ -1223,6 +1681,12 @@ type AccessProviderVisitor struct {
 
 	groups            set.Set[string]
 	servicePrincipals set.Set[string]
+	roleWhat          map[string]map[data_source.DataObjectReference]set.Set[string] // Raito role group name -> data object -> permissions
+
+	importEffectivePermissions bool
+	manageAccountRoles         bool
+	grantGrouper               *grantGrouper // Nil if imported grants are not grouped
+	inheritedGrants            *grantGrouper // Privileges inherited from parent securables, nil if effective permissions are not imported
 
 	repoCredentials               types2.RepositoryCredentials
 	accountId                     string
@@ -1309,6 +1773,104 @@ func (a *AccessProviderVisitor) VisitWorkspace(ctx context.Context, workspace *p
 		}
 	}
 
+	err = a.syncEntitlementsFromTarget(ctx, workspace)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Unable to load entitlements for workspace %q: %s", workspace.WorkspaceName, err.Error()))
+	}
+
+	return nil
+}
+
+func (a *AccessProviderVisitor) syncEntitlementsFromTarget(ctx context.Context, workspace *provisioning.Workspace) error {
+	workspaceClient, err := a.getWorkspaceRepository(workspace)
+	if err != nil {
+		return fmt.Errorf("unable to get workspace repository: %w", err)
+	}
+
+	do := data_source.DataObjectReference{FullName: strconv.FormatInt(workspace.WorkspaceId, 10), Type: constants.WorkspaceType}
+	entitlementsToSync := make(map[string]*sync_from_target.WhoItem)
+
+	addEntitlements := func(principal string, entitlements []iam.ComplexValue, addToWho func(who *sync_from_target.WhoItem)) {
+		for _, entitlement := range entitlements {
+			privilege := entitlementToPrivilege(entitlement.Value)
+
+			if _, supported := privilegeToEntitlement(privilege); !supported {
+				continue
+			}
+
+			if a.syncer.privilegeCache.ContainsPrivilege(do, principal, privilege) {
+				continue
+			}
+
+			if _, found := entitlementsToSync[privilege]; !found {
+				entitlementsToSync[privilege] = &sync_from_target.WhoItem{}
+			}
+
+			addToWho(entitlementsToSync[privilege])
+		}
+	}
+
+	cancelCtx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	for user := range workspaceClient.ListUsers(cancelCtx) {
+		if user.HasError() {
+			return fmt.Errorf("list users: %w", user.Error())
+		}
+
+		addEntitlements(user.I.UserName, user.I.Entitlements, func(who *sync_from_target.WhoItem) {
+			who.Users = append(who.Users, user.I.UserName)
+		})
+	}
+
+	for servicePrincipal := range workspaceClient.ListServicePrincipals(cancelCtx) {
+		if servicePrincipal.HasError() {
+			return fmt.Errorf("list service principals: %w", servicePrincipal.Error())
+		}
+
+		addEntitlements(servicePrincipal.I.ApplicationId, servicePrincipal.I.Entitlements, func(who *sync_from_target.WhoItem) {
+			who.Users = append(who.Users, servicePrincipal.I.ApplicationId)
+		})
+	}
+
+	for group := range workspaceClient.ListGroups(cancelCtx) {
+		if group.HasError() {
+			return fmt.Errorf("list groups: %w", group.Error())
+		}
+
+		addEntitlements(group.I.DisplayName, group.I.Entitlements, func(who *sync_from_target.WhoItem) {
+			who.Groups = append(who.Groups, group.I.DisplayName)
+		})
+	}
+
+	for privilege, whoItems := range entitlementsToSync {
+		humanReadablePrivilege := strings.ReplaceAll(privilege, "_", " ")
+
+		apExternalId := fmt.Sprintf("%s_%s", workspace.WorkspaceName, privilege)
+		apName := fmt.Sprintf("%s %s - %s", TitleCaser.String(constants.WorkspaceType), workspace.WorkspaceName, humanReadablePrivilege)
+
+		err = a.accessProviderHandler.AddAccessProviders(
+			&sync_from_target.AccessProvider{
+				ExternalId: apExternalId,
+				Action:     aptypes.Grant,
+				Name:       apName,
+				NamingHint: apName,
+				ActualName: apName,
+				Type:       ptr.String(access_provider.AclSet),
+				What: []sync_from_target.WhatItem{
+					{
+						DataObject:  &data_source.DataObjectReference{FullName: do.FullName, Type: do.Type},
+						Permissions: []string{humanReadablePrivilege},
+					},
+				},
+				Who: whoItems,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -1343,7 +1905,9 @@ func (a *AccessProviderVisitor) VisitMetastore(ctx context.Context, metastore *c
 
 	logger.Debug(fmt.Sprintf("Load permissions on metastore %q", metastore.MetastoreId))
 
-	permissionsList, err := workspaceClient.GetPermissionsOnResource(ctx, catalog.SecurableTypeMetastore, metastore.MetastoreId)
+	permissionsList, err := checkpointedResponse(ctx, "permissions", func() (*catalog.PermissionsList, error) {
+		return workspaceClient.GetPermissionsOnResource(ctx, catalog.SecurableTypeMetastore, metastore.MetastoreId)
+	})
 	if err != nil {
 		return err
 	}
@@ -1355,7 +1919,7 @@ func (a *AccessProviderVisitor) VisitMetastore(ctx context.Context, metastore *c
 		return err
 	}
 
-	return nil
+	return a.addMetastoreAdmin(metastore)
 }
 
 func (a *AccessProviderVisitor) VisitCatalog(ctx context.Context, c *catalog.CatalogInfo, _ *catalog.MetastoreInfo, workspace *provisioning.Workspace) error {
@@ -1508,7 +2072,13 @@ func (a *AccessProviderVisitor) getWorkspaceRepository(workspace *provisioning.W
 }
 
 func (a *AccessProviderVisitor) syncAccessProviderObjectFromTarget(ctx context.Context, workspaceClient dataAccessWorkspaceRepository, metastoreName, metastoreId, fullName string, doType string, securableType catalog.SecurableType) error {
-	permissionsList, err := workspaceClient.GetPermissionsOnResource(ctx, securableType, fullName)
+	if a.importEffectivePermissions {
+		return a.syncEffectivePermissionsFromTarget(ctx, workspaceClient, metastoreName, metastoreId, fullName, doType, securableType)
+	}
+
+	permissionsList, err := checkpointedResponse(ctx, "permissions/"+fullName, func() (*catalog.PermissionsList, error) {
+		return workspaceClient.GetPermissionsOnResource(ctx, securableType, fullName)
+	})
 	if err != nil {
 		return err
 	}
@@ -1524,6 +2094,19 @@ func (a *AccessProviderVisitor) addPermissionIfNotSetByRaito(apNamePrefix string
 	privilegeToPrincipleMap := make(map[catalog.Privilege][]string)
 
 	for _, assignment := range assignments.PrivilegeAssignments {
+		// Privileges of Raito managed account groups are imported as part of the role
+		if roleWhat, isRole := a.roleWhat[assignment.Principal]; isRole {
+			for _, privilege := range assignment.Privileges {
+				if _, found := roleWhat[*do]; !found {
+					roleWhat[*do] = set.NewSet[string]()
+				}
+
+				roleWhat[*do].Add(strings.ToUpper(strings.ReplaceAll(privilege.String(), "_", " ")))
+			}
+
+			continue
+		}
+
 		for _, privilege := range assignment.Privileges {
 			logger.Debug(fmt.Sprintf("Check if privilege was assigned by Raito: {%s, %s}, %s, %v", do.FullName, do.Type, assignment.Principal, privilege))
 
@@ -1539,23 +2122,16 @@ func (a *AccessProviderVisitor) addPermissionIfNotSetByRaito(apNamePrefix string
 	for privilege, principleList := range privilegeToPrincipleMap {
 		humanReadablePrivilege := strings.ToUpper(strings.ReplaceAll(privilege.String(), "_", " "))
 
+		if a.grantGrouper != nil {
+			a.grantGrouper.Add(*do, humanReadablePrivilege, principleList)
+
+			continue
+		}
+
 		externalId := fmt.Sprintf("%s_%s", do.FullName, privilege.String())
 		apName := fmt.Sprintf("%s - %s", apNamePrefix, humanReadablePrivilege)
 
-		whoItems := sync_from_target.WhoItem{}
-
-		// The principal can be a user email address, a group name or a service principal ID (https://docs.databricks.com/api/workspace/grants/get#privilege_assignments)
-		for _, principal := range principleList {
-			if a.servicePrincipals.Contains(principal) {
-				whoItems.Users = append(whoItems.Users, principal)
-			} else if a.groups.Contains(principal) {
-				whoItems.Groups = append(whoItems.Groups, principal)
-			} else if strings.Contains(principal, "@") {
-				whoItems.Users = append(whoItems.Users, principal)
-			} else {
-				logger.Warn(fmt.Sprintf("Unable to find to validate if %q is users, group or service principal", principal))
-			}
-		}
+		whoItems := a.principalsToWhoItem(principleList)
 
 		err := a.accessProviderHandler.AddAccessProviders(
 			&sync_from_target.AccessProvider{
@@ -1582,6 +2158,103 @@ func (a *AccessProviderVisitor) addPermissionIfNotSetByRaito(apNamePrefix string
 	return nil
 }
 
+type inheritedPrivilegeKey struct {
+	source     data_source.DataObjectReference
+	sourceName string
+	privilege  catalog.Privilege
+}
+
+// syncEffectivePermissionsFromTarget imports the direct privileges of the securable as grants.
+// The privileges inherited from parent securables are collected in the inherited grants, which are imported as one non-internalizable grant per source securable and set of principals.
+func (a *AccessProviderVisitor) syncEffectivePermissionsFromTarget(ctx context.Context, workspaceClient dataAccessWorkspaceRepository, metastoreName, metastoreId, fullName string, doType string, securableType catalog.SecurableType) error {
+	effectivePermissions, err := checkpointedResponse(ctx, "effective-permissions/"+fullName, func() (*catalog.EffectivePermissionsList, error) {
+		return workspaceClient.GetEffectivePermissionsOnResource(ctx, securableType, fullName)
+	})
+	if err != nil {
+		return err
+	}
+
+	if effectivePermissions == nil {
+		return nil
+	}
+
+	apNamePrefix := createAccessProviderNamePrefix(metastoreName, fullName, doType, a.includeMetastoreInExternalAps)
+	do := &data_source.DataObjectReference{FullName: createUniqueId(metastoreId, fullName), Type: doType}
+
+	directPermissions := &catalog.PermissionsList{}
+	inheritedPrivilegeMap := make(map[inheritedPrivilegeKey][]string)
+
+	for _, assignment := range effectivePermissions.PrivilegeAssignments {
+		var directPrivileges []catalog.Privilege
+
+		for _, privilege := range assignment.Privileges {
+			if privilege.InheritedFromName == "" {
+				directPrivileges = append(directPrivileges, privilege.Privilege)
+
+				continue
+			}
+
+			sourceDo := effectivePermissionSource(metastoreId, privilege)
+			if a.syncer.privilegeCache.ContainsPrivilege(sourceDo, assignment.Principal, string(privilege.Privilege)) {
+				logger.Debug(fmt.Sprintf("Inherited privilege was assigned by Raito and will be ignored: %v, %s, %v", sourceDo, assignment.Principal, privilege.Privilege))
+
+				continue
+			}
+
+			key := inheritedPrivilegeKey{source: sourceDo, sourceName: privilege.InheritedFromName, privilege: privilege.Privilege}
+			inheritedPrivilegeMap[key] = append(inheritedPrivilegeMap[key], assignment.Principal)
+		}
+
+		if len(directPrivileges) > 0 {
+			directPermissions.PrivilegeAssignments = append(directPermissions.PrivilegeAssignments, catalog.PrivilegeAssignment{
+				Principal:  assignment.Principal,
+				Privileges: directPrivileges,
+			})
+		}
+	}
+
+	for key, principleList := range inheritedPrivilegeMap {
+		a.inheritedGrants.AddInherited(key.source, key.sourceName, *do, strings.ToUpper(strings.ReplaceAll(key.privilege.String(), "_", " ")), principleList)
+	}
+
+	return a.addPermissionIfNotSetByRaito(apNamePrefix, do, directPermissions)
+}
+
+// principalsToWhoItem converts the principals of privilege assignments to a WhoItem
+func (a *AccessProviderVisitor) principalsToWhoItem(principals []string) sync_from_target.WhoItem {
+	whoItems := sync_from_target.WhoItem{}
+
+	// The principal can be a user email address, a group name or a service principal ID (https://docs.databricks.com/api/workspace/grants/get#privilege_assignments)
+	for _, principal := range principals {
+		if a.servicePrincipals.Contains(principal) {
+			whoItems.Users = append(whoItems.Users, principal)
+		} else if a.groups.Contains(principal) {
+			whoItems.Groups = append(whoItems.Groups, principal)
+		} else if strings.Contains(principal, "@") {
+			whoItems.Users = append(whoItems.Users, principal)
+		} else {
+			logger.Warn(fmt.Sprintf("Unable to find to validate if %q is users, group or service principal", principal))
+		}
+	}
+
+	return whoItems
+}
+
+// effectivePermissionSource returns the data object from which the effective privilege is inherited.
+// Metastore data objects are identified by the id of the metastore.
+func effectivePermissionSource(metastoreId string, privilege catalog.EffectivePrivilege) data_source.DataObjectReference {
+	switch privilege.InheritedFromType {
+	case catalog.SecurableTypeMetastore:
+		return data_source.DataObjectReference{FullName: metastoreId, Type: constants.MetastoreType}
+	case catalog.SecurableTypeCatalog:
+		return data_source.DataObjectReference{FullName: createUniqueId(metastoreId, privilege.InheritedFromName), Type: constants.CatalogType}
+	case catalog.SecurableTypeSchema:
+		return data_source.DataObjectReference{FullName: createUniqueId(metastoreId, privilege.InheritedFromName), Type: data_source.Schema}
+	default:
+		return data_source.DataObjectReference{FullName: createUniqueId(metastoreId, privilege.InheritedFromName), Type: strings.ToLower(privilege.InheritedFromType.String())}
+	}
+}
+
 func createAccessProviderNamePrefix(metastoreId string, fullName string, doType string, includeMetastore bool) string {
 	objectName := fullName
 	if includeMetastore {
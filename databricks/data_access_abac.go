@@ -0,0 +1,513 @@
+package databricks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/util/config"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/masks"
+	"cli-plugin-databricks/databricks/repo"
+	types2 "cli-plugin-databricks/databricks/repo/types"
+	"cli-plugin-databricks/databricks/types"
+)
+
+const (
+	abacAllUsers              = "account users"
+	abacMaskColumnType        = "string"
+	abacMaskedColumnAlias     = "masked_column"
+	defaultAbacFunctionSchema = "default"
+)
+
+// abacScope is the catalog or schema on which an attribute based access control policy is defined
+type abacScope struct {
+	metastore   string
+	catalogName string
+	schemaName  string
+}
+
+func (s abacScope) securableType() string {
+	if s.schemaName == "" {
+		return string(catalog.SecurableTypeCatalog)
+	}
+
+	return string(catalog.SecurableTypeSchema)
+}
+
+func (s abacScope) securableName() string {
+	if s.schemaName == "" {
+		return s.catalogName
+	}
+
+	return s.catalogName + "." + s.schemaName
+}
+
+// functionSchema returns the schema in which the policy function is created
+func (s abacScope) functionSchema(configMap *config.ConfigMap) string {
+	if s.schemaName != "" {
+		return s.schemaName
+	}
+
+	return configMap.GetStringWithDefault(constants.DatabricksAbacFunctionSchema, defaultAbacFunctionSchema)
+}
+
+// isAbacAccessProvider returns true if the mask or filter targets catalogs or schemas instead of individual columns or tables.
+// Such access providers are exported as attribute based access control policies, applying on all tagged columns within the catalog or schema.
+func isAbacAccessProvider(ap *sync_to_target.AccessProvider) bool {
+	for _, whatItems := range [][]sync_to_target.WhatItem{ap.What, ap.DeleteWhat} {
+		for _, whatItem := range whatItems {
+			if whatItem.DataObject != nil && isAbacScopeType(whatItem.DataObject.Type) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isAbacScopeType(doType string) bool {
+	return doType == constants.CatalogType || doType == data_source.Schema
+}
+
+func abacScopes(whatItems []sync_to_target.WhatItem) ([]abacScope, error) {
+	scopes := make([]abacScope, 0, len(whatItems))
+
+	for _, whatItem := range whatItems {
+		if !isAbacScopeType(whatItem.DataObject.Type) {
+			return nil, fmt.Errorf("unsupported what item %q: tag based policies can only be applied on catalogs and schemas", whatItem.DataObject.FullName)
+		}
+
+		parts := strings.Split(whatItem.DataObject.FullName, ".")
+		scope := abacScope{metastore: parts[0], catalogName: parts[1]}
+
+		if len(parts) > 2 {
+			scope.schemaName = parts[2]
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
+
+// abacFunctionName returns the name of the policy function, only keeping the characters that are allowed in an unquoted function name (as the mask factory does)
+func abacFunctionName(policyName string, suffix string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+
+		return -1
+	}, policyName+"_"+suffix)
+}
+
+func abacExceptPrincipals(ap *sync_to_target.AccessProvider) []string {
+	principals := make([]string, 0, len(ap.Who.Users)+len(ap.Who.Groups))
+	principals = append(principals, ap.Who.Users...)
+	principals = append(principals, ap.Who.Groups...)
+
+	return principals
+}
+
+// syncAbacMaskToTarget creates a column mask policy on each catalog or schema in the what items of the mask.
+// The policy masks all string columns matching the tag condition in the policy rule of the mask, for everyone except the who items.
+func (a *AccessSyncer) syncAbacMaskToTarget(ctx context.Context, ap *sync_to_target.AccessProvider, configMap *config.ConfigMap, repoCache *MetastoreRepoCache) (policyName string, _ error) {
+	if ap.ExternalId != nil {
+		policyName = *ap.ExternalId
+	} else {
+		policyName = raitoPrefixName(ap.NamingHint)
+	}
+
+	return policyName, a.syncAbacPolicyToTarget(ctx, ap, policyName, configMap, repoCache, func(sqlClient repo.WarehouseRepository, scope abacScope, functionSchema string) (*types2.AbacPolicy, string, error) {
+		if ap.PolicyRule == nil || *ap.PolicyRule == "" {
+			return nil, "", errors.New("a tag condition is required in the policy rule of a tag based mask")
+		}
+
+		condition, err := parseAbacCondition(*ap.PolicyRule)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse tag condition of mask: %w", err)
+		}
+
+		// The policy function only accepts string columns, so the mask is refused if it would apply on other columns
+		err = validateAbacMaskColumnTypes(ctx, sqlClient, scope, condition)
+		if err != nil {
+			return nil, "", err
+		}
+
+		functionName, functionStatement, err := masks.NewMaskFactory().CreateMask(policyName, abacMaskColumnType, ap.Type, &masks.MaskingBeneficiaries{})
+		if err != nil {
+			return nil, "", err
+		}
+
+		return &types2.AbacPolicy{
+			Name:          policyName,
+			Kind:          types2.AbacColumnMask,
+			SecurableType: scope.securableType(),
+			SecurableName: scope.securableName(),
+			Comment:       ap.Description,
+			Function:      fmt.Sprintf("%s.%s.%s", scope.catalogName, functionSchema, functionName),
+			To:            []string{abacAllUsers},
+			Except:        abacExceptPrincipals(ap),
+			MatchColumns:  []types2.AbacMatchColumn{{Condition: condition, Alias: abacMaskedColumnAlias}},
+		}, string(functionStatement), nil
+	})
+}
+
+// syncAbacFilterToTarget creates a row filter policy on each catalog or schema in the what items of the filter.
+// Each column referenced in the filter is matched on the tag condition configured for the column, so the filter applies to all tables with such tagged columns.
+func (a *AccessSyncer) syncAbacFilterToTarget(ctx context.Context, ap *sync_to_target.AccessProvider, configMap *config.ConfigMap, repoCache *MetastoreRepoCache) (policyName string, _ error) {
+	if ap.ExternalId != nil {
+		policyName = *ap.ExternalId
+	} else {
+		policyName = raitoPrefixName(ap.NamingHint)
+	}
+
+	var columnTags map[string]string
+
+	if _, err := configMap.Unmarshal(constants.DatabricksAbacFilterColumnTags, &columnTags); err != nil {
+		return policyName, fmt.Errorf("unmarshal %s: %w", constants.DatabricksAbacFilterColumnTags, err)
+	}
+
+	return policyName, a.syncAbacPolicyToTarget(ctx, ap, policyName, configMap, repoCache, func(_ repo.WarehouseRepository, scope abacScope, functionSchema string) (*types2.AbacPolicy, string, error) {
+		filterExpressionParts, filterArguments, _, err := a.parseFilterAccessProvidersForDo(ctx, []*sync_to_target.AccessProvider{ap})
+		if err != nil {
+			return nil, "", err
+		}
+
+		if len(filterArguments) == 0 {
+			return nil, "", errors.New("a tag based filter must reference at least one column")
+		}
+
+		functionBody := "FALSE"
+		if len(filterExpressionParts) > 0 {
+			functionBody = strings.Join(filterExpressionParts, " OR ")
+		}
+
+		functionName := abacFunctionName(policyName, "filter")
+		arguments := filterArguments.Slice()
+		argumentsWithType := make([]string, 0, len(arguments))
+		matchColumns := make([]types2.AbacMatchColumn, 0, len(arguments))
+
+		for _, argument := range arguments {
+			tagCondition, found := columnTags[argument.Trimmed()]
+			if !found {
+				return nil, "", fmt.Errorf("no tag condition configured in %s for column %q", constants.DatabricksAbacFilterColumnTags, argument.Trimmed())
+			}
+
+			condition, err := parseAbacCondition(tagCondition)
+			if err != nil {
+				return nil, "", fmt.Errorf("parse tag condition of column %q: %w", argument.Trimmed(), err)
+			}
+
+			argumentsWithType = append(argumentsWithType, fmt.Sprintf("%s %s", argument.Trimmed(), abacMaskColumnType))
+			matchColumns = append(matchColumns, types2.AbacMatchColumn{Condition: condition, Alias: argument.Trimmed()})
+		}
+
+		return &types2.AbacPolicy{
+				Name:          policyName,
+				Kind:          types2.AbacRowFilter,
+				SecurableType: scope.securableType(),
+				SecurableName: scope.securableName(),
+				Comment:       ap.Description,
+				Function:      fmt.Sprintf("%s.%s.%s", scope.catalogName, functionSchema, functionName),
+				To:            []string{abacAllUsers},
+				MatchColumns:  matchColumns,
+			},
+			fmt.Sprintf("CREATE OR REPLACE FUNCTION %s(%s)\n RETURN %s;", functionName, strings.Join(argumentsWithType, ", "), functionBody),
+			nil
+	})
+}
+
+func (a *AccessSyncer) syncAbacPolicyToTarget(ctx context.Context, ap *sync_to_target.AccessProvider, policyName string, configMap *config.ConfigMap, repoCache *MetastoreRepoCache, policyFn func(sqlClient repo.WarehouseRepository, scope abacScope, functionSchema string) (*types2.AbacPolicy, string, error)) error {
+	var warehouseIdMap []types.WarehouseDetails
+
+	if found, err := configMap.Unmarshal(constants.DatabricksSqlWarehouses, &warehouseIdMap); err != nil {
+		return fmt.Errorf("unmarshal %s: %w", constants.DatabricksSqlWarehouses, err)
+	} else if !found {
+		return fmt.Errorf("no warehouses found in configmap")
+	}
+
+	scopes, err := abacScopes(ap.What)
+	if err != nil {
+		return err
+	}
+
+	deletedScopes, err := abacScopes(ap.DeleteWhat)
+	if err != nil {
+		return err
+	}
+
+	if ap.Delete {
+		deletedScopes = append(deletedScopes, scopes...)
+		scopes = nil
+	}
+
+	for _, scope := range deletedScopes {
+		err = a.deleteAbacPolicy(ctx, scope, policyName, configMap, warehouseIdMap, repoCache)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, scope := range scopes {
+		sqlClient, err := a.getAbacSqlClient(ctx, scope, warehouseIdMap, repoCache)
+		if err != nil {
+			return err
+		}
+
+		functionSchema := scope.functionSchema(configMap)
+
+		policy, functionStatement, err := policyFn(sqlClient, scope, functionSchema)
+		if err != nil {
+			return err
+		}
+
+		_, err = sqlClient.ExecuteStatement(ctx, scope.catalogName, functionSchema, functionStatement)
+		if err != nil {
+			return fmt.Errorf("create policy function in %s.%s: %w", scope.catalogName, functionSchema, err)
+		}
+
+		err = sqlClient.CreateOrReplacePolicy(ctx, scope.catalogName, policy)
+		if err != nil {
+			return fmt.Errorf("create policy %q on %s %q: %w", policyName, policy.SecurableType, policy.SecurableName, err)
+		}
+	}
+
+	return nil
+}
+
+func (a *AccessSyncer) deleteAbacPolicy(ctx context.Context, scope abacScope, policyName string, configMap *config.ConfigMap, warehouseIdMap []types.WarehouseDetails, repoCache *MetastoreRepoCache) error {
+	sqlClient, err := a.getAbacSqlClient(ctx, scope, warehouseIdMap, repoCache)
+	if err != nil {
+		return err
+	}
+
+	err = sqlClient.DropPolicy(ctx, scope.catalogName, policyName, scope.securableType(), scope.securableName())
+	if err != nil {
+		return fmt.Errorf("drop policy %q on %s %q: %w", policyName, scope.securableType(), scope.securableName(), err)
+	}
+
+	functionSchema := scope.functionSchema(configMap)
+
+	// The function name depends on the type of the policy
+	for _, functionName := range []string{abacFunctionName(policyName, "filter"), abacFunctionName(policyName, abacMaskColumnType)} {
+		err = sqlClient.DropFunction(ctx, scope.catalogName, functionSchema, functionName)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to drop policy function %s.%s.%s: %s", scope.catalogName, functionSchema, functionName, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func (a *AccessSyncer) getAbacSqlClient(ctx context.Context, scope abacScope, warehouseIdMap []types.WarehouseDetails, repoCache *MetastoreRepoCache) (repo.WarehouseRepository, error) {
+	_, sqlClient, err := a.getSqlClient(ctx, scope.metastore, scope.catalogName, warehouseIdMap, repoCache)
+	if err != nil {
+		return nil, fmt.Errorf("get sql client: %w", err)
+	}
+
+	if sqlClient == nil {
+		return nil, fmt.Errorf("no sql warehouse found for metastore %s", scope.metastore)
+	}
+
+	return sqlClient, nil
+}
+
+// validateAbacMaskColumnTypes returns an error if the tag condition matches columns within the scope that are not of the type of the mask function
+func validateAbacMaskColumnTypes(ctx context.Context, sqlClient repo.WarehouseRepository, scope abacScope, condition types2.AbacCondition) error {
+	columns, err := sqlClient.GetTaggedColumns(ctx, scope.catalogName, scope.schemaName)
+	if err != nil {
+		return err
+	}
+
+	var unsupported []string
+
+	for _, column := range columns {
+		if condition.Matches(column.Tags) && !strings.EqualFold(column.DataType, abacMaskColumnType) {
+			unsupported = append(unsupported, fmt.Sprintf("%s.%s (%s)", scope.catalogName, column.FullName, column.DataType))
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("tag based masks only support %s columns, but the tag condition matches %s", abacMaskColumnType, strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+type abacToken struct {
+	value    string
+	isString bool
+}
+
+// parseAbacCondition parses a tag condition of hasTag('key') and hasTagValue('key', 'value') terms, combined with AND and OR.
+// Only these terms are accepted, as the condition is embedded in the policy statement.
+func parseAbacCondition(input string) (types2.AbacCondition, error) {
+	tokens, err := tokenizeAbacCondition(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var condition types2.AbacCondition
+
+	conjunction := make([]types2.AbacTagCondition, 0, 1)
+
+	for len(tokens) > 0 {
+		var tagCondition types2.AbacTagCondition
+
+		tagCondition, tokens, err = parseAbacTagCondition(tokens)
+		if err != nil {
+			return nil, err
+		}
+
+		conjunction = append(conjunction, tagCondition)
+
+		if len(tokens) == 0 {
+			break
+		}
+
+		switch {
+		case !tokens[0].isString && strings.EqualFold(tokens[0].value, "AND"):
+		case !tokens[0].isString && strings.EqualFold(tokens[0].value, "OR"):
+			condition = append(condition, conjunction)
+			conjunction = make([]types2.AbacTagCondition, 0, 1)
+		default:
+			return nil, fmt.Errorf("expected AND or OR instead of %q", tokens[0].value)
+		}
+
+		tokens = tokens[1:]
+		if len(tokens) == 0 {
+			return nil, errors.New("unexpected end of tag condition")
+		}
+	}
+
+	if len(conjunction) == 0 {
+		return nil, errors.New("empty tag condition")
+	}
+
+	return append(condition, conjunction), nil
+}
+
+func parseAbacTagCondition(tokens []abacToken) (types2.AbacTagCondition, []abacToken, error) {
+	expect := func(value string) error {
+		if len(tokens) == 0 || tokens[0].isString || tokens[0].value != value {
+			return fmt.Errorf("expected %q in tag condition", value)
+		}
+
+		tokens = tokens[1:]
+
+		return nil
+	}
+
+	expectString := func() (string, error) {
+		if len(tokens) == 0 || !tokens[0].isString {
+			return "", errors.New("expected a quoted string in tag condition")
+		}
+
+		value := tokens[0].value
+		tokens = tokens[1:]
+
+		return value, nil
+	}
+
+	if len(tokens) == 0 || tokens[0].isString {
+		return types2.AbacTagCondition{}, nil, errors.New("expected hasTag or hasTagValue in tag condition")
+	}
+
+	function := tokens[0].value
+	tokens = tokens[1:]
+
+	if !strings.EqualFold(function, "hasTag") && !strings.EqualFold(function, "hasTagValue") {
+		return types2.AbacTagCondition{}, nil, fmt.Errorf("unsupported function %q in tag condition, expected hasTag or hasTagValue", function)
+	}
+
+	if err := expect("("); err != nil {
+		return types2.AbacTagCondition{}, nil, err
+	}
+
+	key, err := expectString()
+	if err != nil {
+		return types2.AbacTagCondition{}, nil, err
+	}
+
+	tagCondition := types2.AbacTagCondition{Key: key}
+
+	if strings.EqualFold(function, "hasTagValue") {
+		if err = expect(","); err != nil {
+			return types2.AbacTagCondition{}, nil, err
+		}
+
+		value, err := expectString()
+		if err != nil {
+			return types2.AbacTagCondition{}, nil, err
+		}
+
+		tagCondition.Value = &value
+	}
+
+	if err = expect(")"); err != nil {
+		return types2.AbacTagCondition{}, nil, err
+	}
+
+	return tagCondition, tokens, nil
+}
+
+func tokenizeAbacCondition(input string) ([]abacToken, error) {
+	var tokens []abacToken
+
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, abacToken{value: string(r)})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i+1 < len(runes) && (unicode.IsLetter(runes[i+1]) || unicode.IsDigit(runes[i+1]) || runes[i+1] == '_') {
+				i++
+			}
+
+			tokens = append(tokens, abacToken{value: string(runes[start : i+1])})
+		case r == '\'':
+			var value strings.Builder
+
+			closed := false
+
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					value.WriteRune(runes[i])
+				} else if runes[i] == '\'' && i+1 < len(runes) && runes[i+1] == '\'' {
+					i++
+					value.WriteRune('\'')
+				} else if runes[i] == '\'' {
+					closed = true
+
+					break
+				} else {
+					value.WriteRune(runes[i])
+				}
+			}
+
+			if !closed {
+				return nil, errors.New("unterminated string in tag condition")
+			}
+
+			tokens = append(tokens, abacToken{value: value.String(), isString: true})
+		default:
+			return nil, fmt.Errorf("unexpected character %q in tag condition", r)
+		}
+	}
+
+	return tokens, nil
+}
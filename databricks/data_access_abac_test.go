@@ -0,0 +1,100 @@
+package databricks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"cli-plugin-databricks/databricks/repo"
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+func Test_parseAbacCondition(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    types.AbacCondition
+		wantErr bool
+	}{
+		{
+			name:  "hasTag",
+			input: "hasTag('pii')",
+			want:  types.AbacCondition{{{Key: "pii"}}},
+		},
+		{
+			name:  "hasTagValue with escaped quote",
+			input: `hasTagValue('owner', 'raito''s team')`,
+			want:  types.AbacCondition{{{Key: "owner", Value: ptr.String("raito's team")}}},
+		},
+		{
+			name:  "AND binds stronger than OR",
+			input: "hasTag('pii') AND hasTagValue('class', 'email') or hasTag('secret')",
+			want: types.AbacCondition{
+				{{Key: "pii"}, {Key: "class", Value: ptr.String("email")}},
+				{{Key: "secret"}},
+			},
+		},
+		{
+			name:    "Injection",
+			input:   "hasTag('pii') AS masked_column ON COLUMN masked_column; DROP TABLE users; --",
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported function",
+			input:   "current_user() = 'admin'",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated string",
+			input:   "hasTag('pii)",
+			wantErr: true,
+		},
+		{
+			name:    "Trailing operator",
+			input:   "hasTag('pii') AND",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAbacCondition(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validateAbacMaskColumnTypes(t *testing.T) {
+	// Given
+	sqlRepo := repo.NewMockWarehouseRepository(t)
+	sqlRepo.EXPECT().GetTaggedColumns(mock.Anything, "catalog-1", "").Return([]types.TaggedColumn{
+		{FullName: "schema-1.users.email", DataType: "STRING", Tags: map[string]string{"pii": "email"}},
+		{FullName: "schema-1.users.id", DataType: "BIGINT", Tags: map[string]string{"pii": "id"}},
+	}, nil).Twice()
+
+	scope := abacScope{metastore: "metastore-id1", catalogName: "catalog-1"}
+
+	// When
+	err := validateAbacMaskColumnTypes(context.Background(), sqlRepo, scope, types.AbacCondition{{{Key: "pii", Value: ptr.String("email")}}})
+
+	// Then
+	require.NoError(t, err)
+
+	// When
+	err = validateAbacMaskColumnTypes(context.Background(), sqlRepo, scope, types.AbacCondition{{{Key: "pii"}}})
+
+	// Then
+	require.ErrorContains(t, err, "catalog-1.schema-1.users.id (BIGINT)")
+}
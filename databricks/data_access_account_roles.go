@@ -0,0 +1,597 @@
+package databricks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/databricks/databricks-sdk-go/service/iam"
+	"github.com/hashicorp/go-multierror"
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/access_provider/sync_from_target"
+	aptypes "github.com/raito-io/cli/base/access_provider/types"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/wrappers"
+	"github.com/raito-io/golang-set/set"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/platform"
+	types2 "cli-plugin-databricks/databricks/repo/types"
+	"cli-plugin-databricks/databricks/types"
+	"cli-plugin-databricks/databricks/utils"
+)
+
+const (
+	accountAdminPrivilege     = "ACCOUNT_ADMIN"
+	marketplaceAdminPrivilege = "MARKETPLACE_ADMIN"
+	metastoreAdminPrivilege   = "METASTORE_ADMIN"
+
+	accountAdminRole     = "account_admin"           // SCIM role of account admins
+	marketplaceAdminRole = "roles/marketplace.admin" // Role of marketplace admins in the account rule set
+
+	ruleSetUserPrefix             = "users/"
+	ruleSetGroupPrefix            = "groups/"
+	ruleSetServicePrincipalPrefix = "servicePrincipals/"
+)
+
+var errAccountRolesNotManaged = fmt.Errorf("account admin, marketplace admin and metastore admin are only managed if %s is set to true", constants.DatabricksManageAccountRoles)
+
+// syncAccountRolesFromTarget imports the account admins and marketplace admins as grants on the data source.
+// Account admins are the users, service principals and groups with the account_admin SCIM role, marketplace admins are taken from the account rule set.
+func (a *AccessSyncer) syncAccountRolesFromTarget(ctx context.Context, accessProviderHandler wrappers.AccessProviderHandler, accountRepo dataAccessAccountRepository, identities *accountIdentities, accountId string, manageAccountRoles bool) error {
+	do := data_source.DataObjectReference{FullName: accountId, Type: data_source.Datasource}
+
+	accountAdmins := sync_from_target.WhoItem{}
+
+	addAccountAdmin := func(principal string, roles []iam.ComplexValue, addToWho func(who *sync_from_target.WhoItem)) {
+		if !slices.ContainsFunc(roles, func(role iam.ComplexValue) bool { return role.Value == accountAdminRole }) {
+			return
+		}
+
+		if a.privilegeCache.ContainsPrivilege(do, principal, accountAdminPrivilege) {
+			return
+		}
+
+		addToWho(&accountAdmins)
+	}
+
+	for _, user := range identities.users {
+		addAccountAdmin(user.UserName, user.Roles, func(who *sync_from_target.WhoItem) { who.Users = append(who.Users, user.UserName) })
+	}
+
+	for _, servicePrincipal := range identities.servicePrincipals {
+		addAccountAdmin(servicePrincipal.ApplicationId, servicePrincipal.Roles, func(who *sync_from_target.WhoItem) {
+			who.Users = append(who.Users, servicePrincipal.ApplicationId)
+		})
+	}
+
+	for _, group := range identities.groups {
+		addAccountAdmin(group.DisplayName, group.Roles, func(who *sync_from_target.WhoItem) { who.Groups = append(who.Groups, group.DisplayName) })
+	}
+
+	ruleSet, err := accountRepo.GetAccountRuleSet(ctx)
+	if err != nil {
+		return fmt.Errorf("get account rule set: %w", err)
+	}
+
+	marketplaceAdmins := sync_from_target.WhoItem{}
+
+	for _, rule := range ruleSet.GrantRules {
+		if rule.Role != marketplaceAdminRole {
+			continue
+		}
+
+		for _, ruleSetPrincipal := range rule.Principals {
+			principal, isGroup, found := parseRuleSetPrincipal(ruleSetPrincipal)
+			if !found {
+				logger.Warn(fmt.Sprintf("Unknown principal %q in account rule set. Will ignore principal.", ruleSetPrincipal))
+
+				continue
+			}
+
+			if a.privilegeCache.ContainsPrivilege(do, principal, marketplaceAdminPrivilege) {
+				continue
+			}
+
+			if isGroup {
+				marketplaceAdmins.Groups = append(marketplaceAdmins.Groups, principal)
+			} else {
+				marketplaceAdmins.Users = append(marketplaceAdmins.Users, principal)
+			}
+		}
+	}
+
+	err = a.addAdminAccessProvider(accessProviderHandler, fmt.Sprintf("%s_%s", accountId, accountAdminPrivilege), fmt.Sprintf("Account %s", accountId), &do, accountAdminPrivilege, accountAdmins, manageAccountRoles)
+	if err != nil {
+		return err
+	}
+
+	return a.addAdminAccessProvider(accessProviderHandler, fmt.Sprintf("%s_%s", accountId, marketplaceAdminPrivilege), fmt.Sprintf("Account %s", accountId), &do, marketplaceAdminPrivilege, marketplaceAdmins, manageAccountRoles)
+}
+
+// addMetastoreAdmin imports the owner of the metastore as metastore admin.
+// The owner is a user, service principal or group, usually the group to which the metastore admin role is delegated.
+func (a *AccessProviderVisitor) addMetastoreAdmin(metastore *catalog.MetastoreInfo) error {
+	if metastore.Owner == "" {
+		return nil
+	}
+
+	do := data_source.DataObjectReference{FullName: metastore.MetastoreId, Type: constants.MetastoreType}
+
+	if a.syncer.privilegeCache.ContainsPrivilege(do, metastore.Owner, metastoreAdminPrivilege) {
+		return nil
+	}
+
+	who := sync_from_target.WhoItem{}
+
+	if a.groups.Contains(metastore.Owner) && !a.servicePrincipals.Contains(metastore.Owner) {
+		who.Groups = []string{metastore.Owner}
+	} else {
+		who.Users = []string{metastore.Owner}
+	}
+
+	return a.syncer.addAdminAccessProvider(a.accessProviderHandler, fmt.Sprintf("%s_%s", metastore.MetastoreId, metastoreAdminPrivilege), fmt.Sprintf("%s %s", TitleCaser.String(constants.MetastoreType), metastore.Name), &do, metastoreAdminPrivilege, who, a.manageAccountRoles)
+}
+
+// addAdminAccessProvider adds a grant for an admin role. Unless account roles are managed by Raito, the grant is not internalizable.
+func (a *AccessSyncer) addAdminAccessProvider(accessProviderHandler wrappers.AccessProviderHandler, externalId string, namePrefix string, do *data_source.DataObjectReference, privilege string, who sync_from_target.WhoItem, manageAccountRoles bool) error {
+	if len(who.Users) == 0 && len(who.Groups) == 0 {
+		return nil
+	}
+
+	humanReadablePrivilege := strings.ReplaceAll(privilege, "_", " ")
+	apName := fmt.Sprintf("%s - %s", namePrefix, humanReadablePrivilege)
+
+	return accessProviderHandler.AddAccessProviders(
+		&sync_from_target.AccessProvider{
+			ExternalId:        externalId,
+			Action:            aptypes.Grant,
+			Name:              apName,
+			NamingHint:        apName,
+			ActualName:        apName,
+			Type:              ptr.String(access_provider.AclSet),
+			NotInternalizable: !manageAccountRoles,
+			What: []sync_from_target.WhatItem{
+				{
+					DataObject:  do,
+					Permissions: []string{humanReadablePrivilege},
+				},
+			},
+			Who: &who,
+		},
+	)
+}
+
+// parseRuleSetPrincipal returns the user name, application id or group name of a principal in a rule set
+func parseRuleSetPrincipal(ruleSetPrincipal string) (string, bool, bool) {
+	if principal, found := strings.CutPrefix(ruleSetPrincipal, ruleSetGroupPrefix); found {
+		return principal, true, true
+	} else if principal, found = strings.CutPrefix(ruleSetPrincipal, ruleSetUserPrefix); found {
+		return principal, false, true
+	} else if principal, found = strings.CutPrefix(ruleSetPrincipal, ruleSetServicePrincipalPrefix); found {
+		return principal, false, true
+	}
+
+	return "", false, false
+}
+
+// accountRolePrincipal is a user, service principal or group of which the account roles can be updated
+type accountRolePrincipal struct {
+	ruleSetPrincipal string // users/<user name>, servicePrincipals/<application id> or groups/<group name>
+	updateRoles      func(ctx context.Context, add []string, remove []string) error
+}
+
+func (a *AccessSyncer) getAccountRolePrincipal(ctx context.Context, principal string, accountRepo dataAccessAccountRepository) (*accountRolePrincipal, error) {
+	if strings.Contains(principal, "@") {
+		user, err := a.getUserFromEmail(ctx, principal, accountRepo)
+		if err != nil {
+			return nil, err
+		}
+
+		return &accountRolePrincipal{
+			ruleSetPrincipal: ruleSetUserPrefix + user.UserName,
+			updateRoles: func(ctx context.Context, add []string, remove []string) error {
+				return accountRepo.UpdateUserRoles(ctx, user.Id, add, remove)
+			},
+		}, nil
+	}
+
+	group, err := a.findGroupByName(ctx, principal, accountRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	if group != nil {
+		return &accountRolePrincipal{
+			ruleSetPrincipal: ruleSetGroupPrefix + group.DisplayName,
+			updateRoles: func(ctx context.Context, add []string, remove []string) error {
+				return accountRepo.UpdateGroupRoles(ctx, group.Id, add, remove)
+			},
+		}, nil
+	}
+
+	servicePrincipal, err := a.getServicePrincipalFromApplicationId(ctx, principal, accountRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountRolePrincipal{
+		ruleSetPrincipal: ruleSetServicePrincipalPrefix + servicePrincipal.ApplicationId,
+		updateRoles: func(ctx context.Context, add []string, remove []string) error {
+			return accountRepo.UpdateServicePrincipalRoles(ctx, servicePrincipal.Id, add, remove)
+		},
+	}, nil
+}
+
+// storeAccountRoles grants and revokes the account admin and marketplace admin roles on the data source.
+// Account admins are updated through SCIM, marketplace admins through the account rule set.
+// pluginPrincipalFn returns the user name or application id the plugin authenticates with. It is only called if account admin is revoked.
+func (a *AccessSyncer) storeAccountRoles(ctx context.Context, principlePrivilegesMap map[string]*types.PrivilegesChanges, accountRepo dataAccessAccountRepository, manageAccountRoles bool, pluginPrincipalFn func() (string, error)) {
+	if !manageAccountRoles {
+		for _, privilegesChanges := range principlePrivilegesMap {
+			a.handleAccessProviderError(privilegesChanges, errAccountRolesNotManaged)
+		}
+
+		return
+	}
+
+	for _, principal := range a.refuseAccountAdminRevokes(ctx, principlePrivilegesMap, accountRepo, pluginPrincipalFn) {
+		privilegesChanges := principlePrivilegesMap[principal]
+		if len(privilegesChanges.Add) == 0 && len(privilegesChanges.Remove) == 0 {
+			delete(principlePrivilegesMap, principal)
+		}
+	}
+
+	principals := make([]string, 0, len(principlePrivilegesMap))
+	for principal := range principlePrivilegesMap {
+		principals = append(principals, principal)
+	}
+
+	slices.Sort(principals)
+
+	marketplaceAdd := set.NewSet[string]()
+	marketplaceRemove := set.NewSet[string]()
+
+	var marketplaceChanges []*types.PrivilegesChanges
+
+	for _, principal := range principals {
+		privilegesChanges := principlePrivilegesMap[principal]
+		privilegesChanges.Remove.RemoveAll(privilegesChanges.Add.Slice()...)
+
+		err := a.storeAccountRolesForPrincipal(ctx, principal, privilegesChanges, accountRepo, marketplaceAdd, marketplaceRemove)
+		if err != nil {
+			a.handleAccessProviderError(privilegesChanges, err)
+
+			continue
+		}
+
+		if privilegesChanges.Add.Contains(marketplaceAdminPrivilege) || privilegesChanges.Remove.Contains(marketplaceAdminPrivilege) {
+			marketplaceChanges = append(marketplaceChanges, privilegesChanges)
+		}
+	}
+
+	if len(marketplaceAdd) == 0 && len(marketplaceRemove) == 0 {
+		return
+	}
+
+	err := updateMarketplaceAdmins(ctx, accountRepo, marketplaceAdd, marketplaceRemove)
+	if err != nil {
+		for _, privilegesChanges := range marketplaceChanges {
+			a.handleAccessProviderError(privilegesChanges, err)
+		}
+	}
+}
+
+// accountAdmins contains the names of the principals with the account admin role
+type accountAdmins struct {
+	principals   set.Set[string] // User names, application ids and group names
+	pluginGroups set.Set[string] // Names of the admin groups of which the plugin principal is a direct member
+}
+
+func loadAccountAdmins(identities *accountIdentities, pluginPrincipal string) *accountAdmins {
+	admins := &accountAdmins{principals: set.NewSet[string](), pluginGroups: set.NewSet[string]()}
+
+	isAdmin := func(roles []iam.ComplexValue) bool {
+		return slices.ContainsFunc(roles, func(role iam.ComplexValue) bool { return role.Value == accountAdminRole })
+	}
+
+	var pluginId string
+
+	for _, user := range identities.users {
+		if isAdmin(user.Roles) {
+			admins.principals.Add(user.UserName)
+		}
+
+		if user.UserName == pluginPrincipal {
+			pluginId = user.Id
+		}
+	}
+
+	for _, servicePrincipal := range identities.servicePrincipals {
+		if isAdmin(servicePrincipal.Roles) {
+			admins.principals.Add(servicePrincipal.ApplicationId)
+		}
+
+		if servicePrincipal.ApplicationId == pluginPrincipal {
+			pluginId = servicePrincipal.Id
+		}
+	}
+
+	for _, group := range identities.groups {
+		if !isAdmin(group.Roles) {
+			continue
+		}
+
+		admins.principals.Add(group.DisplayName)
+
+		if pluginId != "" && slices.ContainsFunc(group.Members, func(member iam.ComplexValue) bool { return member.Value == pluginId }) {
+			admins.pluginGroups.Add(group.DisplayName)
+		}
+	}
+
+	return admins
+}
+
+// refuseAccountAdminRevokes refuses to revoke account admin from the principal the plugin authenticates with, from the admin groups it is a direct member of and from the last account admins.
+// Refused revokes are taken out of the privilege changes and reported on the access providers. The principals of which a revoke is refused are returned.
+// All revokes are refused if the plugin principal or the account admins can not be loaded.
+func (a *AccessSyncer) refuseAccountAdminRevokes(ctx context.Context, principlePrivilegesMap map[string]*types.PrivilegesChanges, accountRepo dataAccessAccountRepository, pluginPrincipalFn func() (string, error)) []string {
+	var revoked []string
+
+	for principal, privilegesChanges := range principlePrivilegesMap {
+		if privilegesChanges.Remove.Contains(accountAdminPrivilege) && !privilegesChanges.Add.Contains(accountAdminPrivilege) {
+			revoked = append(revoked, principal)
+		}
+	}
+
+	if len(revoked) == 0 {
+		return nil
+	}
+
+	slices.Sort(revoked)
+
+	var refused []string
+
+	refuse := func(principal string, err error) {
+		privilegesChanges := principlePrivilegesMap[principal]
+		privilegesChanges.Remove.Remove(accountAdminPrivilege)
+
+		a.handleAccessProviderError(privilegesChanges, err)
+
+		refused = append(refused, principal)
+	}
+
+	pluginPrincipal, err := pluginPrincipalFn()
+	if err != nil {
+		for _, principal := range revoked {
+			refuse(principal, fmt.Errorf("account admin can not be revoked from %q as the principal the plugin authenticates with is unknown: %w", principal, err))
+		}
+
+		return refused
+	}
+
+	identities, err := listAccountIdentities(ctx, accountRepo)
+	if err != nil {
+		for _, principal := range revoked {
+			refuse(principal, fmt.Errorf("load account admins: %w", err))
+		}
+
+		return refused
+	}
+
+	admins := loadAccountAdmins(identities, pluginPrincipal)
+
+	remaining := set.NewSet(admins.principals.Slice()...)
+
+	for principal, privilegesChanges := range principlePrivilegesMap {
+		if privilegesChanges.Add.Contains(accountAdminPrivilege) {
+			remaining.Add(principal)
+		}
+	}
+
+	var allowed []string
+
+	for _, principal := range revoked {
+		if principal == pluginPrincipal || admins.pluginGroups.Contains(principal) {
+			refuse(principal, fmt.Errorf("account admin can not be revoked from %q as the plugin authenticates with it", principal))
+
+			continue
+		}
+
+		remaining.Remove(principal)
+		allowed = append(allowed, principal)
+	}
+
+	if len(remaining) == 0 {
+		for _, principal := range allowed {
+			refuse(principal, fmt.Errorf("account admin can not be revoked from %q as no account admin would remain", principal))
+		}
+	}
+
+	return refused
+}
+
+func (a *AccessSyncer) storeAccountRolesForPrincipal(ctx context.Context, principal string, privilegesChanges *types.PrivilegesChanges, accountRepo dataAccessAccountRepository, marketplaceAdd set.Set[string], marketplaceRemove set.Set[string]) error {
+	for _, privilege := range slices.Concat(privilegesChanges.Add.Slice(), privilegesChanges.Remove.Slice()) {
+		if privilege != accountAdminPrivilege && privilege != marketplaceAdminPrivilege {
+			return fmt.Errorf("unsupported permission %q on data source", strings.ReplaceAll(privilege, "_", " "))
+		}
+	}
+
+	rolePrincipal, err := a.getAccountRolePrincipal(ctx, principal, accountRepo)
+	if err != nil {
+		return err
+	}
+
+	var rolesToAdd, rolesToRemove []string
+
+	if privilegesChanges.Add.Contains(accountAdminPrivilege) {
+		logger.Info(fmt.Sprintf("Grant account admin to %q", principal))
+
+		rolesToAdd = append(rolesToAdd, accountAdminRole)
+	} else if privilegesChanges.Remove.Contains(accountAdminPrivilege) {
+		logger.Info(fmt.Sprintf("Revoke account admin from %q", principal))
+
+		rolesToRemove = append(rolesToRemove, accountAdminRole)
+	}
+
+	err = rolePrincipal.updateRoles(ctx, rolesToAdd, rolesToRemove)
+	if err != nil {
+		return fmt.Errorf("update account roles of %q: %w", principal, err)
+	}
+
+	if privilegesChanges.Add.Contains(marketplaceAdminPrivilege) {
+		marketplaceAdd.Add(rolePrincipal.ruleSetPrincipal)
+	} else if privilegesChanges.Remove.Contains(marketplaceAdminPrivilege) {
+		marketplaceRemove.Add(rolePrincipal.ruleSetPrincipal)
+	}
+
+	return nil
+}
+
+// updateMarketplaceAdmins updates the principals of the marketplace admin rule in the account rule set. All other rules are kept as is.
+func updateMarketplaceAdmins(ctx context.Context, accountRepo dataAccessAccountRepository, add set.Set[string], remove set.Set[string]) error {
+	ruleSet, err := accountRepo.GetAccountRuleSet(ctx)
+	if err != nil {
+		return fmt.Errorf("get account rule set: %w", err)
+	}
+
+	principals := set.NewSet[string]()
+	grantRules := make([]iam.GrantRule, 0, len(ruleSet.GrantRules)+1)
+
+	for _, rule := range ruleSet.GrantRules {
+		if rule.Role == marketplaceAdminRole {
+			principals.Add(rule.Principals...)
+		} else {
+			grantRules = append(grantRules, rule)
+		}
+	}
+
+	for principal := range remove {
+		principals.Remove(principal)
+	}
+
+	principals.Add(add.Slice()...)
+
+	if len(principals) > 0 {
+		principalSlice := principals.Slice()
+		slices.Sort(principalSlice)
+
+		grantRules = append(grantRules, iam.GrantRule{Role: marketplaceAdminRole, Principals: principalSlice})
+	}
+
+	logger.Info(fmt.Sprintf("Update marketplace admins: add %v, remove %v", add.Slice(), remove.Slice()))
+
+	err = accountRepo.UpdateAccountRuleSet(ctx, ruleSet.Etag, grantRules)
+	if err != nil {
+		return fmt.Errorf("update account rule set: %w", err)
+	}
+
+	return nil
+}
+
+// resolvePluginPrincipal returns the user name or application id the plugin authenticates with.
+// The account API can not return the current principal, so it is resolved in the first workspace that is accessed with the account credentials.
+func (a *AccessSyncer) resolvePluginPrincipal(ctx context.Context, accountRepo dataAccessAccountRepository, repoCredentials types2.RepositoryCredentials, pltfrm platform.DatabricksPlatform) (string, error) {
+	workspaces, err := accountRepo.GetWorkspaces(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get workspaces: %w", err)
+	}
+
+	var resolveErr error
+
+	for i := range workspaces {
+		// Workspaces with their own credentials are accessed with another principal
+		if repoCredentials.HasWorkspaceCredentials(workspaces[i].WorkspaceId, workspaces[i].DeploymentName) {
+			continue
+		}
+
+		workspaceRepo, err := utils.InitWorkspaceRepo(ctx, repoCredentials, pltfrm, &workspaces[i], a.workspaceRepoFactory)
+		if err != nil {
+			resolveErr = multierror.Append(resolveErr, err)
+
+			continue
+		}
+
+		me, err := workspaceRepo.Me(ctx)
+		if err != nil {
+			resolveErr = multierror.Append(resolveErr, fmt.Errorf("current user in workspace %q: %w", workspaces[i].WorkspaceName, err))
+
+			continue
+		}
+
+		return me.UserName, nil
+	}
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	return "", errors.New("no workspace is accessed with the account credentials")
+}
+
+// storeMetastoreAdmin takes the METASTORE ADMIN privilege out of the privilege changes of a metastore and makes the principal owner of the metastore.
+// A metastore has exactly one owner, so the privilege can only be granted to one principal and is only revoked by granting it to another principal.
+func (a *AccessSyncer) storeMetastoreAdmin(ctx context.Context, item types.SecurableItemKey, principlePrivilegesMap map[string]*types.PrivilegesChanges, accountRepo dataAccessAccountRepository, manageAccountRoles bool) {
+	var newOwners, revokedOwners []string
+	var ownerChanges []*types.PrivilegesChanges
+
+	for principal, privilegesChanges := range principlePrivilegesMap {
+		added := privilegesChanges.Add.Contains(metastoreAdminPrivilege)
+		removed := privilegesChanges.Remove.Contains(metastoreAdminPrivilege)
+
+		if !added && !removed {
+			continue
+		}
+
+		privilegesChanges.Add.Remove(metastoreAdminPrivilege)
+		privilegesChanges.Remove.Remove(metastoreAdminPrivilege)
+
+		if added {
+			newOwners = append(newOwners, principal)
+		} else {
+			revokedOwners = append(revokedOwners, principal)
+		}
+
+		ownerChanges = append(ownerChanges, privilegesChanges)
+
+		if len(privilegesChanges.Add) == 0 && len(privilegesChanges.Remove) == 0 {
+			delete(principlePrivilegesMap, principal)
+		}
+	}
+
+	if len(ownerChanges) == 0 {
+		return
+	}
+
+	slices.Sort(newOwners)
+	slices.Sort(revokedOwners)
+
+	var err error
+
+	switch {
+	case !manageAccountRoles:
+		err = errAccountRolesNotManaged
+	case len(newOwners) == 0:
+		err = fmt.Errorf("metastore admin of metastore %q can not be revoked from %s without granting it to another principal", item.FullName, strings.Join(revokedOwners, ", "))
+	case len(newOwners) > 1:
+		err = fmt.Errorf("metastore admin of metastore %q can only be granted to one principal, got %s", item.FullName, strings.Join(newOwners, ", "))
+	default:
+		logger.Info(fmt.Sprintf("Make %q metastore admin of metastore %q", newOwners[0], item.FullName))
+
+		err = accountRepo.UpdateMetastoreOwner(ctx, item.FullName, newOwners[0])
+		if err != nil {
+			err = fmt.Errorf("update owner of metastore %q: %w", item.FullName, err)
+		}
+	}
+
+	if err != nil {
+		for _, privilegesChanges := range ownerChanges {
+			a.handleAccessProviderError(privilegesChanges, err)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package databricks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/access_provider/sync_from_target"
+	aptypes "github.com/raito-io/cli/base/access_provider/types"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/golang-set/set"
+
+	"cli-plugin-databricks/databricks/constants"
+)
+
+type grantGrouping string
+
+const (
+	grantGroupingNone         grantGrouping = "none"
+	grantGroupingPrincipalSet grantGrouping = "principal-set"
+	grantGroupingPrincipal    grantGrouping = "principal"
+	grantGroupingSchema       grantGrouping = "schema"
+
+	maxPrincipalsInGroupName = 3
+)
+
+func parseGrantGrouping(value string) (grantGrouping, error) {
+	switch grantGrouping(value) {
+	case "", grantGroupingNone:
+		return grantGroupingNone, nil
+	case grantGroupingPrincipalSet, grantGroupingPrincipal, grantGroupingSchema:
+		return grantGrouping(value), nil
+	default:
+		return "", fmt.Errorf("unsupported grant grouping %q, expected one of %q, %q, %q or %q", value, grantGroupingNone, grantGroupingPrincipalSet, grantGroupingPrincipal, grantGroupingSchema)
+	}
+}
+
+type groupedGrant struct {
+	externalId        string
+	name              string
+	principals        []string
+	what              map[data_source.DataObjectReference]set.Set[string]
+	notInternalizable bool
+}
+
+// grantGrouper collects imported grants and combines them into access providers according to the grouping strategy.
+// The external id of each group is derived from the group key, so re-imports result in the same access providers.
+type grantGrouper struct {
+	grouping grantGrouping
+	groups   map[string]*groupedGrant
+}
+
+func newGrantGrouper(grouping grantGrouping) *grantGrouper {
+	return &grantGrouper{
+		grouping: grouping,
+		groups:   make(map[string]*groupedGrant),
+	}
+}
+
+func (g *grantGrouper) Add(do data_source.DataObjectReference, permission string, principals []string) {
+	principals = slices.Clone(principals)
+	slices.Sort(principals)
+	principals = slices.Compact(principals)
+
+	switch g.grouping {
+	case grantGroupingPrincipal:
+		for _, principal := range principals {
+			g.add("principal_"+principal, fmt.Sprintf("Grants of %s", principal), []string{principal}, do, permission)
+		}
+	case grantGroupingSchema:
+		container := groupingContainer(do)
+		principalsHash := hashPrincipals(principals)
+
+		g.add(fmt.Sprintf("%s_%s", container.FullName, principalsHash), fmt.Sprintf("%s %s - %s", TitleCaser.String(container.Type), containerName(container), summarizePrincipals(principals)), principals, do, permission)
+	default:
+		g.add("principals_"+hashPrincipals(principals), fmt.Sprintf("Grants of %s", summarizePrincipals(principals)), principals, do, permission)
+	}
+}
+
+// AddInherited adds a privilege that is inherited from the source securable.
+// Inherited privileges are grouped per source securable and set of principals, independent of the grouping strategy, and can not be internalized.
+func (g *grantGrouper) AddInherited(source data_source.DataObjectReference, sourceName string, do data_source.DataObjectReference, permission string, principals []string) {
+	principals = slices.Clone(principals)
+	slices.Sort(principals)
+	principals = slices.Compact(principals)
+
+	group := g.add(fmt.Sprintf("%s_inherited_%s", source.FullName, hashPrincipals(principals)), fmt.Sprintf("Inherited from %s %s - %s", TitleCaser.String(source.Type), sourceName, summarizePrincipals(principals)), principals, do, permission)
+	group.notInternalizable = true
+}
+
+func (g *grantGrouper) add(externalId string, name string, principals []string, do data_source.DataObjectReference, permission string) *groupedGrant {
+	group, found := g.groups[externalId]
+	if !found {
+		group = &groupedGrant{
+			externalId: externalId,
+			name:       name,
+			principals: principals,
+			what:       make(map[data_source.DataObjectReference]set.Set[string]),
+		}
+
+		g.groups[externalId] = group
+	}
+
+	if _, found := group.what[do]; !found {
+		group.what[do] = set.NewSet[string]()
+	}
+
+	group.what[do].Add(permission)
+
+	return group
+}
+
+// AccessProviders returns the grouped access providers, sorted on external id
+func (g *grantGrouper) AccessProviders(principalsToWho func([]string) sync_from_target.WhoItem) []*sync_from_target.AccessProvider {
+	result := make([]*sync_from_target.AccessProvider, 0, len(g.groups))
+
+	for _, group := range g.groups {
+		whatItems := make([]sync_from_target.WhatItem, 0, len(group.what))
+
+		for do, permissions := range group.what {
+			permissionSlice := permissions.Slice()
+			slices.Sort(permissionSlice)
+
+			whatItems = append(whatItems, sync_from_target.WhatItem{
+				DataObject:  &data_source.DataObjectReference{FullName: do.FullName, Type: do.Type},
+				Permissions: permissionSlice,
+			})
+		}
+
+		slices.SortFunc(whatItems, func(a, b sync_from_target.WhatItem) int {
+			return strings.Compare(a.DataObject.FullName, b.DataObject.FullName)
+		})
+
+		who := principalsToWho(group.principals)
+
+		result = append(result, &sync_from_target.AccessProvider{
+			ExternalId:        group.externalId,
+			Action:            aptypes.Grant,
+			Name:              group.name,
+			NamingHint:        group.name,
+			ActualName:        group.name,
+			Type:              ptr.String(access_provider.AclSet),
+			NotInternalizable: group.notInternalizable,
+			What:              whatItems,
+			Who:               &who,
+		})
+	}
+
+	slices.SortFunc(result, func(a, b *sync_from_target.AccessProvider) int {
+		return strings.Compare(a.ExternalId, b.ExternalId)
+	})
+
+	return result
+}
+
+// groupingContainer returns the schema of the data object, or the data object itself if it is a schema or higher level object
+func groupingContainer(do data_source.DataObjectReference) data_source.DataObjectReference {
+	switch do.Type {
+	case data_source.Table, data_source.View, constants.FunctionType, constants.MaterializedViewType:
+		if schema, err := cutLastPartFullName(do.FullName); err == nil {
+			return data_source.DataObjectReference{FullName: schema, Type: data_source.Schema}
+		}
+	case data_source.Column:
+		if table, err := cutLastPartFullName(do.FullName); err == nil {
+			return groupingContainer(data_source.DataObjectReference{FullName: table, Type: data_source.Table})
+		}
+	}
+
+	return do
+}
+
+func containerName(container data_source.DataObjectReference) string {
+	if container.Type == constants.MetastoreType || !strings.Contains(container.FullName, ".") {
+		return container.FullName
+	}
+
+	_, fullName := getMetastoreAndFullnameOfUniqueId(container.FullName)
+
+	return fullName
+}
+
+func hashPrincipals(principals []string) string {
+	hash := sha256.Sum256([]byte(strings.Join(principals, "\n")))
+
+	return hex.EncodeToString(hash[:8])
+}
+
+func summarizePrincipals(principals []string) string {
+	if len(principals) <= maxPrincipalsInGroupName {
+		return strings.Join(principals, ", ")
+	}
+
+	return fmt.Sprintf("%s and %d more", strings.Join(principals[:maxPrincipalsInGroupName], ", "), len(principals)-maxPrincipalsInGroupName)
+}
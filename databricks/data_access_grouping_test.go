@@ -0,0 +1,167 @@
+package databricks
+
+import (
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/access_provider/sync_from_target"
+	types3 "github.com/raito-io/cli/base/access_provider/types"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantGrouper_AccessProviders(t *testing.T) {
+	table1 := data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.schema-1.table-1", Type: data_source.Table}
+	table2 := data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.schema-1.table-2", Type: data_source.Table}
+	schema2 := data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.schema-2", Type: data_source.Schema}
+
+	principalsToWho := func(principals []string) sync_from_target.WhoItem {
+		return sync_from_target.WhoItem{Users: principals}
+	}
+
+	grantsOf := func(grouper *grantGrouper) {
+		grouper.Add(table1, "SELECT", []string{"ruben@raito.io", "dieter@raito.io"})
+		grouper.Add(table1, "MODIFY", []string{"dieter@raito.io"})
+		grouper.Add(table2, "SELECT", []string{"dieter@raito.io", "ruben@raito.io"})
+		grouper.Add(schema2, "USE SCHEMA", []string{"dieter@raito.io", "ruben@raito.io"})
+	}
+
+	tests := []struct {
+		name     string
+		grouping grantGrouping
+		want     []*sync_from_target.AccessProvider
+	}{
+		{
+			name:     "principal set",
+			grouping: grantGroupingPrincipalSet,
+			want: []*sync_from_target.AccessProvider{
+				{
+					ExternalId: "principals_" + hashPrincipals([]string{"dieter@raito.io"}),
+					Name:       "Grants of dieter@raito.io",
+					NamingHint: "Grants of dieter@raito.io",
+					ActualName: "Grants of dieter@raito.io",
+					Action:     types3.Grant,
+					Type:       ptr.String(access_provider.AclSet),
+					Who:        &sync_from_target.WhoItem{Users: []string{"dieter@raito.io"}},
+					What: []sync_from_target.WhatItem{
+						{DataObject: &table1, Permissions: []string{"MODIFY"}},
+					},
+				},
+				{
+					ExternalId: "principals_" + hashPrincipals([]string{"dieter@raito.io", "ruben@raito.io"}),
+					Name:       "Grants of dieter@raito.io, ruben@raito.io",
+					NamingHint: "Grants of dieter@raito.io, ruben@raito.io",
+					ActualName: "Grants of dieter@raito.io, ruben@raito.io",
+					Action:     types3.Grant,
+					Type:       ptr.String(access_provider.AclSet),
+					Who:        &sync_from_target.WhoItem{Users: []string{"dieter@raito.io", "ruben@raito.io"}},
+					What: []sync_from_target.WhatItem{
+						{DataObject: &table1, Permissions: []string{"SELECT"}},
+						{DataObject: &table2, Permissions: []string{"SELECT"}},
+						{DataObject: &schema2, Permissions: []string{"USE SCHEMA"}},
+					},
+				},
+			},
+		},
+		{
+			name:     "principal",
+			grouping: grantGroupingPrincipal,
+			want: []*sync_from_target.AccessProvider{
+				{
+					ExternalId: "principal_dieter@raito.io",
+					Name:       "Grants of dieter@raito.io",
+					NamingHint: "Grants of dieter@raito.io",
+					ActualName: "Grants of dieter@raito.io",
+					Action:     types3.Grant,
+					Type:       ptr.String(access_provider.AclSet),
+					Who:        &sync_from_target.WhoItem{Users: []string{"dieter@raito.io"}},
+					What: []sync_from_target.WhatItem{
+						{DataObject: &table1, Permissions: []string{"MODIFY", "SELECT"}},
+						{DataObject: &table2, Permissions: []string{"SELECT"}},
+						{DataObject: &schema2, Permissions: []string{"USE SCHEMA"}},
+					},
+				},
+				{
+					ExternalId: "principal_ruben@raito.io",
+					Name:       "Grants of ruben@raito.io",
+					NamingHint: "Grants of ruben@raito.io",
+					ActualName: "Grants of ruben@raito.io",
+					Action:     types3.Grant,
+					Type:       ptr.String(access_provider.AclSet),
+					Who:        &sync_from_target.WhoItem{Users: []string{"ruben@raito.io"}},
+					What: []sync_from_target.WhatItem{
+						{DataObject: &table1, Permissions: []string{"SELECT"}},
+						{DataObject: &table2, Permissions: []string{"SELECT"}},
+						{DataObject: &schema2, Permissions: []string{"USE SCHEMA"}},
+					},
+				},
+			},
+		},
+		{
+			name:     "schema",
+			grouping: grantGroupingSchema,
+			want: []*sync_from_target.AccessProvider{
+				{
+					ExternalId: "metastore-id1.catalog-1.schema-1_" + hashPrincipals([]string{"dieter@raito.io"}),
+					Name:       "Schema catalog-1.schema-1 - dieter@raito.io",
+					NamingHint: "Schema catalog-1.schema-1 - dieter@raito.io",
+					ActualName: "Schema catalog-1.schema-1 - dieter@raito.io",
+					Action:     types3.Grant,
+					Type:       ptr.String(access_provider.AclSet),
+					Who:        &sync_from_target.WhoItem{Users: []string{"dieter@raito.io"}},
+					What: []sync_from_target.WhatItem{
+						{DataObject: &table1, Permissions: []string{"MODIFY"}},
+					},
+				},
+				{
+					ExternalId: "metastore-id1.catalog-1.schema-1_" + hashPrincipals([]string{"dieter@raito.io", "ruben@raito.io"}),
+					Name:       "Schema catalog-1.schema-1 - dieter@raito.io, ruben@raito.io",
+					NamingHint: "Schema catalog-1.schema-1 - dieter@raito.io, ruben@raito.io",
+					ActualName: "Schema catalog-1.schema-1 - dieter@raito.io, ruben@raito.io",
+					Action:     types3.Grant,
+					Type:       ptr.String(access_provider.AclSet),
+					Who:        &sync_from_target.WhoItem{Users: []string{"dieter@raito.io", "ruben@raito.io"}},
+					What: []sync_from_target.WhatItem{
+						{DataObject: &table1, Permissions: []string{"SELECT"}},
+						{DataObject: &table2, Permissions: []string{"SELECT"}},
+					},
+				},
+				{
+					ExternalId: "metastore-id1.catalog-1.schema-2_" + hashPrincipals([]string{"dieter@raito.io", "ruben@raito.io"}),
+					Name:       "Schema catalog-1.schema-2 - dieter@raito.io, ruben@raito.io",
+					NamingHint: "Schema catalog-1.schema-2 - dieter@raito.io, ruben@raito.io",
+					ActualName: "Schema catalog-1.schema-2 - dieter@raito.io, ruben@raito.io",
+					Action:     types3.Grant,
+					Type:       ptr.String(access_provider.AclSet),
+					Who:        &sync_from_target.WhoItem{Users: []string{"dieter@raito.io", "ruben@raito.io"}},
+					What: []sync_from_target.WhatItem{
+						{DataObject: &schema2, Permissions: []string{"USE SCHEMA"}},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grouper := newGrantGrouper(tt.grouping)
+			grantsOf(grouper)
+
+			assert.ElementsMatch(t, tt.want, grouper.AccessProviders(principalsToWho))
+		})
+	}
+}
+
+func Test_parseGrantGrouping(t *testing.T) {
+	grouping, err := parseGrantGrouping("")
+	require.NoError(t, err)
+	assert.Equal(t, grantGroupingNone, grouping)
+
+	grouping, err = parseGrantGrouping("schema")
+	require.NoError(t, err)
+	assert.Equal(t, grantGroupingSchema, grouping)
+
+	_, err = parseGrantGrouping("catalog")
+	require.Error(t, err)
+}
@@ -0,0 +1,455 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/databricks/databricks-sdk-go/service/iam"
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/access_provider/sync_from_target"
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	aptypes "github.com/raito-io/cli/base/access_provider/types"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/wrappers"
+	"github.com/raito-io/golang-set/set"
+
+	types2 "cli-plugin-databricks/databricks/repo/types"
+	"cli-plugin-databricks/databricks/types"
+	"cli-plugin-databricks/utils"
+)
+
+const (
+	inheritFromIdPrefix = "ID:"
+
+	// roleExternalIdPrefix prefixes the Raito role id that is stored as SCIM externalId of the account group backing the role
+	roleExternalIdPrefix = "raito-role:"
+)
+
+// roleState contains the account group backing a Raito role during sync to target
+type roleState struct {
+	ap        *sync_to_target.AccessProvider
+	groupName string
+	group     *iam.Group
+}
+
+func isRoleAccessProvider(ap *sync_to_target.AccessProvider) bool {
+	return ap.Type != nil && *ap.Type == access_provider.Role
+}
+
+// isRoleGroup returns true if the account group backs a Raito role, which is identified by the role id stored as externalId.
+// Other groups, even if their name starts with the Raito prefix, are not managed by Raito.
+func isRoleGroup(group *iam.Group) bool {
+	return strings.HasPrefix(group.ExternalId, roleExternalIdPrefix)
+}
+
+// roleGroupName returns the name of the account group backing the role
+func roleGroupName(ap *sync_to_target.AccessProvider) string {
+	if ap.ActualName != nil && *ap.ActualName != "" {
+		return *ap.ActualName
+	}
+
+	name := ap.NamingHint
+	if name == "" {
+		name = ap.Name
+	}
+
+	return raitoPrefixName(name)
+}
+
+func roleExternalId(roleId string) string {
+	return roleExternalIdPrefix + roleId
+}
+
+// syncRolesToTarget creates the account groups of all roles, updates their membership and collects the privileges of the roles.
+// The groups of deleted roles are returned (role id -> group id) as they can only be removed after all privileges are revoked.
+func (a *AccessSyncer) syncRolesToTarget(ctx context.Context, roles []*sync_to_target.AccessProvider, accountRepo dataAccessAccountRepository, permissionsChanges *types.PrivilegesChangeCollection) map[string]string {
+	groupsToDelete := make(map[string]string)
+	states := make([]*roleState, 0, len(roles))
+
+	// Group names must be known before inherited roles can be resolved
+	for _, role := range roles {
+		groupName := roleGroupName(role)
+		a.roleGroups[role.Id] = groupName
+
+		states = append(states, &roleState{ap: role, groupName: groupName})
+	}
+
+	cyclicRoles := roleNestingCycles(states, a.roleGroups)
+
+	// Ensure all groups exist before memberships are updated, as roles can be nested
+	for _, state := range states {
+		a.apFeedbackObjects[state.ap.Id] = sync_to_target.AccessProviderSyncFeedback{
+			AccessProvider: state.ap.Id,
+			ActualName:     state.groupName,
+			Type:           ptr.String(access_provider.Role),
+		}
+
+		if cyclicRoles.Contains(state.ap.Id) {
+			a.addRoleFeedbackError(state.ap.Id, fmt.Errorf("nesting of account group %q introduces a cycle", state.groupName))
+
+			continue
+		}
+
+		group, err := a.findGroupByName(ctx, state.groupName, accountRepo)
+		if err != nil {
+			a.addRoleFeedbackError(state.ap.Id, fmt.Errorf("find group %q: %w", state.groupName, err))
+
+			continue
+		}
+
+		if group == nil && !state.ap.Delete {
+			logger.Info(fmt.Sprintf("Creating account group %q for role %q", state.groupName, state.ap.Name))
+
+			group, err = accountRepo.CreateGroup(ctx, state.groupName)
+			if err != nil {
+				a.addRoleFeedbackError(state.ap.Id, fmt.Errorf("create group %q: %w", state.groupName, err))
+
+				continue
+			}
+		}
+
+		// The role id is kept on the group, so the role can be resolved when it is inherited in a later export
+		if group != nil && !state.ap.Delete && group.ExternalId != roleExternalId(state.ap.Id) {
+			err = accountRepo.UpdateGroupExternalId(ctx, group.Id, roleExternalId(state.ap.Id))
+			if err != nil {
+				a.addRoleFeedbackError(state.ap.Id, fmt.Errorf("update external id of group %q: %w", state.groupName, err))
+
+				continue
+			}
+		}
+
+		state.group = group
+	}
+
+	for _, state := range states {
+		feedbackElement := a.apFeedbackObjects[state.ap.Id]
+		if len(feedbackElement.Errors) > 0 || state.group == nil {
+			continue
+		}
+
+		feedbackElement.ExternalId = ptr.String(state.group.Id)
+
+		err := a.syncRoleToTarget(ctx, state, accountRepo, permissionsChanges)
+		if err != nil {
+			feedbackElement.Errors = append(feedbackElement.Errors, err.Error())
+		} else if state.ap.Delete {
+			groupsToDelete[state.ap.Id] = state.group.Id
+		} else {
+			feedbackElement.State = &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users:       state.ap.Who.Users,
+					Groups:      state.ap.Who.Groups,
+					InheritFrom: state.ap.Who.InheritFrom,
+				},
+			}
+		}
+
+		a.apFeedbackObjects[state.ap.Id] = feedbackElement
+	}
+
+	return groupsToDelete
+}
+
+// roleNestingCycles returns the ids of the roles of which the nesting of account groups introduces a cycle.
+// Each role group depends on the role groups of the export that are member of it, through its who groups or inherited roles.
+func roleNestingCycles(states []*roleState, roleGroups map[string]string) set.Set[string] {
+	exportedGroups := set.NewSet[string]()
+
+	for _, state := range states {
+		if !state.ap.Delete {
+			exportedGroups.Add(state.groupName)
+		}
+	}
+
+	cyclicRoles := set.NewSet[string]()
+	dependencyTree := utils.NewDependencyTree[string]()
+
+	for _, state := range states {
+		if state.ap.Delete {
+			continue
+		}
+
+		memberGroups := set.NewSet[string]()
+
+		for _, groupName := range state.ap.Who.Groups {
+			if exportedGroups.Contains(groupName) {
+				memberGroups.Add(groupName)
+			}
+		}
+
+		for _, inherited := range state.ap.Who.InheritFrom {
+			groupName := inherited
+
+			if apId, found := strings.CutPrefix(inherited, inheritFromIdPrefix); found {
+				groupName = roleGroups[apId]
+			}
+
+			if exportedGroups.Contains(groupName) {
+				memberGroups.Add(groupName)
+			}
+		}
+
+		if memberGroups.Contains(state.groupName) {
+			cyclicRoles.Add(state.ap.Id)
+
+			continue
+		}
+
+		dependsOn := memberGroups.Slice()
+		sort.Strings(dependsOn)
+
+		err := dependencyTree.AddDependency(state.groupName, dependsOn...)
+		if err != nil {
+			cyclicRoles.Add(state.ap.Id)
+		}
+	}
+
+	return cyclicRoles
+}
+
+func (a *AccessSyncer) syncRoleToTarget(ctx context.Context, state *roleState, accountRepo dataAccessAccountRepository, permissionsChanges *types.PrivilegesChangeCollection) error {
+	// All privileges of the role are granted to the account group instead of the individual members
+	grant := *state.ap
+	grant.Who = sync_to_target.WhoItem{Groups: []string{state.groupName}}
+	grant.DeletedWho = nil
+
+	if state.ap.Delete {
+		return a.syncGrantToTarget(ctx, &grant, accountRepo, permissionsChanges)
+	}
+
+	members, err := a.roleMemberIds(ctx, state.ap, accountRepo)
+	if err != nil {
+		return err
+	}
+
+	currentMembers := set.NewSet[string]()
+	for _, member := range state.group.Members {
+		currentMembers.Add(member.Value)
+	}
+
+	var toAdd []string
+
+	for member := range members {
+		if !currentMembers.Contains(member) {
+			toAdd = append(toAdd, member)
+		}
+	}
+
+	var toRemove []string
+
+	for member := range currentMembers {
+		if !members.Contains(member) {
+			toRemove = append(toRemove, member)
+		}
+	}
+
+	slices.Sort(toAdd)
+	slices.Sort(toRemove)
+
+	err = accountRepo.UpdateGroupMembers(ctx, state.group.Id, toAdd, toRemove)
+	if err != nil {
+		return fmt.Errorf("update members of group %q: %w", state.groupName, err)
+	}
+
+	return a.syncGrantToTarget(ctx, &grant, accountRepo, permissionsChanges)
+}
+
+// roleMemberIds returns the SCIM ids of all principals in the WHO of the role
+func (a *AccessSyncer) roleMemberIds(ctx context.Context, ap *sync_to_target.AccessProvider, accountRepo dataAccessAccountRepository) (set.Set[string], error) {
+	members := set.NewSet[string]()
+
+	for _, user := range ap.Who.Users {
+		// Service principals are referenced by their application id
+		if strings.Contains(user, "@") {
+			iamUser, err := a.getUserFromEmail(ctx, user, accountRepo)
+			if err != nil {
+				return nil, err
+			}
+
+			members.Add(iamUser.Id)
+		} else {
+			servicePrincipal, err := a.getServicePrincipalFromApplicationId(ctx, user, accountRepo)
+			if err != nil {
+				return nil, err
+			}
+
+			members.Add(servicePrincipal.Id)
+		}
+	}
+
+	inheritedGroups, err := a.resolveInheritedRoles(ctx, ap.Who.InheritFrom, accountRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, groupName := range append(slices.Clone(ap.Who.Groups), inheritedGroups...) {
+		group, err := a.getGroupIdFromName(ctx, groupName, accountRepo)
+		if err != nil {
+			return nil, err
+		}
+
+		members.Add(group.Id)
+	}
+
+	return members, nil
+}
+
+// resolveInheritedRoles converts the inherited access providers to the names of their account groups.
+// Roles that are not part of the current export are resolved through the role id stored on their account group.
+func (a *AccessSyncer) resolveInheritedRoles(ctx context.Context, inheritFrom []string, accountRepo dataAccessAccountRepository) ([]string, error) {
+	groupNames := make([]string, 0, len(inheritFrom))
+
+	for _, inherited := range inheritFrom {
+		apId, found := strings.CutPrefix(inherited, inheritFromIdPrefix)
+		if !found {
+			groupNames = append(groupNames, inherited)
+
+			continue
+		}
+
+		if groupName, ok := a.roleGroups[apId]; ok {
+			groupNames = append(groupNames, groupName)
+
+			continue
+		}
+
+		group, err := a.findGroupByRoleId(ctx, apId, accountRepo)
+		if err != nil {
+			return nil, err
+		}
+
+		if group == nil {
+			return nil, fmt.Errorf("unable to resolve inherited access provider %q: no account group found for the role", inherited)
+		}
+
+		a.roleGroups[apId] = group.DisplayName
+		groupNames = append(groupNames, group.DisplayName)
+	}
+
+	return groupNames, nil
+}
+
+// findGroupByRoleId returns the account group backing the role with the given id or nil if no such group exists
+func (a *AccessSyncer) findGroupByRoleId(ctx context.Context, roleId string, accountRepo dataAccessAccountRepository) (*iam.Group, error) {
+	cancelCtx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	externalId := roleExternalId(roleId)
+
+	for group := range accountRepo.ListGroups(cancelCtx, func(options *types2.DatabricksGroupsFilter) { options.ExternalId = &externalId }) {
+		if group.HasError() {
+			return nil, fmt.Errorf("list group item: %w", group.Error())
+		}
+
+		return group.I, nil
+	}
+
+	return nil, nil
+}
+
+func (a *AccessSyncer) deleteRoleGroups(ctx context.Context, groupsToDelete map[string]string, accountRepo dataAccessAccountRepository) {
+	for roleId, groupId := range groupsToDelete {
+		if len(a.apFeedbackObjects[roleId].Errors) > 0 {
+			logger.Warn(fmt.Sprintf("Account group %q of role %q is not deleted as not all privileges could be revoked", groupId, roleId))
+
+			continue
+		}
+
+		err := accountRepo.DeleteGroup(ctx, groupId)
+		if err != nil {
+			a.addRoleFeedbackError(roleId, fmt.Errorf("delete group %q: %w", groupId, err))
+		}
+	}
+}
+
+func (a *AccessSyncer) addRoleFeedbackError(roleId string, err error) {
+	fo := a.apFeedbackObjects[roleId]
+	fo.Errors = append(fo.Errors, err.Error())
+	a.apFeedbackObjects[roleId] = fo
+}
+
+// syncRolesFromTarget imports all Raito managed account groups as roles.
+// roleWhat contains the permissions per data object of each role group, as collected during the data object traversal.
+func (a *AccessSyncer) syncRolesFromTarget(accessProviderHandler wrappers.AccessProviderHandler, identities *accountIdentities, roleWhat map[string]map[data_source.DataObjectReference]set.Set[string]) error {
+	userNames := make(map[string]string, len(identities.users))
+	for _, user := range identities.users {
+		userNames[user.Id] = user.UserName
+	}
+
+	servicePrincipalNames := make(map[string]string, len(identities.servicePrincipals))
+	for _, servicePrincipal := range identities.servicePrincipals {
+		servicePrincipalNames[servicePrincipal.Id] = servicePrincipal.ApplicationId
+	}
+
+	groupNames := make(map[string]string, len(identities.groups))
+	roleGroupIds := set.NewSet[string]()
+
+	for _, group := range identities.groups {
+		groupNames[group.Id] = group.DisplayName
+
+		if isRoleGroup(group) {
+			roleGroupIds.Add(group.Id)
+		}
+	}
+
+	for _, group := range identities.groups {
+		if !roleGroupIds.Contains(group.Id) {
+			continue
+		}
+
+		who := sync_from_target.WhoItem{}
+
+		// SCIM ids are unique within the account, so the member type can be derived from the listed principals
+		for _, member := range group.Members {
+			if groupName, found := groupNames[member.Value]; found {
+				if roleGroupIds.Contains(member.Value) {
+					who.AccessProviders = append(who.AccessProviders, member.Value)
+				} else {
+					who.Groups = append(who.Groups, groupName)
+				}
+			} else if applicationId, found := servicePrincipalNames[member.Value]; found {
+				who.Users = append(who.Users, applicationId)
+			} else if userName, found := userNames[member.Value]; found {
+				who.Users = append(who.Users, userName)
+			} else {
+				logger.Warn(fmt.Sprintf("Unable to resolve member %q of group %q", member.Display, group.DisplayName))
+			}
+		}
+
+		whatItems := make([]sync_from_target.WhatItem, 0, len(roleWhat[group.DisplayName]))
+
+		for do, permissions := range roleWhat[group.DisplayName] {
+			permissionSlice := permissions.Slice()
+			slices.Sort(permissionSlice)
+
+			whatItems = append(whatItems, sync_from_target.WhatItem{
+				DataObject:  &data_source.DataObjectReference{FullName: do.FullName, Type: do.Type},
+				Permissions: permissionSlice,
+			})
+		}
+
+		sort.Slice(whatItems, func(i, j int) bool {
+			return whatItems[i].DataObject.FullName < whatItems[j].DataObject.FullName
+		})
+
+		err := accessProviderHandler.AddAccessProviders(&sync_from_target.AccessProvider{
+			ExternalId: group.Id,
+			Action:     aptypes.Grant,
+			Name:       group.DisplayName,
+			NamingHint: group.DisplayName,
+			ActualName: group.DisplayName,
+			Type:       ptr.String(access_provider.Role),
+			What:       whatItems,
+			Who:        &who,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
@@ -21,6 +23,7 @@ import (
 	"github.com/raito-io/cli/base/data_source"
 	"github.com/raito-io/cli/base/util/config"
 	"github.com/raito-io/cli/base/wrappers/mocks"
+	"github.com/raito-io/golang-set/set"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -53,6 +56,7 @@ func TestAccessSyncer_SyncAccessProvidersFromTarget(t *testing.T) {
 	metastore1 := catalog.MetastoreInfo{
 		Name:        "metastore1",
 		MetastoreId: "metastore-id1",
+		Owner:       "group1",
 	}
 
 	workspaceObject := provisioning.Workspace{
@@ -62,21 +66,52 @@ func TestAccessSyncer_SyncAccessProvidersFromTarget(t *testing.T) {
 		WorkspaceStatus: "RUNNING",
 	}
 
-	mockAccountRepo.EXPECT().ListGroups(mock.Anything).Return(repo.ArrayToChannel[iam.Group]([]iam.Group{
-		{
-			DisplayName: "group1",
-			ExternalId:  "group1",
-			Id:          "group1",
-		},
-	}))
-	mockAccountRepo.EXPECT().ListServicePrincipals(mock.Anything).Return(repo.ArrayToChannel[iam.ServicePrincipal]([]iam.ServicePrincipal{
-		{
-			ApplicationId: "5f239a72-c050-47b4-947c-f329f8e2e8f2",
-			DisplayName:   "Service Principal 1",
-			ExternalId:    "5f239a72-c050-47b4-947c-f329f8e2e8f2",
-			Id:            "5f239a72-c050-47b4-947c-f329f8e2e8f2",
+	mockAccountRepo.EXPECT().ListGroups(mock.Anything).RunAndReturn(func(ctx context.Context, _ ...func(*types2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
+		return repo.ArrayToChannel[iam.Group]([]iam.Group{
+			{
+				DisplayName: "group1",
+				ExternalId:  "group1",
+				Id:          "group1",
+			},
+			{
+				DisplayName: "raito_analysts",
+				ExternalId:  "raito-role:role-analysts-id",
+				Id:          "8461",
+				Members:     []iam.ComplexValue{{Value: "group1"}, {Value: "314"}, {Value: "5f239a72-c050-47b4-947c-f329f8e2e8f2"}, {Value: "9272"}},
+			},
+			{
+				// Not created by Raito, so imported as a regular group
+				DisplayName: "raito_legacy",
+				Id:          "9272",
+			},
+		})
+	})
+	mockAccountRepo.EXPECT().ListUsers(mock.Anything).RunAndReturn(func(ctx context.Context, _ ...func(*types2.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User] {
+		return repo.ArrayToChannel[iam.User]([]iam.User{
+			{
+				UserName: "ruben@raito.io",
+				Id:       "314",
+				Roles:    []iam.ComplexValue{{Value: "account_admin"}},
+			},
+		})
+	}).Once()
+	mockAccountRepo.EXPECT().ListServicePrincipals(mock.Anything).RunAndReturn(func(ctx context.Context, _ ...func(*types2.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal] {
+		return repo.ArrayToChannel[iam.ServicePrincipal]([]iam.ServicePrincipal{
+			{
+				ApplicationId: "5f239a72-c050-47b4-947c-f329f8e2e8f2",
+				DisplayName:   "Service Principal 1",
+				ExternalId:    "5f239a72-c050-47b4-947c-f329f8e2e8f2",
+				Id:            "5f239a72-c050-47b4-947c-f329f8e2e8f2",
+				Roles:         []iam.ComplexValue{{Value: "account_admin"}},
+			},
+		})
+	})
+	mockAccountRepo.EXPECT().GetAccountRuleSet(mock.Anything).Return(&iam.RuleSetResponse{
+		GrantRules: []iam.GrantRule{
+			{Role: "roles/marketplace.admin", Principals: []string{"users/dieter@raito.io", "groups/group1"}},
+			{Role: "roles/servicePrincipal.user", Principals: []string{"users/ruben@raito.io"}},
 		},
-	}))
+	}, nil).Once()
 	mockAccountRepo.EXPECT().ListMetastores(mock.Anything).Return([]catalog.MetastoreInfo{metastore1}, nil).Once()
 	mockAccountRepo.EXPECT().GetWorkspaces(mock.Anything).Return([]provisioning.Workspace{workspaceObject}, nil).Once()
 	mockAccountRepo.EXPECT().GetWorkspaceMap(mock.Anything, []catalog.MetastoreInfo{metastore1}, []provisioning.Workspace{workspaceObject}).Return(map[string][]*provisioning.Workspace{metastore1.MetastoreId: {{DeploymentName: deployment}}}, nil, nil).Twice()
@@ -99,6 +134,28 @@ func TestAccessSyncer_SyncAccessProvidersFromTarget(t *testing.T) {
 		},
 	}, nil).Once()
 
+	mockWorkspaceRepoMap[deployment].EXPECT().ListUsers(mock.Anything).Return(repo.ArrayToChannel([]iam.User{
+		{
+			UserName:     "ruben@raito.io",
+			Entitlements: []iam.ComplexValue{{Value: "databricks-sql-access"}, {Value: "unknown-entitlement"}},
+		},
+		{
+			UserName: "dieter@raito.io",
+		},
+	})).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().ListServicePrincipals(mock.Anything).Return(repo.ArrayToChannel([]iam.ServicePrincipal{
+		{
+			ApplicationId: "5f239a72-c050-47b4-947c-f329f8e2e8f2",
+			Entitlements:  []iam.ComplexValue{{Value: "databricks-sql-access"}},
+		},
+	})).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().ListGroups(mock.Anything).Return(repo.ArrayToChannel([]iam.Group{
+		{
+			DisplayName:  "group1",
+			Entitlements: []iam.ComplexValue{{Value: "workspace-access"}, {Value: "allow-cluster-create"}},
+		},
+	})).Once()
+
 	mockWorkspaceRepoMap[deployment].EXPECT().Ping(mock.Anything).Return(nil).Once()
 	mockWorkspaceRepoMap[deployment].EXPECT().GetPermissionsOnResource(mock.Anything, catalog.SecurableTypeMetastore, "metastore-id1").Return(nil, nil).Once()
 
@@ -121,6 +178,14 @@ func TestAccessSyncer_SyncAccessProvidersFromTarget(t *testing.T) {
 					Principal:  "group1",
 					Privileges: []catalog.Privilege{catalog.PrivilegeUseCatalog, catalog.PrivilegeSelect},
 				},
+				{
+					Principal:  "raito_analysts",
+					Privileges: []catalog.Privilege{catalog.PrivilegeUseCatalog, catalog.PrivilegeSelect},
+				},
+				{
+					Principal:  "raito_legacy",
+					Privileges: []catalog.Privilege{catalog.PrivilegeUseCatalog},
+				},
 			},
 		}, nil).Once()
 
@@ -215,6 +280,27 @@ func TestAccessSyncer_SyncAccessProvidersFromTarget(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.ElementsMatch(t, accessProviderHandlerMock.AccessProviders, []sync_from_target.AccessProvider{
+		{
+			ExternalId: "8461",
+			Name:       "raito_analysts",
+			NamingHint: "raito_analysts",
+			ActualName: "raito_analysts",
+			Action:     types3.Grant,
+			Type:       ptr.String(access_provider.Role),
+			Who: &sync_from_target.WhoItem{
+				Users:  []string{"ruben@raito.io", "5f239a72-c050-47b4-947c-f329f8e2e8f2"},
+				Groups: []string{"group1", "raito_legacy"},
+			},
+			What: []sync_from_target.WhatItem{
+				{
+					DataObject: &data_source.DataObjectReference{
+						FullName: "metastore-id1.catalog-1",
+						Type:     constants.CatalogType,
+					},
+					Permissions: []string{"SELECT", "USE CATALOG"},
+				},
+			},
+		},
 		{
 			ExternalId: "test-workspace_USER",
 			Name:       "Workspace test-workspace - USER",
@@ -255,6 +341,66 @@ func TestAccessSyncer_SyncAccessProvidersFromTarget(t *testing.T) {
 				},
 			},
 		},
+		{
+			ExternalId: "test-workspace_DATABRICKS_SQL_ACCESS",
+			Name:       "Workspace test-workspace - DATABRICKS SQL ACCESS",
+			NamingHint: "Workspace test-workspace - DATABRICKS SQL ACCESS",
+			ActualName: "Workspace test-workspace - DATABRICKS SQL ACCESS",
+			Action:     types3.Grant,
+			Type:       ptr.String(access_provider.AclSet),
+			Who: &sync_from_target.WhoItem{
+				Users: []string{"ruben@raito.io", "5f239a72-c050-47b4-947c-f329f8e2e8f2"},
+			},
+			What: []sync_from_target.WhatItem{
+				{
+					DataObject: &data_source.DataObjectReference{
+						FullName: "42",
+						Type:     constants.WorkspaceType,
+					},
+					Permissions: []string{"DATABRICKS SQL ACCESS"},
+				},
+			},
+		},
+		{
+			ExternalId: "test-workspace_WORKSPACE_ACCESS",
+			Name:       "Workspace test-workspace - WORKSPACE ACCESS",
+			NamingHint: "Workspace test-workspace - WORKSPACE ACCESS",
+			ActualName: "Workspace test-workspace - WORKSPACE ACCESS",
+			Action:     types3.Grant,
+			Type:       ptr.String(access_provider.AclSet),
+			Who: &sync_from_target.WhoItem{
+				Groups: []string{"group1"},
+			},
+			What: []sync_from_target.WhatItem{
+				{
+					DataObject: &data_source.DataObjectReference{
+						FullName: "42",
+						Type:     constants.WorkspaceType,
+					},
+					Permissions: []string{"WORKSPACE ACCESS"},
+				},
+			},
+		},
+		{
+			ExternalId: "test-workspace_ALLOW_CLUSTER_CREATE",
+			Name:       "Workspace test-workspace - ALLOW CLUSTER CREATE",
+			NamingHint: "Workspace test-workspace - ALLOW CLUSTER CREATE",
+			ActualName: "Workspace test-workspace - ALLOW CLUSTER CREATE",
+			Action:     types3.Grant,
+			Type:       ptr.String(access_provider.AclSet),
+			Who: &sync_from_target.WhoItem{
+				Groups: []string{"group1"},
+			},
+			What: []sync_from_target.WhatItem{
+				{
+					DataObject: &data_source.DataObjectReference{
+						FullName: "42",
+						Type:     constants.WorkspaceType,
+					},
+					Permissions: []string{"ALLOW CLUSTER CREATE"},
+				},
+			},
+		},
 		{
 			ExternalId: "metastore-id1.catalog-1_SELECT",
 			Name:       "Catalog catalog-1 - SELECT",
@@ -284,7 +430,7 @@ func TestAccessSyncer_SyncAccessProvidersFromTarget(t *testing.T) {
 			Type:       ptr.String(access_provider.AclSet),
 			Who: &sync_from_target.WhoItem{
 				Users:  []string{"ruben@raito.io"},
-				Groups: []string{"group1"},
+				Groups: []string{"group1", "raito_legacy"},
 			},
 			What: []sync_from_target.WhatItem{
 				{
@@ -429,6 +575,64 @@ func TestAccessSyncer_SyncAccessProvidersFromTarget(t *testing.T) {
 			},
 			Incomplete: ptr.Bool(true),
 		},
+		{
+			ExternalId:        "metastore-id1_METASTORE_ADMIN",
+			Name:              "Metastore metastore1 - METASTORE ADMIN",
+			NamingHint:        "Metastore metastore1 - METASTORE ADMIN",
+			ActualName:        "Metastore metastore1 - METASTORE ADMIN",
+			Action:            types3.Grant,
+			Type:              ptr.String(access_provider.AclSet),
+			NotInternalizable: true,
+			Who: &sync_from_target.WhoItem{
+				Groups: []string{"group1"},
+			},
+			What: []sync_from_target.WhatItem{{
+				DataObject: &data_source.DataObjectReference{
+					FullName: "metastore-id1",
+					Type:     constants.MetastoreType,
+				},
+				Permissions: []string{"METASTORE ADMIN"},
+			}},
+		},
+		{
+			ExternalId:        "AccountId_ACCOUNT_ADMIN",
+			Name:              "Account AccountId - ACCOUNT ADMIN",
+			NamingHint:        "Account AccountId - ACCOUNT ADMIN",
+			ActualName:        "Account AccountId - ACCOUNT ADMIN",
+			Action:            types3.Grant,
+			Type:              ptr.String(access_provider.AclSet),
+			NotInternalizable: true,
+			Who: &sync_from_target.WhoItem{
+				Users: []string{"ruben@raito.io", "5f239a72-c050-47b4-947c-f329f8e2e8f2"},
+			},
+			What: []sync_from_target.WhatItem{{
+				DataObject: &data_source.DataObjectReference{
+					FullName: "AccountId",
+					Type:     data_source.Datasource,
+				},
+				Permissions: []string{"ACCOUNT ADMIN"},
+			}},
+		},
+		{
+			ExternalId:        "AccountId_MARKETPLACE_ADMIN",
+			Name:              "Account AccountId - MARKETPLACE ADMIN",
+			NamingHint:        "Account AccountId - MARKETPLACE ADMIN",
+			ActualName:        "Account AccountId - MARKETPLACE ADMIN",
+			Action:            types3.Grant,
+			Type:              ptr.String(access_provider.AclSet),
+			NotInternalizable: true,
+			Who: &sync_from_target.WhoItem{
+				Users:  []string{"dieter@raito.io"},
+				Groups: []string{"group1"},
+			},
+			What: []sync_from_target.WhatItem{{
+				DataObject: &data_source.DataObjectReference{
+					FullName: "AccountId",
+					Type:     data_source.Datasource,
+				},
+				Permissions: []string{"MARKETPLACE ADMIN"},
+			}},
+		},
 	})
 }
 
@@ -602,42 +806,985 @@ func TestAccessSyncer_SyncAccessProviderToTarget(t *testing.T) {
 			fn(&options)
 		}
 
-		require.NotNil(t, options.Username)
+		require.NotNil(t, options.Username)
+
+		if *options.Username == "ruben@raito.io" {
+			return repo.ArrayToChannel([]iam.User{
+				{
+					DisplayName: "Ruben Mennes",
+					Id:          "314",
+				},
+			})
+		} else if *options.Username == "dieter@raito.io" {
+			return repo.ArrayToChannel([]iam.User{
+				{
+					DisplayName: "Dieter Wachters",
+					Id:          "1592",
+				},
+			})
+		} else {
+			assert.Fail(t, "unexpected username")
+		}
+
+		return repo.ArrayToChannel[iam.User]([]iam.User{})
+	})
+	mockAccountRepo.EXPECT().ListGroups(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, f ...func(filter *types2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
+		options := types2.DatabricksGroupsFilter{}
+		for _, fn := range f {
+			fn(&options)
+		}
+
+		require.NotNil(t, options.Groupname)
+		require.Equal(t, "group1", *options.Groupname)
+
+		return repo.ArrayToChannel([]iam.Group{{DisplayName: "group1", Id: "6535"}})
+	})
+	mockAccountRepo.EXPECT().UpdateWorkspaceAssignment(mock.Anything, int64(42), int64(314), []iam.WorkspacePermission{iam.WorkspacePermissionUser}).Return(nil).Once()
+	mockAccountRepo.EXPECT().UpdateWorkspaceAssignment(mock.Anything, int64(42), int64(6535), []iam.WorkspacePermission{iam.WorkspacePermissionUser}).Return(nil).Once()
+	mockAccountRepo.EXPECT().UpdateWorkspaceAssignment(mock.Anything, int64(42), int64(1592), []iam.WorkspacePermission{}).Return(nil).Once()
+
+	// When
+	err := accessSyncer.SyncAccessProviderToTarget(context.Background(), &accessProviders, accessProviderHandlerMock, configMap)
+
+	// Then
+	require.NoError(t, err)
+
+	assert.Len(t, accessProviderHandlerMock.AccessProviderFeedback, 3)
+	assert.ElementsMatch(t, []sync_to_target.AccessProviderSyncFeedback{
+		{
+			AccessProvider: "workspace-ap-id",
+			ActualName:     "workspace-ap-id",
+			Type:           ptr.String(access_provider.AclSet),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users:  []string{"ruben@raito.io"},
+					Groups: []string{"group1"},
+				},
+			},
+		},
+		{
+			AccessProvider: "catalog-ap-id",
+			ActualName:     "catalog-ap-id",
+			Type:           ptr.String(access_provider.AclSet),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users: []string{"wannes@raito.io"},
+				},
+			},
+		},
+		{
+			AccessProvider: "multiple-do-ap-id",
+			ActualName:     "multiple-do-ap-id",
+			Type:           ptr.String(access_provider.AclSet),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users: []string{"bart@raito.io"},
+				},
+			},
+		},
+	}, accessProviderHandlerMock.AccessProviderFeedback)
+}
+
+func TestAccessSyncer_SyncAccessProviderToTarget_withEntitlements(t *testing.T) {
+	// Given
+	deployment := "test-deployment"
+	workspace := "test-workspace"
+	accessSyncer, mockAccountRepo, mockWorkspaceRepoMap := createAccessSyncer(t, deployment)
+
+	accessProviderHandlerMock := mocks.NewSimpleAccessProviderFeedbackHandler(t)
+
+	accessProviders := sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{
+			{
+				Id:     "workspace-ap-id",
+				Name:   "workspace-ap",
+				Action: types3.Grant,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "42",
+							Type:     constants.WorkspaceType,
+						},
+						Permissions: []string{"USER", "DATABRICKS SQL ACCESS"},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Users: []string{"ruben@raito.io"},
+				},
+			},
+			{
+				Id:     "workspace-entitlement-ap-id",
+				Name:   "workspace-entitlement-ap",
+				Action: types3.Grant,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "42",
+							Type:     constants.WorkspaceType,
+						},
+						Permissions: []string{"WORKSPACE ACCESS"},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Groups: []string{"group1"},
+				},
+				DeletedWho: &sync_to_target.WhoItem{
+					Groups: []string{"group2"},
+				},
+			},
+			{
+				Id:     "workspace-sp-entitlement-ap-id",
+				Name:   "workspace-sp-entitlement-ap",
+				Action: types3.Grant,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "42",
+							Type:     constants.WorkspaceType,
+						},
+						Permissions: []string{"ALLOW CLUSTER CREATE"},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Users: []string{"app-1"},
+				},
+			},
+		},
+	}
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId: "AccountId",
+			constants.DatabricksUser:      "User",
+			constants.DatabricksPassword:  "Password",
+			constants.DatabricksPlatform:  "AWS",
+		},
+	}
+
+	workspaceObject := provisioning.Workspace{
+		WorkspaceId:     42,
+		DeploymentName:  deployment,
+		WorkspaceName:   workspace,
+		WorkspaceStatus: "RUNNING",
+	}
+
+	mockAccountRepo.EXPECT().ListMetastores(mock.Anything).Return([]catalog.MetastoreInfo{}, nil).Once()
+	mockAccountRepo.EXPECT().GetWorkspaces(mock.Anything).Return([]provisioning.Workspace{workspaceObject}, nil).Once()
+
+	mockAccountRepo.EXPECT().ListUsers(mock.Anything, mock.Anything).Return(repo.ArrayToChannel([]iam.User{{UserName: "ruben@raito.io", Id: "314"}}))
+	mockAccountRepo.EXPECT().ListGroups(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, f ...func(filter *types2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
+		options := types2.DatabricksGroupsFilter{}
+		for _, fn := range f {
+			fn(&options)
+		}
+
+		require.NotNil(t, options.Groupname)
+
+		switch *options.Groupname {
+		case "group1":
+			return repo.ArrayToChannel([]iam.Group{{DisplayName: "group1", Id: "6535"}})
+		case "group2":
+			return repo.ArrayToChannel([]iam.Group{{DisplayName: "group2", Id: "8979"}})
+		}
+
+		return repo.ArrayToChannel([]iam.Group{})
+	})
+	mockAccountRepo.EXPECT().ListServicePrincipals(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, f ...func(filter *types2.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal] {
+		return repo.ArrayToChannel([]iam.ServicePrincipal{{ApplicationId: "app-1", Id: "2718"}})
+	})
+
+	mockAccountRepo.EXPECT().UpdateWorkspaceAssignment(mock.Anything, int64(42), int64(314), []iam.WorkspacePermission{iam.WorkspacePermissionUser}).Return(nil).Once()
+
+	mockWorkspaceRepoMap[deployment].EXPECT().Ping(mock.Anything).Return(nil)
+	mockWorkspaceRepoMap[deployment].EXPECT().UpdateUserEntitlements(mock.Anything, "314", []string{"databricks-sql-access"}, []string(nil)).Return(nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().UpdateGroupEntitlements(mock.Anything, "6535", []string{"workspace-access"}, []string(nil)).Return(nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().UpdateGroupEntitlements(mock.Anything, "8979", []string(nil), []string{"workspace-access"}).Return(nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().UpdateServicePrincipalEntitlements(mock.Anything, "2718", []string{"allow-cluster-create"}, []string(nil)).Return(nil).Once()
+
+	// When
+	err := accessSyncer.SyncAccessProviderToTarget(context.Background(), &accessProviders, accessProviderHandlerMock, configMap)
+
+	// Then
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []sync_to_target.AccessProviderSyncFeedback{
+		{
+			AccessProvider: "workspace-ap-id",
+			ActualName:     "workspace-ap-id",
+			Type:           ptr.String(access_provider.AclSet),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users: []string{"ruben@raito.io"},
+				},
+			},
+		},
+		{
+			AccessProvider: "workspace-entitlement-ap-id",
+			ActualName:     "workspace-entitlement-ap-id",
+			Type:           ptr.String(access_provider.AclSet),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Groups: []string{"group1"},
+				},
+			},
+		},
+		{
+			AccessProvider: "workspace-sp-entitlement-ap-id",
+			ActualName:     "workspace-sp-entitlement-ap-id",
+			Type:           ptr.String(access_provider.AclSet),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users: []string{"app-1"},
+				},
+			},
+		},
+	}, accessProviderHandlerMock.AccessProviderFeedback)
+}
+
+func TestAccessSyncer_SyncAccessProviderToTarget_withAccountRoles(t *testing.T) {
+	// Given
+	deployment := "test-deployment"
+	accessSyncer, mockAccountRepo, mockWorkspaceRepoMap := createAccessSyncer(t, deployment)
+
+	accessProviderHandlerMock := mocks.NewSimpleAccessProviderFeedbackHandler(t)
+
+	accessProviders := sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{
+			{
+				Id:     "account-ap-id",
+				Name:   "account-ap",
+				Action: types3.Grant,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "AccountId",
+							Type:     data_source.Datasource,
+						},
+						Permissions: []string{"ACCOUNT ADMIN", "MARKETPLACE ADMIN"},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Users:  []string{"ruben@raito.io"},
+					Groups: []string{"group1"},
+				},
+				DeletedWho: &sync_to_target.WhoItem{
+					Users: []string{"5f239a72-c050-47b4-947c-f329f8e2e8f2"},
+				},
+			},
+			{
+				Id:     "metastore-ap-id",
+				Name:   "metastore-ap",
+				Action: types3.Grant,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "metastore-id1",
+							Type:     constants.MetastoreType,
+						},
+						Permissions: []string{"METASTORE ADMIN"},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Groups: []string{"group2"},
+				},
+			},
+		},
+	}
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:          "AccountId",
+			constants.DatabricksUser:               "User",
+			constants.DatabricksPassword:           "Password",
+			constants.DatabricksPlatform:           "AWS",
+			constants.DatabricksManageAccountRoles: "true",
+		},
+	}
+
+	mockAccountRepo.EXPECT().ListMetastores(mock.Anything).Return([]catalog.MetastoreInfo{}, nil).Once()
+
+	mockAccountRepo.EXPECT().ListUsers(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, _ ...func(filter *types2.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User] {
+		return repo.ArrayToChannel([]iam.User{{UserName: "ruben@raito.io", Id: "314"}})
+	})
+	mockAccountRepo.EXPECT().ListServicePrincipals(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, _ ...func(filter *types2.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal] {
+		return repo.ArrayToChannel([]iam.ServicePrincipal{{ApplicationId: "5f239a72-c050-47b4-947c-f329f8e2e8f2", Id: "7412", Roles: []iam.ComplexValue{{Value: "account_admin"}}}})
+	})
+	mockAccountRepo.EXPECT().ListGroups(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, f ...func(filter *types2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
+		options := types2.DatabricksGroupsFilter{}
+		for _, fn := range f {
+			fn(&options)
+		}
+
+		// All groups are listed to find the remaining account admins before account admin is revoked
+		if options.Groupname == nil {
+			return repo.ArrayToChannel([]iam.Group{{DisplayName: "admins", Id: "1234", Roles: []iam.ComplexValue{{Value: "account_admin"}}}})
+		}
+
+		if *options.Groupname == "group1" {
+			return repo.ArrayToChannel([]iam.Group{{DisplayName: "group1", Id: "6535"}})
+		}
+
+		return repo.ArrayToChannel([]iam.Group{})
+	})
+
+	mockAccountRepo.EXPECT().UpdateUserRoles(mock.Anything, "314", []string{"account_admin"}, []string(nil)).Return(nil).Once()
+	mockAccountRepo.EXPECT().UpdateGroupRoles(mock.Anything, "6535", []string{"account_admin"}, []string(nil)).Return(nil).Once()
+	mockAccountRepo.EXPECT().UpdateServicePrincipalRoles(mock.Anything, "7412", []string(nil), []string{"account_admin"}).Return(nil).Once()
+
+	// The plugin principal is resolved in a workspace before account admin is revoked
+	mockAccountRepo.EXPECT().GetWorkspaces(mock.Anything).Return([]provisioning.Workspace{{WorkspaceId: 42, DeploymentName: deployment, WorkspaceName: "test-workspace"}}, nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().Ping(mock.Anything).Return(nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().Me(mock.Anything).Return(&iam.User{UserName: "User"}, nil).Once()
+
+	mockAccountRepo.EXPECT().GetAccountRuleSet(mock.Anything).Return(&iam.RuleSetResponse{
+		Etag: "etag-1",
+		GrantRules: []iam.GrantRule{
+			{Role: "roles/marketplace.admin", Principals: []string{"users/dieter@raito.io", "servicePrincipals/5f239a72-c050-47b4-947c-f329f8e2e8f2"}},
+			{Role: "roles/servicePrincipal.user", Principals: []string{"users/ruben@raito.io"}},
+		},
+	}, nil).Once()
+	mockAccountRepo.EXPECT().UpdateAccountRuleSet(mock.Anything, "etag-1", []iam.GrantRule{
+		{Role: "roles/servicePrincipal.user", Principals: []string{"users/ruben@raito.io"}},
+		{Role: "roles/marketplace.admin", Principals: []string{"groups/group1", "users/dieter@raito.io", "users/ruben@raito.io"}},
+	}).Return(nil).Once()
+
+	mockAccountRepo.EXPECT().UpdateMetastoreOwner(mock.Anything, "metastore-id1", "group2").Return(nil).Once()
+
+	// When
+	err := accessSyncer.SyncAccessProviderToTarget(context.Background(), &accessProviders, accessProviderHandlerMock, configMap)
+
+	// Then
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []sync_to_target.AccessProviderSyncFeedback{
+		{
+			AccessProvider: "account-ap-id",
+			ActualName:     "account-ap-id",
+			Type:           ptr.String(access_provider.AclSet),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users:  []string{"ruben@raito.io"},
+					Groups: []string{"group1"},
+				},
+			},
+		},
+		{
+			AccessProvider: "metastore-ap-id",
+			ActualName:     "metastore-ap-id",
+			Type:           ptr.String(access_provider.AclSet),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Groups: []string{"group2"},
+				},
+			},
+		},
+	}, accessProviderHandlerMock.AccessProviderFeedback)
+}
+
+func TestAccessSyncer_storeAccountRoles_refusesAccountAdminRevokes(t *testing.T) {
+	revoke := func(apId string) *types.PrivilegesChanges {
+		return &types.PrivilegesChanges{Add: set.NewSet[string](), Remove: set.NewSet[string]("ACCOUNT_ADMIN"), AssociatedAPs: set.NewSet[string](apId)}
+	}
+
+	pluginPrincipal := func(principal string) func() (string, error) {
+		return func() (string, error) { return principal, nil }
+	}
+
+	t.Run("Plugin principal", func(t *testing.T) {
+		// Given
+		mockAccountRepo := newMockDataAccessAccountRepository(t)
+		mockAccountRepo.EXPECT().ListUsers(mock.Anything).Return(repo.ArrayToChannel([]iam.User{
+			{UserName: "plugin@raito.io", Id: "1", Roles: []iam.ComplexValue{{Value: "account_admin"}}},
+			{UserName: "ruben@raito.io", Id: "2", Roles: []iam.ComplexValue{{Value: "account_admin"}}},
+		})).Once()
+		mockAccountRepo.EXPECT().ListServicePrincipals(mock.Anything).Return(repo.ArrayToChannel([]iam.ServicePrincipal{})).Once()
+		mockAccountRepo.EXPECT().ListGroups(mock.Anything).Return(repo.ArrayToChannel([]iam.Group{
+			{DisplayName: "admins", Id: "3", Roles: []iam.ComplexValue{{Value: "account_admin"}}, Members: []iam.ComplexValue{{Value: "1"}}},
+		})).Once()
+		mockAccountRepo.EXPECT().ListUsers(mock.Anything, mock.Anything).Return(repo.ArrayToChannel([]iam.User{{UserName: "ruben@raito.io", Id: "2"}})).Once()
+		mockAccountRepo.EXPECT().UpdateUserRoles(mock.Anything, "2", []string(nil), []string{"account_admin"}).Return(nil).Once()
+
+		accessSyncer := AccessSyncer{apFeedbackObjects: map[string]sync_to_target.AccessProviderSyncFeedback{}}
+
+		principlePrivilegesMap := map[string]*types.PrivilegesChanges{
+			"plugin@raito.io": revoke("ap-plugin"),
+			"admins":          revoke("ap-group"),
+			"ruben@raito.io":  revoke("ap-ruben"),
+		}
+
+		// When
+		accessSyncer.storeAccountRoles(context.Background(), principlePrivilegesMap, mockAccountRepo, true, pluginPrincipal("plugin@raito.io"))
+
+		// Then
+		assert.Equal(t, []string{`account admin can not be revoked from "plugin@raito.io" as the plugin authenticates with it`}, accessSyncer.apFeedbackObjects["ap-plugin"].Errors)
+		assert.Equal(t, []string{`account admin can not be revoked from "admins" as the plugin authenticates with it`}, accessSyncer.apFeedbackObjects["ap-group"].Errors)
+		assert.Empty(t, accessSyncer.apFeedbackObjects["ap-ruben"].Errors)
+	})
+
+	t.Run("Last account admin", func(t *testing.T) {
+		// Given
+		mockAccountRepo := newMockDataAccessAccountRepository(t)
+		mockAccountRepo.EXPECT().ListUsers(mock.Anything).Return(repo.ArrayToChannel([]iam.User{
+			{UserName: "ruben@raito.io", Id: "2", Roles: []iam.ComplexValue{{Value: "account_admin"}}},
+		})).Once()
+		mockAccountRepo.EXPECT().ListServicePrincipals(mock.Anything).Return(repo.ArrayToChannel([]iam.ServicePrincipal{})).Once()
+		mockAccountRepo.EXPECT().ListGroups(mock.Anything).Return(repo.ArrayToChannel([]iam.Group{})).Once()
+
+		accessSyncer := AccessSyncer{apFeedbackObjects: map[string]sync_to_target.AccessProviderSyncFeedback{}}
+
+		principlePrivilegesMap := map[string]*types.PrivilegesChanges{
+			"ruben@raito.io": revoke("ap-ruben"),
+		}
+
+		// When
+		accessSyncer.storeAccountRoles(context.Background(), principlePrivilegesMap, mockAccountRepo, true, pluginPrincipal("plugin@raito.io"))
+
+		// Then
+		assert.Empty(t, principlePrivilegesMap)
+		assert.Equal(t, []string{`account admin can not be revoked from "ruben@raito.io" as no account admin would remain`}, accessSyncer.apFeedbackObjects["ap-ruben"].Errors)
+	})
+
+	t.Run("Unknown plugin principal", func(t *testing.T) {
+		// Given
+		mockAccountRepo := newMockDataAccessAccountRepository(t)
+
+		accessSyncer := AccessSyncer{apFeedbackObjects: map[string]sync_to_target.AccessProviderSyncFeedback{}}
+
+		principlePrivilegesMap := map[string]*types.PrivilegesChanges{
+			"ruben@raito.io": revoke("ap-ruben"),
+		}
+
+		// When
+		accessSyncer.storeAccountRoles(context.Background(), principlePrivilegesMap, mockAccountRepo, true, func() (string, error) {
+			return "", errors.New("no workspace is accessed with the account credentials")
+		})
+
+		// Then
+		assert.Empty(t, principlePrivilegesMap)
+		assert.Equal(t, []string{`account admin can not be revoked from "ruben@raito.io" as the principal the plugin authenticates with is unknown: no workspace is accessed with the account credentials`}, accessSyncer.apFeedbackObjects["ap-ruben"].Errors)
+	})
+}
+
+func TestAccessSyncer_storeMetastoreAdmin_revokeWithoutNewOwner(t *testing.T) {
+	item := types.SecurableItemKey{Type: constants.MetastoreType, FullName: "metastore-id1"}
+
+	tests := []struct {
+		name               string
+		manageAccountRoles bool
+		expectedError      string
+	}{
+		{
+			name:               "Managed",
+			manageAccountRoles: true,
+			expectedError:      `metastore admin of metastore "metastore-id1" can not be revoked from group2 without granting it to another principal`,
+		},
+		{
+			name:               "Not managed",
+			manageAccountRoles: false,
+			expectedError:      "account admin, marketplace admin and metastore admin are only managed if databricks-manage-account-roles is set to true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			accessSyncer := AccessSyncer{apFeedbackObjects: map[string]sync_to_target.AccessProviderSyncFeedback{}}
+
+			principlePrivilegesMap := map[string]*types.PrivilegesChanges{
+				"group2": {Add: set.NewSet[string](), Remove: set.NewSet[string]("METASTORE_ADMIN"), AssociatedAPs: set.NewSet[string]("metastore-ap-id")},
+			}
+
+			// When
+			accessSyncer.storeMetastoreAdmin(context.Background(), item, principlePrivilegesMap, newMockDataAccessAccountRepository(t), tt.manageAccountRoles)
+
+			// Then
+			assert.Empty(t, principlePrivilegesMap)
+			assert.Equal(t, []string{tt.expectedError}, accessSyncer.apFeedbackObjects["metastore-ap-id"].Errors)
+		})
+	}
+}
+
+func TestAccessSyncer_SyncAccessProviderToTarget_withAccountRolesNotManaged(t *testing.T) {
+	// Given
+	deployment := "test-deployment"
+	accessSyncer, mockAccountRepo, _ := createAccessSyncer(t, deployment)
+
+	accessProviderHandlerMock := mocks.NewSimpleAccessProviderFeedbackHandler(t)
+
+	accessProviders := sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{
+			{
+				Id:     "account-ap-id",
+				Name:   "account-ap",
+				Action: types3.Grant,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "AccountId",
+							Type:     data_source.Datasource,
+						},
+						Permissions: []string{"ACCOUNT ADMIN"},
+					},
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "metastore-id1",
+							Type:     constants.MetastoreType,
+						},
+						Permissions: []string{"METASTORE ADMIN"},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Users: []string{"ruben@raito.io"},
+				},
+			},
+		},
+	}
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId: "AccountId",
+			constants.DatabricksUser:      "User",
+			constants.DatabricksPassword:  "Password",
+			constants.DatabricksPlatform:  "AWS",
+		},
+	}
+
+	mockAccountRepo.EXPECT().ListMetastores(mock.Anything).Return([]catalog.MetastoreInfo{}, nil).Once()
+
+	// When
+	err := accessSyncer.SyncAccessProviderToTarget(context.Background(), &accessProviders, accessProviderHandlerMock, configMap)
+
+	// Then
+	require.NoError(t, err)
+
+	expectedError := "account admin, marketplace admin and metastore admin are only managed if databricks-manage-account-roles is set to true"
+
+	assert.ElementsMatch(t, []sync_to_target.AccessProviderSyncFeedback{
+		{
+			AccessProvider: "account-ap-id",
+			ActualName:     "account-ap-id",
+			Type:           ptr.String(access_provider.AclSet),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users: []string{"ruben@raito.io"},
+				},
+			},
+			Errors: []string{expectedError, expectedError},
+		},
+	}, accessProviderHandlerMock.AccessProviderFeedback)
+}
+
+func TestAccessSyncer_SyncAccessProviderToTarget_withRoles(t *testing.T) {
+	// Given
+	deployment := "test-deployment"
+	workspace := "test-workspace"
+	accessSyncer, mockAccountRepo, mockWorkspaceRepoMap := createAccessSyncer(t, deployment)
+
+	accessProviderHandlerMock := mocks.NewSimpleAccessProviderFeedbackHandler(t)
+
+	accessProviders := sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{
+			{
+				Id:         "role-analysts-id",
+				Name:       "Data Analysts",
+				NamingHint: "Data Analysts",
+				Type:       ptr.String(access_provider.Role),
+				Action:     types3.Grant,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "metastore-id1.catalog-1",
+							Type:     constants.CatalogType,
+						},
+						Permissions: []string{"SELECT"},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Users:  []string{"ruben@raito.io", "5f239a72-c050-47b4-947c-f329f8e2e8f2"},
+					Groups: []string{"group1"},
+				},
+			},
+			{
+				Id:         "role-engineers-id",
+				Name:       "Engineers",
+				NamingHint: "Engineers",
+				ActualName: ptr.String("raito_engineers"),
+				Type:       ptr.String(access_provider.Role),
+				Action:     types3.Grant,
+				Who: sync_to_target.WhoItem{
+					Users:       []string{"dieter@raito.io"},
+					InheritFrom: []string{"ID:role-analysts-id"},
+				},
+			},
+			{
+				Id:         "role-old-id",
+				Name:       "Old",
+				NamingHint: "Old",
+				ActualName: ptr.String("raito_old"),
+				Type:       ptr.String(access_provider.Role),
+				Action:     types3.Grant,
+				Delete:     true,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "metastore-id1.catalog-1",
+							Type:     constants.CatalogType,
+						},
+						Permissions: []string{"SELECT"},
+					},
+				},
+			},
+		},
+	}
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId: "AccountId",
+			constants.DatabricksUser:      "User",
+			constants.DatabricksPassword:  "Password",
+			constants.DatabricksPlatform:  "AWS",
+		},
+	}
+
+	metastore1 := catalog.MetastoreInfo{
+		Name:        "metastore1",
+		MetastoreId: "metastore-id1",
+	}
+
+	workspaceObject := provisioning.Workspace{
+		WorkspaceId:     42,
+		DeploymentName:  deployment,
+		WorkspaceName:   workspace,
+		WorkspaceStatus: "RUNNING",
+	}
+
+	mockAccountRepo.EXPECT().ListMetastores(mock.Anything).Return([]catalog.MetastoreInfo{metastore1}, nil).Once()
+	mockAccountRepo.EXPECT().GetWorkspaces(mock.Anything).Return([]provisioning.Workspace{workspaceObject}, nil).Once()
+	mockAccountRepo.EXPECT().GetWorkspaceMap(mock.Anything, []catalog.MetastoreInfo{metastore1}, []provisioning.Workspace{workspaceObject}).Return(map[string][]*provisioning.Workspace{metastore1.MetastoreId: {{DeploymentName: deployment}}}, nil, nil).Once()
+
+	analystsCreated := false
+
+	mockAccountRepo.EXPECT().ListGroups(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, f ...func(filter *types2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
+		options := types2.DatabricksGroupsFilter{}
+		for _, fn := range f {
+			fn(&options)
+		}
+
+		require.NotNil(t, options.Groupname)
+
+		switch *options.Groupname {
+		case "group1":
+			return repo.ArrayToChannel([]iam.Group{{DisplayName: "group1", Id: "6535"}})
+		case "raito_data_analysts":
+			if analystsCreated {
+				return repo.ArrayToChannel([]iam.Group{{DisplayName: "raito_data_analysts", Id: "7001"}})
+			}
+		case "raito_engineers":
+			return repo.ArrayToChannel([]iam.Group{{DisplayName: "raito_engineers", Id: "7002", Members: []iam.ComplexValue{{Value: "1592"}, {Value: "2718"}}}})
+		case "raito_old":
+			return repo.ArrayToChannel([]iam.Group{{DisplayName: "raito_old", Id: "7003"}})
+		}
+
+		return repo.ArrayToChannel([]iam.Group{})
+	})
+	mockAccountRepo.EXPECT().ListUsers(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, f ...func(filter *types2.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User] {
+		options := types2.DatabricksUsersFilter{}
+		for _, fn := range f {
+			fn(&options)
+		}
+
+		require.NotNil(t, options.Username)
+
+		switch *options.Username {
+		case "ruben@raito.io":
+			return repo.ArrayToChannel([]iam.User{{UserName: "ruben@raito.io", Id: "314"}})
+		case "dieter@raito.io":
+			return repo.ArrayToChannel([]iam.User{{UserName: "dieter@raito.io", Id: "1592"}})
+		}
+
+		return repo.ArrayToChannel([]iam.User{})
+	})
+	mockAccountRepo.EXPECT().ListServicePrincipals(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, f ...func(filter *types2.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal] {
+		options := types2.DatabricksServicePrincipalFilter{}
+		for _, fn := range f {
+			fn(&options)
+		}
+
+		require.NotNil(t, options.ApplicationId)
+		assert.Equal(t, "5f239a72-c050-47b4-947c-f329f8e2e8f2", *options.ApplicationId)
+
+		return repo.ArrayToChannel([]iam.ServicePrincipal{{ApplicationId: *options.ApplicationId, Id: "9876"}})
+	})
+
+	mockAccountRepo.EXPECT().CreateGroup(mock.Anything, "raito_data_analysts").RunAndReturn(func(_ context.Context, displayName string) (*iam.Group, error) {
+		analystsCreated = true
+
+		return &iam.Group{DisplayName: displayName, Id: "7001"}, nil
+	}).Once()
+	mockAccountRepo.EXPECT().UpdateGroupMembers(mock.Anything, "7001", []string{"314", "6535", "9876"}, []string(nil)).Return(nil).Once()
+	mockAccountRepo.EXPECT().UpdateGroupMembers(mock.Anything, "7002", []string{"7001"}, []string{"2718"}).Return(nil).Once()
+	mockAccountRepo.EXPECT().UpdateGroupExternalId(mock.Anything, "7001", "raito-role:role-analysts-id").Return(nil).Once()
+	mockAccountRepo.EXPECT().UpdateGroupExternalId(mock.Anything, "7002", "raito-role:role-engineers-id").Return(nil).Once()
+	mockAccountRepo.EXPECT().DeleteGroup(mock.Anything, "7003").Return(nil).Once()
+
+	mockWorkspaceRepoMap[deployment].EXPECT().Ping(mock.Anything).Return(nil).Maybe()
+	mockWorkspaceRepoMap[deployment].EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel([]catalog.CatalogInfo{
+		{
+			FullName:    "catalog-1",
+			MetastoreId: "catalogId-1",
+			Name:        "catalog-1",
+		},
+	})).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().GetCatalogWorkspaceBinding(mock.Anything, "catalog-1").Return(&catalog.WorkspaceBinding{WorkspaceId: 1234, BindingType: catalog.WorkspaceBindingBindingTypeBindingTypeReadWrite}, nil).Maybe()
+	mockWorkspaceRepoMap[deployment].EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeCatalog, "catalog-1", mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, securableType catalog.SecurableType, s string, change ...catalog.PermissionsChange) error {
+		require.Len(t, change, 2)
+
+		for _, c := range change {
+			switch c.Principal {
+			case "raito_data_analysts":
+				assert.ElementsMatch(t, []catalog.Privilege{catalog.PrivilegeUseCatalog, catalog.PrivilegeSelect}, c.Add)
+				assert.Empty(t, c.Remove)
+			case "raito_old":
+				assert.Empty(t, c.Add)
+				assert.ElementsMatch(t, []catalog.Privilege{catalog.PrivilegeSelect}, c.Remove)
+			default:
+				assert.Fail(t, "unexpected principal", c.Principal)
+			}
+		}
+
+		return nil
+	}).Once()
+
+	// When
+	err := accessSyncer.SyncAccessProviderToTarget(context.Background(), &accessProviders, accessProviderHandlerMock, configMap)
+
+	// Then
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []sync_to_target.AccessProviderSyncFeedback{
+		{
+			AccessProvider: "role-analysts-id",
+			ActualName:     "raito_data_analysts",
+			ExternalId:     ptr.String("7001"),
+			Type:           ptr.String(access_provider.Role),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users:  []string{"ruben@raito.io", "5f239a72-c050-47b4-947c-f329f8e2e8f2"},
+					Groups: []string{"group1"},
+				},
+			},
+		},
+		{
+			AccessProvider: "role-engineers-id",
+			ActualName:     "raito_engineers",
+			ExternalId:     ptr.String("7002"),
+			Type:           ptr.String(access_provider.Role),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users:       []string{"dieter@raito.io"},
+					InheritFrom: []string{"ID:role-analysts-id"},
+				},
+			},
+		},
+		{
+			AccessProvider: "role-old-id",
+			ActualName:     "raito_old",
+			ExternalId:     ptr.String("7003"),
+			Type:           ptr.String(access_provider.Role),
+		},
+	}, accessProviderHandlerMock.AccessProviderFeedback)
+}
+
+func TestAccessSyncer_resolveInheritedRoles(t *testing.T) {
+	// Given
+	mockAccountRepo := newMockDataAccessAccountRepository(t)
+	mockAccountRepo.EXPECT().ListGroups(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, f ...func(filter *types2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
+		options := types2.DatabricksGroupsFilter{}
+		for _, fn := range f {
+			fn(&options)
+		}
+
+		require.NotNil(t, options.ExternalId)
+
+		if *options.ExternalId == "raito-role:role-outside-export-id" {
+			return repo.ArrayToChannel([]iam.Group{{DisplayName: "raito_outside_export", Id: "7004", ExternalId: *options.ExternalId}})
+		}
+
+		return repo.ArrayToChannel([]iam.Group{})
+	}).Twice()
+
+	accessSyncer := AccessSyncer{roleGroups: map[string]string{"role-in-export-id": "raito_in_export"}}
+
+	// When
+	groupNames, err := accessSyncer.resolveInheritedRoles(context.Background(), []string{"group1", "ID:role-in-export-id", "ID:role-outside-export-id"}, mockAccountRepo)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"group1", "raito_in_export", "raito_outside_export"}, groupNames)
+	assert.Equal(t, "raito_outside_export", accessSyncer.roleGroups["role-outside-export-id"])
+
+	// When
+	_, err = accessSyncer.resolveInheritedRoles(context.Background(), []string{"ID:unknown-role-id"}, mockAccountRepo)
+
+	// Then
+	require.Error(t, err)
+}
+
+func Test_roleNestingCycles(t *testing.T) {
+	// Given
+	states := []*roleState{
+		{ap: &sync_to_target.AccessProvider{Id: "role-a", Who: sync_to_target.WhoItem{Groups: []string{"raito_b", "group1"}}}, groupName: "raito_a"},
+		{ap: &sync_to_target.AccessProvider{Id: "role-b", Who: sync_to_target.WhoItem{InheritFrom: []string{"ID:role-c"}}}, groupName: "raito_b"},
+		{ap: &sync_to_target.AccessProvider{Id: "role-c", Who: sync_to_target.WhoItem{InheritFrom: []string{"ID:role-a"}}}, groupName: "raito_c"},
+		{ap: &sync_to_target.AccessProvider{Id: "role-d", Who: sync_to_target.WhoItem{InheritFrom: []string{"ID:role-a"}}}, groupName: "raito_d"},
+		{ap: &sync_to_target.AccessProvider{Id: "role-e", Who: sync_to_target.WhoItem{Groups: []string{"raito_e"}}}, groupName: "raito_e"},
+		{ap: &sync_to_target.AccessProvider{Id: "role-f", Who: sync_to_target.WhoItem{Groups: []string{"raito_d"}}, Delete: true}, groupName: "raito_f"},
+	}
+
+	roleGroups := map[string]string{"role-a": "raito_a", "role-b": "raito_b", "role-c": "raito_c", "role-d": "raito_d", "role-e": "raito_e", "role-f": "raito_f"}
+
+	// When
+	cyclicRoles := roleNestingCycles(states, roleGroups)
+
+	// Then
+	assert.ElementsMatch(t, []string{"role-c", "role-e"}, cyclicRoles.Slice())
+}
+
+func TestAccessSyncer_syncGrantToTarget_keepsUsageGrantsOnError(t *testing.T) {
+	// Given
+	usageGrants, err := types.LoadUsageGrantState(filepath.Join(t.TempDir(), "usage-grants.json"))
+	require.NoError(t, err)
+
+	usageKey := types.UsageGrantKey{Item: types.SecurableItemKey{Type: constants.CatalogType, FullName: "metastore-id1.catalog-1"}, Principal: "ruben@raito.io", Privilege: string(catalog.PrivilegeUseCatalog)}
+	usageGrants.Reference(usageKey, "ap-id")
+
+	accessSyncer := AccessSyncer{usageGrants: usageGrants, privilegeCache: types.NewPrivilegeCache()}
+	changeCollection := types.NewPrivilegesChangeCollection()
+
+	ap := sync_to_target.AccessProvider{
+		Id:   "ap-id",
+		Name: "ap",
+		Who:  sync_to_target.WhoItem{Users: []string{"ruben@raito.io"}},
+		What: []sync_to_target.WhatItem{
+			{DataObject: &data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.schema-1", Type: data_source.Schema}, Permissions: []string{"SELECT"}},
+			{DataObject: &data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.dataset-1", Type: "dataset"}, Permissions: []string{"SELECT"}},
+		},
+	}
+
+	// When
+	err = accessSyncer.syncGrantToTarget(context.Background(), &ap, newMockDataAccessAccountRepository(t), &changeCollection)
+
+	// Then
+	require.Error(t, err)
+	assert.True(t, usageGrants.IsReferenced(usageKey))
+	assert.Empty(t, changeCollection.M)
+}
+
+func TestAccessSyncer_SyncAccessProviderToTarget_withUsageGrantCleanup(t *testing.T) {
+	// Given
+	deployment := "test-deployment"
+	workspace := "test-workspace"
+	accessSyncer, mockAccountRepo, mockWorkspaceRepoMap := createAccessSyncer(t, deployment)
+
+	accessProviderHandlerMock := mocks.NewSimpleAccessProviderFeedbackHandler(t)
+
+	stateFile := filepath.Join(t.TempDir(), "usage-grants.json")
+	require.NoError(t, os.WriteFile(stateFile, []byte(`[
+		{"type": "catalog", "fullName": "metastore-id1.catalog-1", "principal": "ruben@raito.io", "privilege": "USE_CATALOG", "owned": true, "references": ["ap-old"]},
+		{"type": "schema", "fullName": "metastore-id1.catalog-1.schema-1", "principal": "ruben@raito.io", "privilege": "USE_SCHEMA", "owned": true, "references": ["ap-old"]},
+		{"type": "schema", "fullName": "metastore-id1.catalog-1.schema-3", "principal": "ruben@raito.io", "privilege": "USE_SCHEMA", "owned": false, "references": ["ap-old"]}
+	]`), 0600))
+
+	accessProviders := sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{
+			{
+				Id:     "ap-old",
+				Name:   "old",
+				Action: types3.Grant,
+				Delete: true,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "metastore-id1.catalog-1.schema-1.table-1",
+							Type:     data_source.Table,
+						},
+						Permissions: []string{"SELECT"},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Users: []string{"ruben@raito.io"},
+				},
+			},
+			{
+				Id:     "ap-new",
+				Name:   "new",
+				Action: types3.Grant,
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{
+							FullName: "metastore-id1.catalog-1.schema-2.table-2",
+							Type:     data_source.Table,
+						},
+						Permissions: []string{"SELECT"},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Users: []string{"ruben@raito.io"},
+				},
+			},
+		},
+	}
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:           "AccountId",
+			constants.DatabricksUser:                "User",
+			constants.DatabricksPassword:            "Password",
+			constants.DatabricksPlatform:            "AWS",
+			constants.DatabricksUsageGrantStateFile: stateFile,
+		},
+	}
+
+	metastore1 := catalog.MetastoreInfo{
+		Name:        "metastore1",
+		MetastoreId: "metastore-id1",
+	}
+
+	workspaceObject := provisioning.Workspace{
+		WorkspaceId:     42,
+		DeploymentName:  deployment,
+		WorkspaceName:   workspace,
+		WorkspaceStatus: "RUNNING",
+	}
 
-		if *options.Username == "ruben@raito.io" {
-			return repo.ArrayToChannel([]iam.User{
-				{
-					DisplayName: "Ruben Mennes",
-					Id:          "314",
-				},
-			})
-		} else if *options.Username == "dieter@raito.io" {
-			return repo.ArrayToChannel([]iam.User{
-				{
-					DisplayName: "Dieter Wachters",
-					Id:          "1592",
-				},
-			})
-		} else {
-			assert.Fail(t, "unexpected username")
-		}
+	mockAccountRepo.EXPECT().ListMetastores(mock.Anything).Return([]catalog.MetastoreInfo{metastore1}, nil).Once()
+	mockAccountRepo.EXPECT().GetWorkspaces(mock.Anything).Return([]provisioning.Workspace{workspaceObject}, nil).Once()
+	mockAccountRepo.EXPECT().GetWorkspaceMap(mock.Anything, []catalog.MetastoreInfo{metastore1}, []provisioning.Workspace{workspaceObject}).Return(map[string][]*provisioning.Workspace{metastore1.MetastoreId: {{DeploymentName: deployment}}}, nil, nil).Once()
 
-		return repo.ArrayToChannel[iam.User]([]iam.User{})
-	})
-	mockAccountRepo.EXPECT().ListGroups(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, f ...func(filter *types2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
-		options := types2.DatabricksGroupsFilter{}
-		for _, fn := range f {
-			fn(&options)
-		}
+	mockWorkspaceRepoMap[deployment].EXPECT().Ping(mock.Anything).Return(nil).Maybe()
+	mockWorkspaceRepoMap[deployment].EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel([]catalog.CatalogInfo{
+		{
+			FullName:    "catalog-1",
+			MetastoreId: "catalogId-1",
+			Name:        "catalog-1",
+		},
+	})).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().GetCatalogWorkspaceBinding(mock.Anything, "catalog-1").Return(&catalog.WorkspaceBinding{WorkspaceId: 1234, BindingType: catalog.WorkspaceBindingBindingTypeBindingTypeReadWrite}, nil).Maybe()
 
-		require.NotNil(t, options.Groupname)
-		require.Equal(t, "group1", *options.Groupname)
+	mockWorkspaceRepoMap[deployment].EXPECT().GetPermissionsOnResource(mock.Anything, catalog.SecurableTypeSchema, "catalog-1.schema-2").Return(&catalog.PermissionsList{
+		PrivilegeAssignments: []catalog.PrivilegeAssignment{
+			{
+				Principal:  "ruben@raito.io",
+				Privileges: []catalog.Privilege{catalog.PrivilegeUseSchema},
+			},
+		},
+	}, nil).Once()
 
-		return repo.ArrayToChannel([]iam.Group{{DisplayName: "group1", Id: "6535"}})
-	})
-	mockAccountRepo.EXPECT().UpdateWorkspaceAssignment(mock.Anything, int64(42), int64(314), []iam.WorkspacePermission{iam.WorkspacePermissionUser}).Return(nil).Once()
-	mockAccountRepo.EXPECT().UpdateWorkspaceAssignment(mock.Anything, int64(42), int64(6535), []iam.WorkspacePermission{iam.WorkspacePermissionUser}).Return(nil).Once()
-	mockAccountRepo.EXPECT().UpdateWorkspaceAssignment(mock.Anything, int64(42), int64(1592), []iam.WorkspacePermission{}).Return(nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeCatalog, "catalog-1", catalog.PermissionsChange{Principal: "ruben@raito.io", Add: []catalog.Privilege{catalog.PrivilegeUseCatalog}, Remove: []catalog.Privilege{}}).Return(nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeSchema, "catalog-1.schema-1", catalog.PermissionsChange{Principal: "ruben@raito.io", Add: []catalog.Privilege{}, Remove: []catalog.Privilege{catalog.PrivilegeUseSchema}}).Return(nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeSchema, "catalog-1.schema-2", catalog.PermissionsChange{Principal: "ruben@raito.io", Add: []catalog.Privilege{catalog.PrivilegeUseSchema}, Remove: []catalog.Privilege{}}).Return(nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeTable, "catalog-1.schema-1.table-1", catalog.PermissionsChange{Principal: "ruben@raito.io", Add: []catalog.Privilege{}, Remove: []catalog.Privilege{catalog.PrivilegeSelect}}).Return(nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeTable, "catalog-1.schema-2.table-2", catalog.PermissionsChange{Principal: "ruben@raito.io", Add: []catalog.Privilege{catalog.PrivilegeSelect}, Remove: []catalog.Privilege{}}).Return(nil).Once()
 
 	// When
 	err := accessSyncer.SyncAccessProviderToTarget(context.Background(), &accessProviders, accessProviderHandlerMock, configMap)
@@ -645,40 +1792,13 @@ func TestAccessSyncer_SyncAccessProviderToTarget(t *testing.T) {
 	// Then
 	require.NoError(t, err)
 
-	assert.Len(t, accessProviderHandlerMock.AccessProviderFeedback, 3)
-	assert.ElementsMatch(t, []sync_to_target.AccessProviderSyncFeedback{
-		{
-			AccessProvider: "workspace-ap-id",
-			ActualName:     "workspace-ap-id",
-			Type:           ptr.String(access_provider.AclSet),
-			State: &sync_to_target.AccessProviderFeedbackState{
-				Who: sync_to_target.AccessProviderWhoFeedbackState{
-					Users:  []string{"ruben@raito.io"},
-					Groups: []string{"group1"},
-				},
-			},
-		},
-		{
-			AccessProvider: "catalog-ap-id",
-			ActualName:     "catalog-ap-id",
-			Type:           ptr.String(access_provider.AclSet),
-			State: &sync_to_target.AccessProviderFeedbackState{
-				Who: sync_to_target.AccessProviderWhoFeedbackState{
-					Users: []string{"wannes@raito.io"},
-				},
-			},
-		},
-		{
-			AccessProvider: "multiple-do-ap-id",
-			ActualName:     "multiple-do-ap-id",
-			Type:           ptr.String(access_provider.AclSet),
-			State: &sync_to_target.AccessProviderFeedbackState{
-				Who: sync_to_target.AccessProviderWhoFeedbackState{
-					Users: []string{"bart@raito.io"},
-				},
-			},
-		},
-	}, accessProviderHandlerMock.AccessProviderFeedback)
+	state, err := os.ReadFile(stateFile)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[
+		{"type": "catalog", "fullName": "metastore-id1.catalog-1", "principal": "ruben@raito.io", "privilege": "USE_CATALOG", "owned": true, "references": ["ap-new"]},
+		{"type": "schema", "fullName": "metastore-id1.catalog-1.schema-2", "principal": "ruben@raito.io", "privilege": "USE_SCHEMA", "owned": false, "references": ["ap-new"]}
+	]`, string(state))
 }
 
 func TestAccessSyncer_SyncAccessProviderToTarget_withMasks(t *testing.T) {
@@ -1628,6 +2748,112 @@ func TestAccessSyncer_SyncAccessProviderToTarget_withErrors(t *testing.T) {
 	}, accessProviderHandlerMock.AccessProviderFeedback)
 }
 
+func TestAccessProviderVisitor_syncEffectivePermissionsFromTarget(t *testing.T) {
+	// Given
+	deployment := "test-deployment"
+	accessSyncer, _, mockWorkspaceRepoMap := createAccessSyncer(t, deployment)
+
+	accessProviderHandlerMock := mocks.NewSimpleAccessProviderHandler(t, 1)
+
+	accessSyncer.privilegeCache.AddPrivilege(data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.schema-1", Type: data_source.Schema}, "ruben@raito.io", "MODIFY")
+
+	visitor := AccessProviderVisitor{
+		syncer:                     accessSyncer,
+		accessProviderHandler:      accessProviderHandlerMock,
+		groups:                     set.NewSet("group1"),
+		servicePrincipals:          set.NewSet[string](),
+		roleWhat:                   map[string]map[data_source.DataObjectReference]set.Set[string]{},
+		importEffectivePermissions: true,
+		inheritedGrants:            newGrantGrouper(grantGroupingPrincipalSet),
+	}
+
+	mockWorkspaceRepoMap[deployment].EXPECT().GetEffectivePermissionsOnResource(mock.Anything, catalog.SecurableTypeTable, "catalog-1.schema-1.table-1").Return(&catalog.EffectivePermissionsList{
+		PrivilegeAssignments: []catalog.EffectivePrivilegeAssignment{
+			{
+				Principal: "ruben@raito.io",
+				Privileges: []catalog.EffectivePrivilege{
+					{Privilege: catalog.PrivilegeSelect},
+					{Privilege: catalog.PrivilegeModify, InheritedFromName: "catalog-1.schema-1", InheritedFromType: catalog.SecurableTypeSchema},
+				},
+			},
+			{
+				Principal: "group1",
+				Privileges: []catalog.EffectivePrivilege{
+					{Privilege: catalog.PrivilegeSelect, InheritedFromName: "catalog-1", InheritedFromType: catalog.SecurableTypeCatalog},
+				},
+			},
+		},
+	}, nil).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().GetEffectivePermissionsOnResource(mock.Anything, catalog.SecurableTypeTable, "catalog-1.schema-1.table-2").Return(&catalog.EffectivePermissionsList{
+		PrivilegeAssignments: []catalog.EffectivePrivilegeAssignment{
+			{
+				Principal: "group1",
+				Privileges: []catalog.EffectivePrivilege{
+					{Privilege: catalog.PrivilegeSelect, InheritedFromName: "catalog-1", InheritedFromType: catalog.SecurableTypeCatalog},
+					{Privilege: catalog.PrivilegeModify, InheritedFromName: "catalog-1", InheritedFromType: catalog.SecurableTypeCatalog},
+				},
+			},
+		},
+	}, nil).Once()
+
+	// When
+	err := visitor.syncAccessProviderObjectFromTarget(context.Background(), mockWorkspaceRepoMap[deployment], "metastore1", "metastore-id1", "catalog-1.schema-1.table-1", data_source.Table, catalog.SecurableTypeTable)
+	require.NoError(t, err)
+
+	err = visitor.syncAccessProviderObjectFromTarget(context.Background(), mockWorkspaceRepoMap[deployment], "metastore1", "metastore-id1", "catalog-1.schema-1.table-2", data_source.Table, catalog.SecurableTypeTable)
+	require.NoError(t, err)
+
+	// Then
+	assert.ElementsMatch(t, accessProviderHandlerMock.AccessProviders, []sync_from_target.AccessProvider{
+		{
+			ExternalId: "metastore-id1.catalog-1.schema-1.table-1_SELECT",
+			Name:       "Table catalog-1.schema-1.table-1 - SELECT",
+			NamingHint: "Table catalog-1.schema-1.table-1 - SELECT",
+			ActualName: "Table catalog-1.schema-1.table-1 - SELECT",
+			Action:     types3.Grant,
+			Type:       ptr.String(access_provider.AclSet),
+			Who: &sync_from_target.WhoItem{
+				Users: []string{"ruben@raito.io"},
+			},
+			What: []sync_from_target.WhatItem{
+				{
+					DataObject: &data_source.DataObjectReference{
+						FullName: "metastore-id1.catalog-1.schema-1.table-1",
+						Type:     data_source.Table,
+					},
+					Permissions: []string{"SELECT"},
+				},
+			},
+		},
+	})
+
+	inheritedAps := visitor.inheritedGrants.AccessProviders(visitor.principalsToWhoItem)
+	require.Len(t, inheritedAps, 1)
+
+	assert.Equal(t, &sync_from_target.AccessProvider{
+		ExternalId:        "metastore-id1.catalog-1_inherited_" + hashPrincipals([]string{"group1"}),
+		Name:              "Inherited from Catalog catalog-1 - group1",
+		NamingHint:        "Inherited from Catalog catalog-1 - group1",
+		ActualName:        "Inherited from Catalog catalog-1 - group1",
+		Action:            types3.Grant,
+		Type:              ptr.String(access_provider.AclSet),
+		NotInternalizable: true,
+		Who: &sync_from_target.WhoItem{
+			Groups: []string{"group1"},
+		},
+		What: []sync_from_target.WhatItem{
+			{
+				DataObject:  &data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.schema-1.table-1", Type: data_source.Table},
+				Permissions: []string{"SELECT"},
+			},
+			{
+				DataObject:  &data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.schema-1.table-2", Type: data_source.Table},
+				Permissions: []string{"MODIFY", "SELECT"},
+			},
+		},
+	}, inheritedAps[0])
+}
+
 func createAccessSyncer(t *testing.T, deployments ...string) (*AccessSyncer, *mockDataAccessAccountRepository, map[string]*mockDataAccessWorkspaceRepository) {
 	t.Helper()
 
@@ -1661,6 +2887,35 @@ func createAccessSyncer(t *testing.T, deployments ...string) (*AccessSyncer, *mo
 	}, accountRepo, workspaceMockRepos
 }
 
+func Test_effectivePermissionSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		privilege catalog.EffectivePrivilege
+		want      data_source.DataObjectReference
+	}{
+		{
+			name:      "Metastore",
+			privilege: catalog.EffectivePrivilege{Privilege: catalog.PrivilegeCreateCatalog, InheritedFromName: "metastore1", InheritedFromType: catalog.SecurableTypeMetastore},
+			want:      data_source.DataObjectReference{FullName: "metastore-id1", Type: constants.MetastoreType},
+		},
+		{
+			name:      "Catalog",
+			privilege: catalog.EffectivePrivilege{Privilege: catalog.PrivilegeSelect, InheritedFromName: "catalog-1", InheritedFromType: catalog.SecurableTypeCatalog},
+			want:      data_source.DataObjectReference{FullName: "metastore-id1.catalog-1", Type: constants.CatalogType},
+		},
+		{
+			name:      "Schema",
+			privilege: catalog.EffectivePrivilege{Privilege: catalog.PrivilegeModify, InheritedFromName: "catalog-1.schema-1", InheritedFromType: catalog.SecurableTypeSchema},
+			want:      data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.schema-1", Type: data_source.Schema},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, effectivePermissionSource("metastore-id1", tt.privilege))
+		})
+	}
+}
+
 func Test_createAccessProviderNamePrefix(t *testing.T) {
 	type args struct {
 		metastoreId      string
@@ -1700,3 +2955,164 @@ func Test_createAccessProviderNamePrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestAccessSyncer_SyncAccessProviderToTarget_withTagBasedPolicies(t *testing.T) {
+	// Given
+	deployment := "test-deployment"
+	workspace := "test-workspace"
+	accessSyncer, mockAccountRepo, mockWorkspaceRepoMap := createAccessSyncer(t, deployment)
+
+	accessProviderHandlerMock := mocks.NewSimpleAccessProviderFeedbackHandler(t)
+
+	accessProviders := sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{
+			{
+				Id:          "mask-id",
+				Name:        "tagged-mask",
+				NamingHint:  "tagged-mask",
+				Description: "Mask all emails",
+				Action:      types3.Mask,
+				PolicyRule:  ptr.String("hasTagValue('pii', 'email')"),
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{FullName: "metastore-id1.catalog-1.schema-1", Type: data_source.Schema},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Users:  []string{"ruben@raito.io"},
+					Groups: []string{"group1"},
+				},
+			},
+			{
+				Id:         "filter-id",
+				Name:       "tagged-filter",
+				NamingHint: "tagged-filter",
+				Action:     types3.Filtered,
+				PolicyRule: ptr.String("{region} = 'EMEA'"),
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{FullName: "metastore-id1.catalog-1", Type: constants.CatalogType},
+					},
+				},
+				Who: sync_to_target.WhoItem{
+					Groups: []string{"emea"},
+				},
+			},
+			{
+				Id:         "deleted-mask-id",
+				Name:       "old-mask",
+				NamingHint: "old-mask",
+				ExternalId: ptr.String("raito_old-mask"),
+				Action:     types3.Mask,
+				Delete:     true,
+				PolicyRule: ptr.String("hasTag('pii')"),
+				What: []sync_to_target.WhatItem{
+					{
+						DataObject: &data_source.DataObjectReference{FullName: "metastore-id1.catalog-1", Type: constants.CatalogType},
+					},
+				},
+			},
+		},
+	}
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:            "AccountId",
+			constants.DatabricksUser:                 "User",
+			constants.DatabricksPassword:             "Password",
+			constants.DatabricksSqlWarehouses:        fmt.Sprintf(`[{"workspace": "%s", "warehouse": "sqlWarehouse1"}]`, deployment),
+			constants.DatabricksPlatform:             "AWS",
+			constants.DatabricksAbacFilterColumnTags: `{"region": "hasTagValue('geo', 'region')"}`,
+		},
+	}
+
+	metastore1 := catalog.MetastoreInfo{
+		Name:        "metastore1",
+		MetastoreId: "metastore-id1",
+	}
+
+	workspaceObject := provisioning.Workspace{
+		WorkspaceId:     42,
+		DeploymentName:  deployment,
+		WorkspaceName:   workspace,
+		WorkspaceStatus: "RUNNING",
+	}
+
+	mockAccountRepo.EXPECT().ListMetastores(mock.Anything).Return([]catalog.MetastoreInfo{metastore1}, nil).Once()
+	mockAccountRepo.EXPECT().GetWorkspaces(mock.Anything).Return([]provisioning.Workspace{workspaceObject}, nil).Once()
+	mockAccountRepo.EXPECT().GetWorkspaceMap(mock.Anything, []catalog.MetastoreInfo{metastore1}, []provisioning.Workspace{workspaceObject}).Return(map[string][]*provisioning.Workspace{metastore1.MetastoreId: {{DeploymentName: deployment}}}, nil, nil).Once()
+
+	mockWarehouseRepo := repo.NewMockWarehouseRepository(t)
+
+	mockWorkspaceRepoMap[deployment].EXPECT().Ping(mock.Anything).Return(nil).Maybe()
+	mockWorkspaceRepoMap[deployment].EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel([]catalog.CatalogInfo{{Name: "catalog-1", FullName: "catalog-1"}})).Once()
+	mockWorkspaceRepoMap[deployment].EXPECT().SqlWarehouseRepository("sqlWarehouse1").Return(mockWarehouseRepo)
+
+	mockWarehouseRepo.EXPECT().GetTaggedColumns(mock.Anything, "catalog-1", "schema-1").Return([]types2.TaggedColumn{
+		{FullName: "schema-1.users.email", DataType: "STRING", Tags: map[string]string{"pii": "email"}},
+		{FullName: "schema-1.users.id", DataType: "BIGINT", Tags: map[string]string{"pii": "id"}},
+	}, nil).Once()
+	mockWarehouseRepo.EXPECT().ExecuteStatement(mock.Anything, "catalog-1", "schema-1", "CREATE OR REPLACE FUNCTION raito_taggedmask_string(val string)\nRETURN '*****';").Return(nil, nil).Once()
+	mockWarehouseRepo.EXPECT().CreateOrReplacePolicy(mock.Anything, "catalog-1", &types2.AbacPolicy{
+		Name:          "raito_tagged-mask",
+		Kind:          types2.AbacColumnMask,
+		SecurableType: "SCHEMA",
+		SecurableName: "catalog-1.schema-1",
+		Comment:       "Mask all emails",
+		Function:      "catalog-1.schema-1.raito_taggedmask_string",
+		To:            []string{"account users"},
+		Except:        []string{"ruben@raito.io", "group1"},
+		MatchColumns:  []types2.AbacMatchColumn{{Condition: types2.AbacCondition{{{Key: "pii", Value: ptr.String("email")}}}, Alias: "masked_column"}},
+	}).Return(nil).Once()
+
+	mockWarehouseRepo.EXPECT().ExecuteStatement(mock.Anything, "catalog-1", "default", "CREATE OR REPLACE FUNCTION raito_taggedfilter_filter(region string)\n RETURN ((is_account_group_member('emea')) AND (region = 'EMEA'));").Return(nil, nil).Once()
+	mockWarehouseRepo.EXPECT().CreateOrReplacePolicy(mock.Anything, "catalog-1", &types2.AbacPolicy{
+		Name:          "raito_tagged-filter",
+		Kind:          types2.AbacRowFilter,
+		SecurableType: "CATALOG",
+		SecurableName: "catalog-1",
+		Function:      "catalog-1.default.raito_taggedfilter_filter",
+		To:            []string{"account users"},
+		MatchColumns:  []types2.AbacMatchColumn{{Condition: types2.AbacCondition{{{Key: "geo", Value: ptr.String("region")}}}, Alias: "region"}},
+	}).Return(nil).Once()
+
+	mockWarehouseRepo.EXPECT().DropPolicy(mock.Anything, "catalog-1", "raito_old-mask", "CATALOG", "catalog-1").Return(nil).Once()
+	mockWarehouseRepo.EXPECT().DropFunction(mock.Anything, "catalog-1", "default", "raito_oldmask_filter").Return(nil).Once()
+	mockWarehouseRepo.EXPECT().DropFunction(mock.Anything, "catalog-1", "default", "raito_oldmask_string").Return(nil).Once()
+
+	// When
+	err := accessSyncer.SyncAccessProviderToTarget(context.Background(), &accessProviders, accessProviderHandlerMock, configMap)
+
+	// Then
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []sync_to_target.AccessProviderSyncFeedback{
+		{
+			AccessProvider: "mask-id",
+			ActualName:     "raito_tagged-mask",
+			ExternalId:     ptr.String("raito_tagged-mask"),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Users:  []string{"ruben@raito.io"},
+					Groups: []string{"group1"},
+				},
+			},
+		},
+		{
+			AccessProvider: "filter-id",
+			ActualName:     "raito_tagged-filter",
+			ExternalId:     ptr.String("raito_tagged-filter"),
+			State: &sync_to_target.AccessProviderFeedbackState{
+				Who: sync_to_target.AccessProviderWhoFeedbackState{
+					Groups: []string{"emea"},
+				},
+			},
+		},
+		{
+			AccessProvider: "deleted-mask-id",
+			ActualName:     "raito_old-mask",
+			ExternalId:     ptr.String("raito_old-mask"),
+			State:          &sync_to_target.AccessProviderFeedbackState{},
+		},
+	}, accessProviderHandlerMock.AccessProviderFeedback)
+}
@@ -0,0 +1,131 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/hashicorp/go-multierror"
+	ds "github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/util/config"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/filters"
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+type tagAssignmentRepository interface {
+	ListEntityTagAssignments(ctx context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error)
+}
+
+// parseDataObjectFilter parses the data object filter expression of the configuration. Nil is returned if no expression is configured.
+func parseDataObjectFilter(configMap *config.ConfigMap) (*filters.Expression, error) {
+	input := strings.TrimSpace(configMap.GetString(constants.DatabricksDataObjectFilter))
+	if input == "" {
+		return nil, nil
+	}
+
+	expression, err := filters.Parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", constants.DatabricksDataObjectFilter, err)
+	}
+
+	return expression, nil
+}
+
+// loadDataObjectFilterTags loads the tags of the data objects (full names without metastore) of the metastore in one concurrent batch, if the expression refers to tags.
+// Tags that are already in the cache are not requested again.
+func loadDataObjectFilterTags(ctx context.Context, expression *filters.Expression, tagCache *entityTagCache, tagRepo tagAssignmentRepository, metastoreId string, fullNames []string) map[string]entityTagResult {
+	if expression == nil || !expression.UsesTags() || len(fullNames) == 0 {
+		return nil
+	}
+
+	return tagCache.load(ctx, tagRepo, metastoreId, fullNames)
+}
+
+// matchDataObjectFilter returns true if the data object matches the expression or no expression is configured.
+// If the expression refers to tags, the tags of the data object are taken from the tags loaded with loadDataObjectFilterTags.
+// An error is returned if the tags of the data object could not be loaded, so the data object is never matched without its tags.
+func matchDataObjectFilter(expression *filters.Expression, tags map[string]entityTagResult, object *filters.Object) (bool, error) {
+	if expression == nil {
+		return true, nil
+	}
+
+	// Functions cannot be tagged
+	if expression.UsesTags() && object.Type != constants.FunctionType {
+		result, found := tags[object.Path]
+		if !found {
+			return false, fmt.Errorf("tags of %q are not loaded", object.Path)
+		}
+
+		if result.Err != nil {
+			return false, fmt.Errorf("load tags of %q: %w", object.Path, result.Err)
+		}
+
+		object.Tags = make(map[string]string, len(result.Assignments))
+
+		for _, assignment := range result.Assignments {
+			object.Tags[assignment.TagKey] = assignment.TagValue
+		}
+	}
+
+	return expression.Match(object), nil
+}
+
+// selectDataObjects returns the data objects that match the expression. The tags of the data objects are loaded in one concurrent batch.
+// Data objects of which the filter could not be evaluated are not selected and are returned as error.
+func selectDataObjects[T any](ctx context.Context, expression *filters.Expression, tagCache *entityTagCache, tagRepo tagAssignmentRepository, metastoreId string, objects []T, filterObject func(T) *filters.Object) ([]T, error) {
+	if expression == nil {
+		return objects, nil
+	}
+
+	filterObjects := make([]*filters.Object, 0, len(objects))
+	fullNames := make([]string, 0, len(objects))
+
+	for _, object := range objects {
+		filterObjects = append(filterObjects, filterObject(object))
+		fullNames = append(fullNames, filterObjects[len(filterObjects)-1].Path)
+	}
+
+	tags := loadDataObjectFilterTags(ctx, expression, tagCache, tagRepo, metastoreId, fullNames)
+
+	var selected []T
+	var err error
+
+	for i, object := range objects {
+		match, matchErr := matchDataObjectFilter(expression, tags, filterObjects[i])
+		if matchErr != nil {
+			err = multierror.Append(err, matchErr)
+
+			continue
+		}
+
+		if match {
+			selected = append(selected, object)
+		}
+	}
+
+	return selected, err
+}
+
+func catalogFilterObject(c *catalog.CatalogInfo) *filters.Object {
+	return &filters.Object{Path: c.Name, Name: c.Name, Type: constants.CatalogType, Owner: c.Owner, Comment: c.Comment}
+}
+
+func schemaFilterObject(schema *catalog.SchemaInfo) *filters.Object {
+	return &filters.Object{Path: schema.FullName, Name: schema.Name, Type: ds.Schema, Owner: schema.Owner, Comment: schema.Comment}
+}
+
+func tableFilterObject(table *catalog.TableInfo) *filters.Object {
+	return &filters.Object{Path: table.FullName, Name: table.Name, Type: ds.Table, TableType: string(table.TableType), Owner: table.Owner, Comment: table.Comment}
+}
+
+// columnFilterObject returns the filter object of the column. Columns have no owner, so the owner of the table is used.
+func columnFilterObject(column *catalog.ColumnInfo, table *catalog.TableInfo) *filters.Object {
+	return &filters.Object{Path: table.FullName + "." + column.Name, Name: column.Name, Type: ds.Column, Owner: table.Owner, Comment: column.Comment}
+}
+
+func functionFilterObject(function *catalog.FunctionInfo) *filters.Object {
+	return &filters.Object{Path: function.FullName, Name: function.Name, Type: constants.FunctionType, Owner: function.Owner, Comment: function.Comment}
+}
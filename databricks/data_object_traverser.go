@@ -5,14 +5,19 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/databricks/databricks-sdk-go/service/catalog"
 	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	"github.com/hashicorp/go-multierror"
 	ds "github.com/raito-io/cli/base/data_source"
 	"github.com/raito-io/golang-set/set"
 
 	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/filters"
 	"cli-plugin-databricks/databricks/repo"
+	"cli-plugin-databricks/databricks/repo/types"
+	"cli-plugin-databricks/utils"
 )
 
 //go:generate go run github.com/vektra/mockery/v2 --name=accountRepository
@@ -28,9 +33,14 @@ type workspaceRepository interface {
 	ListCatalogs(ctx context.Context) <-chan repo.ChannelItem[catalog.CatalogInfo]
 	ListSchemas(ctx context.Context, catalogName string) <-chan repo.ChannelItem[catalog.SchemaInfo]
 	ListAllTables(ctx context.Context, catalogName string, schemaName string) ([]catalog.TableInfo, error)
+	ListTableSummaries(ctx context.Context, catalogName string, schemaName string) ([]catalog.TableInfo, error)
 	ListFunctions(ctx context.Context, catalogName string, schemaName string) <-chan repo.ChannelItem[catalog.FunctionInfo]
+	ListEntityTagAssignments(ctx context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error)
 }
 
+// DataObjectVisitor is called by the DataObjectTraverser for each data object found.
+// Listing may happen concurrently, but the visit methods are always called sequentially and in traversal order, so implementations do not need to be safe for concurrent use.
+//
 //go:generate go run github.com/vektra/mockery/v2 --name=DataObjectVisitor
 type DataObjectVisitor interface {
 	// VisitWorkspace is called for each workspace found in the account
@@ -77,6 +87,16 @@ type DataObjectTraverser struct {
 	catalogFilter   ObjectFilter
 	schemaFilter    ObjectFilter
 	tableFilter     ObjectFilter
+
+	dataObjectFilter *filters.Expression // Nil if no data object filter expression is configured
+	tagAssignments   *entityTagCache     // Tags loaded for the data object filter, shared with the tag handler of the data source sync
+
+	catalogParallelism int
+	schemaParallelism  int
+
+	checkpoint         *traverserCheckpoint            // Set during the traversal if a checkpoint file is configured
+	metastoreResponses map[string]*checkpointResponses // Responses of the visited metastores, stored in the checkpoint once the metastore is completed
+	metadataCache      *metadataCache                  // Set during the traversal if a metadata cache file is configured
 }
 
 func NewDataObjectTraverser(config *ds.DataSourceSyncConfig, accountFactory AccountRepoFactory, workspaceFactory WorkspaceRepoFactory, createFullName CreateFullName) (*DataObjectTraverser, error) {
@@ -105,6 +125,11 @@ func NewDataObjectTraverser(config *ds.DataSourceSyncConfig, accountFactory Acco
 		return nil, fmt.Errorf("table filter: %w", err)
 	}
 
+	dataObjectFilter, err := parseDataObjectFilter(config.GetConfigMap())
+	if err != nil {
+		return nil, fmt.Errorf("data object filter: %w", err)
+	}
+
 	return &DataObjectTraverser{
 		config:               config,
 		accountRepoFactory:   accountFactory,
@@ -116,6 +141,12 @@ func NewDataObjectTraverser(config *ds.DataSourceSyncConfig, accountFactory Acco
 		catalogFilter:   catalogFilter,
 		schemaFilter:    schemaFilter,
 		tableFilter:     tableFilter,
+
+		dataObjectFilter: dataObjectFilter,
+		tagAssignments:   newEntityTagCache(),
+
+		catalogParallelism: config.GetConfigMap().GetIntWithDefault(constants.DatabricksCatalogParallelism, 1),
+		schemaParallelism:  config.GetConfigMap().GetIntWithDefault(constants.DatabricksSchemaParallelism, 1),
 	}, nil
 }
 
@@ -125,6 +156,39 @@ func (t *DataObjectTraverser) Traverse(ctx context.Context, visitor DataObjectVi
 		option(&options)
 	}
 
+	key := traversalKey(options.SecurableTypesToReturn, t.config)
+
+	if checkpointFile := t.config.GetConfigMap().GetString(constants.DatabricksCheckpointFile); checkpointFile != "" {
+		checkpoint, err := loadTraverserCheckpoint(checkpointFile, key)
+		if err != nil {
+			return fmt.Errorf("load checkpoint: %w", err)
+		}
+
+		t.checkpoint = checkpoint
+		t.metastoreResponses = make(map[string]*checkpointResponses)
+
+		defer func() {
+			t.checkpoint.Close()
+			t.checkpoint = nil
+			t.metastoreResponses = nil
+		}()
+	}
+
+	if cacheFile := t.config.GetConfigMap().GetString(constants.DatabricksMetadataCacheFile); cacheFile != "" {
+		maxAge := time.Duration(t.config.GetConfigMap().GetIntWithDefault(constants.DatabricksMetadataCacheMaxAge, int(defaultMetadataCacheMaxAge/time.Hour))) * time.Hour
+
+		cache, err := loadMetadataCache(cacheFile, key, maxAge)
+		if err != nil {
+			return fmt.Errorf("load metadata cache: %w", err)
+		}
+
+		t.metadataCache = cache
+
+		defer func() {
+			t.metadataCache = nil
+		}()
+	}
+
 	accountRepo, err := t.accountRepoFactory()
 	if err != nil {
 		return fmt.Errorf("account repo factory: %w", err)
@@ -140,6 +204,16 @@ func (t *DataObjectTraverser) Traverse(ctx context.Context, visitor DataObjectVi
 		return fmt.Errorf("traverse catalog: %w", err)
 	}
 
+	err = t.checkpoint.Clear()
+	if err != nil {
+		return fmt.Errorf("clear checkpoint: %w", err)
+	}
+
+	err = t.metadataCache.Save()
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Unable to save metadata cache: %s", err.Error()))
+	}
+
 	return nil
 }
 
@@ -159,53 +233,110 @@ func (t *DataObjectTraverser) traverseCatalog(ctx context.Context, visitor DataO
 
 			if metastoreWorkspaces, ok := metastoreWorkspaceMap[metastore.MetastoreId]; ok {
 				visitedCatalogs := set.NewSet[string]()
+				completed := true
 
 				for _, selectedWorkspace := range metastoreWorkspaces {
 					workspaceClient, err2 := t.workspaceRepoFactory(selectedWorkspace)
 					if err2 != nil {
 						logger.Warn(fmt.Sprintf("Failed to login for workspace %s for metastore %s: %s. Will skip all dataobjects in workspace.", selectedWorkspace.WorkspaceName, metastore.MetastoreId, err2))
 
+						completed = false
+
 						continue
 					}
 
 					logger.Info(fmt.Sprintf("Traversing catalogs for metastore %q in workspace %q", metastore.Name, selectedWorkspace.WorkspaceName))
 
-					catalogs := workspaceClient.ListCatalogs(ctx)
-
-					for catalogItem := range catalogs {
-						if catalogItem.HasError() {
-							logger.Warn(fmt.Sprintf("Unable to list catalogs for metastore %s: %s. Will skip all dataobjects in catalog.", metastore.MetastoreId, catalogItem.Err.Error()))
+					catalogs, listErr := collectChannelItems(workspaceClient.ListCatalogs(ctx))
 
-							break
-						}
+					var includedCatalogs []*catalog.CatalogInfo
 
-						if visitedCatalogs.Contains(catalogItem.I.FullName) {
+					for _, c := range catalogs {
+						if visitedCatalogs.Contains(c.FullName) {
 							continue
 						}
 
-						visitedCatalogs.Add(catalogItem.I.FullName)
+						visitedCatalogs.Add(c.FullName)
 
-						fullName := t.createFullName(constants.CatalogType, metastore, catalogItem.I)
+						fullName := t.createFullName(constants.CatalogType, metastore, c)
 
 						logger.Debug(fmt.Sprintf("traversing catalog %s", fullName))
 
-						if t.shouldGoInto(fullName) && t.catalogFilter.IncludeObject(catalogItem.I.Name) {
-							if options.SecurableTypesToReturn.Contains(constants.CatalogType) && t.shouldHandle(fullName) {
-								err = visitor.VisitCatalog(ctx, catalogItem.I, metastore, selectedWorkspace)
-								if err != nil {
-									return fmt.Errorf("handle %s: %w", fullName, err)
-								}
+						if t.shouldGoInto(fullName) && t.catalogFilter.IncludeObject(c.Name) {
+							includedCatalogs = append(includedCatalogs, c)
+						}
+					}
+
+					selectedCatalogs, filterErr := selectDataObjects(ctx, t.dataObjectFilter, t.tagAssignments, workspaceClient, metastore.MetastoreId, includedCatalogs, catalogFilterObject)
+					if filterErr != nil {
+						logger.Warn(fmt.Sprintf("Unable to evaluate the data object filter on catalogs of metastore %s: %s. Will skip all dataobjects in these catalogs.", metastore.MetastoreId, filterErr.Error()))
+
+						completed = false
+					}
+
+					// Schemas of the next catalogs are listed while the current catalog is visited
+					err = utils.ProcessOrdered(ctx, t.catalogParallelism, selectedCatalogs, func(ctx context.Context, c *catalog.CatalogInfo) schemaListing {
+						if !t.shouldListSchemas(options) {
+							return schemaListing{}
+						}
+
+						if record, found := t.checkpoint.Get(constants.CatalogType, t.createFullName(constants.CatalogType, metastore, c)); found {
+							listing := schemaListing{}
+
+							for i := range record.Schemas {
+								listing.schemas = append(listing.schemas, &record.Schemas[i])
 							}
 
-							err = t.traverseSchemas(ctx, options, workspaceClient, catalogItem.I, visitor, selectedWorkspace)
-							if err != nil {
-								logger.Warn(fmt.Sprintf("Unable to list schemas for catalog %s: %s. Will skip all dataobjects in catalog.", fullName, err.Error()))
+							return listing
+						}
+
+						schemas, err := collectChannelItems(workspaceClient.ListSchemas(ctx, c.Name))
 
-								continue
+						return schemaListing{schemas: schemas, err: err}
+					}, func(c *catalog.CatalogInfo, listing schemaListing) error {
+						fullName := t.createFullName(constants.CatalogType, metastore, c)
+						visitCtx, responses := t.visitContext(ctx, constants.CatalogType, fullName)
+
+						if options.SecurableTypesToReturn.Contains(constants.CatalogType) && t.shouldHandle(fullName) {
+							err := visitor.VisitCatalog(visitCtx, c, metastore, selectedWorkspace)
+							if err != nil {
+								return fmt.Errorf("handle %s: %w", fullName, err)
 							}
 						}
+
+						err := t.traverseSchemas(ctx, options, workspaceClient, c, listing, visitor, selectedWorkspace)
+						if err != nil {
+							logger.Warn(fmt.Sprintf("Unable to list schemas for catalog %s: %s. Will skip all dataobjects in catalog.", fullName, err.Error()))
+
+							completed = false
+
+							return nil
+						}
+
+						record := &checkpointRecord{Type: constants.CatalogType, FullName: fullName, Responses: responses.Responses()}
+
+						for _, schema := range listing.schemas {
+							record.Schemas = append(record.Schemas, *schema)
+						}
+
+						t.completeCheckpoint(record)
+
+						return nil
+					})
+					if err != nil {
+						return err
+					}
+
+					if listErr != nil {
+						logger.Warn(fmt.Sprintf("Unable to list catalogs for metastore %s: %s. Will skip all dataobjects in catalog.", metastore.MetastoreId, listErr.Error()))
+
+						completed = false
 					}
 				}
+
+				if completed {
+					t.completeCheckpoint(&checkpointRecord{Type: constants.MetastoreType, FullName: metastore.MetastoreId, Responses: t.metastoreResponses[metastore.MetastoreId].Responses()})
+				}
 			}
 		}
 	}
@@ -213,60 +344,168 @@ func (t *DataObjectTraverser) traverseCatalog(ctx context.Context, visitor DataO
 	return nil
 }
 
-func (t *DataObjectTraverser) traverseSchemas(ctx context.Context, options DataObjectTraverserOptions, workspaceClient workspaceRepository, cat *catalog.CatalogInfo, visitor DataObjectVisitor, selectedWorkspace *provisioning.Workspace) error {
-	if options.SecurableTypesToReturn.Contains(ds.Schema) || options.SecurableTypesToReturn.Contains(ds.Table) || options.SecurableTypesToReturn.Contains(ds.Column) {
-		schemas := workspaceClient.ListSchemas(ctx, cat.Name)
+type schemaListing struct {
+	schemas []*catalog.SchemaInfo
+	err     error
+}
+
+type schemaContent struct {
+	tables       []catalog.TableInfo
+	tablesErr    error
+	functions    []*catalog.FunctionInfo
+	functionsErr error
+
+	listedAt time.Time
+}
 
-		for schemaItem := range schemas {
-			if schemaItem.HasError() {
-				return fmt.Errorf("list schemas of catalog %q: %w", cat.Name, schemaItem.Err)
+func (t *DataObjectTraverser) shouldListSchemas(options DataObjectTraverserOptions) bool {
+	return options.SecurableTypesToReturn.Contains(ds.Schema) || options.SecurableTypesToReturn.Contains(ds.Table) || options.SecurableTypesToReturn.Contains(ds.Column)
+}
+
+func (t *DataObjectTraverser) traverseSchemas(ctx context.Context, options DataObjectTraverserOptions, workspaceClient workspaceRepository, cat *catalog.CatalogInfo, listing schemaListing, visitor DataObjectVisitor, selectedWorkspace *provisioning.Workspace) error {
+	if !t.shouldListSchemas(options) {
+		return nil
+	}
+
+	var includedSchemas []*catalog.SchemaInfo
+
+	for _, schema := range listing.schemas {
+		fullName := t.createFullName(ds.Schema, cat, schema)
+		logger.Debug(fmt.Sprintf("traversing schema %s", fullName))
+
+		if t.shouldGoInto(fullName) && t.schemaFilter.IncludeObject(schema.Name) {
+			includedSchemas = append(includedSchemas, schema)
+		}
+	}
+
+	selectedSchemas, filterErr := selectDataObjects(ctx, t.dataObjectFilter, t.tagAssignments, workspaceClient, cat.MetastoreId, includedSchemas, schemaFilterObject)
+
+	listTables := options.SecurableTypesToReturn.Contains(ds.Table) || options.SecurableTypesToReturn.Contains(ds.Column)
+
+	// Tables and functions of the next schemas are listed while the current schema is visited
+	err := utils.ProcessOrdered(ctx, t.schemaParallelism, selectedSchemas, func(ctx context.Context, schema *catalog.SchemaInfo) schemaContent {
+		if !listTables {
+			return schemaContent{}
+		}
+
+		fullName := t.createFullName(ds.Schema, cat, schema)
+
+		if record, found := t.checkpoint.Get(ds.Schema, fullName); found {
+			content := schemaContent{tables: record.Tables, listedAt: record.CreatedAt}
+
+			for i := range record.Functions {
+				content.functions = append(content.functions, &record.Functions[i])
 			}
 
-			schema := schemaItem.I
+			return content
+		}
 
-			fullName := t.createFullName(ds.Schema, cat, schema)
-			logger.Debug(fmt.Sprintf("traversing schema %s", fullName))
+		content := schemaContent{listedAt: time.Now()}
 
-			if t.shouldGoInto(fullName) && t.schemaFilter.IncludeObject(schema.Name) {
-				if options.SecurableTypesToReturn.Contains(ds.Schema) && t.shouldHandle(fullName) {
-					err := visitor.VisitSchema(ctx, schema, cat, selectedWorkspace)
-					if err != nil {
-						return fmt.Errorf("handle schema %s: %w", fullName, err)
-					}
-				}
+		if entry, found := t.metadataCache.Get(fullName); found && t.cachedTablesUnchanged(ctx, workspaceClient, schema, entry) {
+			logger.Debug(fmt.Sprintf("Tables of schema %s did not change since %s. Using cached tables", fullName, entry.ListedAt.Format(time.RFC3339)))
 
-				if options.SecurableTypesToReturn.Contains(ds.Table) || options.SecurableTypesToReturn.Contains(ds.Column) {
-					tables, err := workspaceClient.ListAllTables(ctx, schema.CatalogName, schema.Name)
-					if err != nil {
-						logger.Warn(fmt.Sprintf("Unable to list tables for schema %s: %s. Will skip all tables and functions in schema", fullName, err.Error()))
+			content.tables = entry.Tables
+			content.listedAt = entry.ListedAt
+		} else {
+			content.tables, content.tablesErr = workspaceClient.ListAllTables(ctx, schema.CatalogName, schema.Name)
+		}
 
-						continue
-					}
+		if content.tablesErr == nil && options.SecurableTypesToReturn.Contains(constants.FunctionType) {
+			content.functions, content.functionsErr = collectChannelItems(workspaceClient.ListFunctions(ctx, schema.CatalogName, schema.Name))
+		}
 
-					err = t.traverseTablesAndColumns(ctx, tables, options, schema, visitor, selectedWorkspace)
-					if err != nil {
-						logger.Warn(fmt.Sprintf("Unable to traverse tables and columns for schema %s: %s", fullName, err.Error()))
-					}
+		return content
+	}, func(schema *catalog.SchemaInfo, content schemaContent) error {
+		fullName := t.createFullName(ds.Schema, cat, schema)
+		visitCtx, responses := t.visitContext(ctx, ds.Schema, fullName)
 
-					err = t.traverseFunctions(ctx, options, workspaceClient, schema, visitor, selectedWorkspace) // should be executed after list tables and traverse tables and columns to check filters and masks
-					if err != nil {
-						logger.Warn(fmt.Sprintf("Unable to traverse functions for schema %s: %s", fullName, err.Error()))
-					}
-				}
+		if options.SecurableTypesToReturn.Contains(ds.Schema) && t.shouldHandle(fullName) {
+			err := visitor.VisitSchema(visitCtx, schema, cat, selectedWorkspace)
+			if err != nil {
+				return fmt.Errorf("handle schema %s: %w", fullName, err)
 			}
 		}
+
+		if !listTables {
+			return nil
+		}
+
+		if content.tablesErr != nil {
+			logger.Warn(fmt.Sprintf("Unable to list tables for schema %s: %s. Will skip all tables and functions in schema", fullName, content.tablesErr.Error()))
+
+			return nil
+		}
+
+		tablesErr := t.traverseTablesAndColumns(visitCtx, content.tables, options, workspaceClient, schema, visitor, selectedWorkspace)
+		if tablesErr != nil {
+			logger.Warn(fmt.Sprintf("Unable to traverse tables and columns for schema %s: %s", fullName, tablesErr.Error()))
+		}
+
+		functionsErr := t.traverseFunctions(visitCtx, options, workspaceClient, content, schema, visitor, selectedWorkspace) // should be executed after traverse tables and columns to check filters and masks
+		if functionsErr != nil {
+			logger.Warn(fmt.Sprintf("Unable to traverse functions for schema %s: %s", fullName, functionsErr.Error()))
+		}
+
+		if tablesErr == nil && functionsErr == nil {
+			record := &checkpointRecord{Type: ds.Schema, FullName: fullName, Tables: content.tables, Responses: responses.Responses()}
+
+			for _, function := range content.functions {
+				record.Functions = append(record.Functions, *function)
+			}
+
+			t.completeCheckpoint(record)
+
+			t.metadataCache.Put(fullName, content.tables, content.listedAt)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if listing.err != nil {
+		return fmt.Errorf("list schemas of catalog %q: %w", cat.Name, listing.err)
+	}
+
+	if filterErr != nil {
+		return fmt.Errorf("evaluate data object filter on schemas of catalog %q: %w", cat.Name, filterErr)
 	}
 
 	return nil
 }
 
-func (t *DataObjectTraverser) traverseTablesAndColumns(ctx context.Context, tables []catalog.TableInfo, options DataObjectTraverserOptions, schema *catalog.SchemaInfo, visitor DataObjectVisitor, selectedWorkspace *provisioning.Workspace) error {
+// traverseTablesAndColumns visits the tables and columns of the schema.
+// Tables and columns of which the data object filter could not be evaluated are skipped and returned as error, after the other tables are visited.
+func (t *DataObjectTraverser) traverseTablesAndColumns(ctx context.Context, tables []catalog.TableInfo, options DataObjectTraverserOptions, workspaceClient workspaceRepository, schema *catalog.SchemaInfo, visitor DataObjectVisitor, selectedWorkspace *provisioning.Workspace) error {
+	var filterErr error
+
 	for i := range tables {
 		fullName := t.createFullName(ds.Table, schema, &tables[i])
 
 		logger.Debug(fmt.Sprintf("traversing table %s", fullName))
 
-		if t.shouldGoInto(fullName) && t.tableFilter.IncludeObject(tables[i].Name) {
+		if !t.shouldGoInto(fullName) || !t.tableFilter.IncludeObject(tables[i].Name) {
+			continue
+		}
+
+		tagBatch := []string{tables[i].FullName}
+		if options.SecurableTypesToReturn.Contains(ds.Column) {
+			// The tags of the columns are loaded together with the tags of the table
+			tagBatch = tableTagBatch(&tables[i])
+		}
+
+		tags := loadDataObjectFilterTags(ctx, t.dataObjectFilter, t.tagAssignments, workspaceClient, schema.MetastoreId, tagBatch)
+
+		match, err := matchDataObjectFilter(t.dataObjectFilter, tags, tableFilterObject(&tables[i]))
+		if err != nil {
+			filterErr = multierror.Append(filterErr, err)
+
+			continue
+		}
+
+		if match {
 			if options.SecurableTypesToReturn.Contains(ds.Table) && t.shouldHandle(fullName) {
 				err := visitor.VisitTable(ctx, &tables[i], schema, selectedWorkspace)
 				if err != nil {
@@ -278,7 +517,18 @@ func (t *DataObjectTraverser) traverseTablesAndColumns(ctx context.Context, tabl
 				for j := range tables[i].Columns {
 					columnFullName := t.createFullName(ds.Column, &tables[i], &tables[i].Columns[j])
 
-					if t.shouldHandle(columnFullName) {
+					if !t.shouldHandle(columnFullName) {
+						continue
+					}
+
+					columnMatch, columnErr := matchDataObjectFilter(t.dataObjectFilter, tags, columnFilterObject(&tables[i].Columns[j], &tables[i]))
+					if columnErr != nil {
+						filterErr = multierror.Append(filterErr, columnErr)
+
+						continue
+					}
+
+					if columnMatch {
 						err := visitor.VisitColumn(ctx, &tables[i].Columns[j], &tables[i], selectedWorkspace)
 						if err != nil {
 							return fmt.Errorf("handle column %s: %w", columnFullName, err)
@@ -289,29 +539,39 @@ func (t *DataObjectTraverser) traverseTablesAndColumns(ctx context.Context, tabl
 		}
 	}
 
+	if filterErr != nil {
+		return fmt.Errorf("evaluate data object filter: %w", filterErr)
+	}
+
 	return nil
 }
 
-func (t *DataObjectTraverser) traverseFunctions(ctx context.Context, options DataObjectTraverserOptions, workspaceClient workspaceRepository, schema *catalog.SchemaInfo, visitor DataObjectVisitor, selectedWorkspace *provisioning.Workspace) error {
+func (t *DataObjectTraverser) traverseFunctions(ctx context.Context, options DataObjectTraverserOptions, workspaceClient workspaceRepository, content schemaContent, schema *catalog.SchemaInfo, visitor DataObjectVisitor, selectedWorkspace *provisioning.Workspace) error {
 	if options.SecurableTypesToReturn.Contains(constants.FunctionType) {
-		functions := workspaceClient.ListFunctions(ctx, schema.CatalogName, schema.Name)
+		for _, function := range content.functions {
+			logger.Debug(fmt.Sprintf("traversing function %s", function.FullName))
 
-		for functionItem := range functions {
-			if functionItem.HasError() {
-				return fmt.Errorf("list functions of schema %s: %w", schema.FullName, functionItem.Err)
+			if !t.shouldHandle(t.createFullName(constants.FunctionType, schema, function)) {
+				continue
 			}
 
-			function := functionItem.I
-
-			logger.Debug(fmt.Sprintf("traversing function %s", function.FullName))
+			// Functions cannot be tagged, so no tags are loaded
+			match, err := matchDataObjectFilter(t.dataObjectFilter, nil, functionFilterObject(function))
+			if err != nil {
+				return fmt.Errorf("evaluate data object filter on function %s: %w", function.FullName, err)
+			}
 
-			if options.SecurableTypesToReturn.Contains(constants.FunctionType) && t.shouldHandle(t.createFullName(constants.FunctionType, schema, function)) {
-				err := visitor.VisitFunction(ctx, function, schema, selectedWorkspace)
+			if match {
+				err = visitor.VisitFunction(ctx, function, schema, selectedWorkspace)
 				if err != nil {
 					return fmt.Errorf("handle function %s: %w", function.FullName, err)
 				}
 			}
 		}
+
+		if content.functionsErr != nil {
+			return fmt.Errorf("list functions of schema %s: %w", schema.FullName, content.functionsErr)
+		}
 	}
 
 	return nil
@@ -372,10 +632,16 @@ func (t *DataObjectTraverser) traverseAccount(ctx context.Context, accountRepo a
 
 			if metastoreWorkspaces, ok := metastoreWorkspaceMap[metastore.MetastoreId]; ok {
 				if t.shouldHandle(t.createFullName(constants.MetastoreType, nil, metastore)) {
-					err = visitor.VisitMetastore(ctx, &metastores[i], metastoreWorkspaces)
+					visitCtx, responses := t.visitContext(ctx, constants.MetastoreType, metastore.MetastoreId)
+
+					err = visitor.VisitMetastore(visitCtx, &metastores[i], metastoreWorkspaces)
 					if err != nil {
 						return nil, nil, err
 					}
+
+					if responses != nil {
+						t.metastoreResponses[metastore.MetastoreId] = responses
+					}
 				}
 			} else {
 				logger.Warn(fmt.Sprintf("No active workspace found for metastore %q", metastore.Name))
@@ -386,6 +652,57 @@ func (t *DataObjectTraverser) traverseAccount(ctx context.Context, accountRepo a
 	return metastores, workspaces, nil
 }
 
+// cachedTablesUnchanged lists the tables of the schema without their columns and properties, to check that no table was created, changed or dropped since the tables were cached
+func (t *DataObjectTraverser) cachedTablesUnchanged(ctx context.Context, workspaceClient workspaceRepository, schema *catalog.SchemaInfo, entry *metadataCacheEntry) bool {
+	tables, err := workspaceClient.ListTableSummaries(ctx, schema.CatalogName, schema.Name)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Unable to list table summaries of schema %s: %s. Will list all tables again", schema.FullName, err.Error()))
+
+		return false
+	}
+
+	return entry.Unchanged(tables)
+}
+
+// visitContext returns the context to visit the data object in.
+// If a checkpoint file is configured, the responses of the visitor are replayed from the checkpoint or kept to store in the checkpoint.
+func (t *DataObjectTraverser) visitContext(ctx context.Context, securableType string, fullName string) (context.Context, *checkpointResponses) {
+	if t.checkpoint == nil {
+		return ctx, nil
+	}
+
+	record, _ := t.checkpoint.Get(securableType, fullName)
+
+	return withCheckpointResponses(ctx, record)
+}
+
+// completeCheckpoint stores the record of a completely traversed data object in the checkpoint, unless the interrupted traversal already completed it
+func (t *DataObjectTraverser) completeCheckpoint(record *checkpointRecord) {
+	if _, found := t.checkpoint.Get(record.Type, record.FullName); found {
+		return
+	}
+
+	err := t.checkpoint.Complete(record)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Unable to store checkpoint for %s %s: %s", record.Type, record.FullName, err.Error()))
+	}
+}
+
+// collectChannelItems reads all items of the channel. If an error is received, the items received before the error are returned together with the error.
+func collectChannelItems[T any](items <-chan repo.ChannelItem[T]) ([]*T, error) {
+	var result []*T
+
+	for item := range items {
+		if item.HasError() {
+			return result, item.Err
+		}
+
+		result = append(result, item.I)
+	}
+
+	return result, nil
+}
+
 func filterObjects[T any](input []T, filter func(o T) bool) []T {
 	filtered := make([]T, 0)
 
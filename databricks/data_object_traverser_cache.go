@@ -0,0 +1,154 @@
+package databricks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+)
+
+const defaultMetadataCacheMaxAge = 7 * 24 * time.Hour
+
+type metadataCacheEntry struct {
+	ListedAt time.Time           `json:"listedAt"`
+	Tables   []catalog.TableInfo `json:"tables"`
+}
+
+type metadataCacheContent struct {
+	Traversals map[string]map[string]*metadataCacheEntry `json:"traversals"` // traversal key -> schema -> entry
+}
+
+// metadataCache keeps the tables of each schema, including their columns.
+// If none of the tables of a schema was created, changed or dropped since the previous sync, the tables are served from the cache instead of listing them again with all their details.
+// Cached entries are listed again once they are older than maxAge, regardless of their modification times.
+type metadataCache struct {
+	path   string
+	key    string
+	maxAge time.Duration
+
+	content metadataCacheContent
+	entries map[string]*metadataCacheEntry // Entries of the current traversal
+}
+
+func loadMetadataCache(path string, key string, maxAge time.Duration) (*metadataCache, error) {
+	cache := &metadataCache{
+		path:    path,
+		key:     key,
+		maxAge:  maxAge,
+		entries: make(map[string]*metadataCacheEntry),
+	}
+
+	fileContent, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read metadata cache %q: %w", path, err)
+	}
+
+	if len(fileContent) > 0 {
+		err = json.Unmarshal(fileContent, &cache.content)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Ignoring invalid metadata cache %q: %s", path, err.Error()))
+
+			cache.content = metadataCacheContent{}
+		}
+	}
+
+	if cache.content.Traversals == nil {
+		cache.content.Traversals = make(map[string]map[string]*metadataCacheEntry)
+	}
+
+	return cache, nil
+}
+
+// Get returns the cached entry of the schema, if it is not older than the maximum age.
+// The caller verifies with Unchanged whether the cached tables are still up to date.
+func (c *metadataCache) Get(schemaFullName string) (*metadataCacheEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	entry, found := c.content.Traversals[c.key][schemaFullName]
+	if !found || time.Since(entry.ListedAt) >= c.maxAge {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// Put stores the tables of the schema, listed at listedAt
+func (c *metadataCache) Put(schemaFullName string, tables []catalog.TableInfo, listedAt time.Time) {
+	if c == nil {
+		return
+	}
+
+	c.entries[schemaFullName] = &metadataCacheEntry{
+		ListedAt: listedAt,
+		Tables:   tables,
+	}
+}
+
+// Unchanged checks if the current tables of the schema, listed without their columns, are the cached tables with the same creation and modification times.
+// Changing the columns or the properties of a table changes its modification time.
+func (e *metadataCacheEntry) Unchanged(currentTables []catalog.TableInfo) bool {
+	if len(currentTables) != len(e.Tables) {
+		return false
+	}
+
+	cachedTables := make(map[string]*catalog.TableInfo, len(e.Tables))
+	for i := range e.Tables {
+		cachedTables[e.Tables[i].FullName] = &e.Tables[i]
+	}
+
+	for i := range currentTables {
+		cachedTable, found := cachedTables[currentTables[i].FullName]
+		if !found || cachedTable.CreatedAt != currentTables[i].CreatedAt || cachedTable.UpdatedAt != currentTables[i].UpdatedAt {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Save replaces the cached entries of this traversal with the entries of the current traversal, so schemas that no longer exist are removed
+func (c *metadataCache) Save() error {
+	if c == nil {
+		return nil
+	}
+
+	c.content.Traversals[c.key] = c.entries
+
+	fileContent, err := json.Marshal(c.content)
+	if err != nil {
+		return fmt.Errorf("marshal metadata cache: %w", err)
+	}
+
+	// Write to a temporary file first, so an interrupted sync never leaves a partially written cache
+	tmpFile, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create metadata cache %q: %w", c.path, err)
+	}
+
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(fileContent)
+	if err != nil {
+		tmpFile.Close()
+
+		return fmt.Errorf("write metadata cache %q: %w", c.path, err)
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return fmt.Errorf("write metadata cache %q: %w", c.path, err)
+	}
+
+	err = os.Rename(tmpFile.Name(), c.path)
+	if err != nil {
+		return fmt.Errorf("write metadata cache %q: %w", c.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,324 @@
+package databricks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	ds "github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/golang-set/set"
+
+	"cli-plugin-databricks/databricks/constants"
+)
+
+// checkpointMaxAge is the maximum age of a checkpoint to resume from. Older checkpoints are ignored, as the listed data objects are likely outdated.
+const checkpointMaxAge = 24 * time.Hour
+
+type checkpointRecord struct {
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"createdAt"`
+	Type      string    `json:"type"` // Metastore, catalog or schema
+	FullName  string    `json:"fullName"`
+
+	Schemas   []catalog.SchemaInfo   `json:"schemas,omitempty"`   // Schemas of a catalog
+	Tables    []catalog.TableInfo    `json:"tables,omitempty"`    // Tables of a schema
+	Functions []catalog.FunctionInfo `json:"functions,omitempty"` // Functions of a schema
+
+	Responses map[string]json.RawMessage `json:"responses,omitempty"` // Responses of the requests of the visitor while visiting the data object
+}
+
+// traverserCheckpoint keeps track of the metastores, catalogs and schemas of which all data objects are traversed.
+// The listed data objects and the responses of the visitor are appended to the checkpoint file, so an interrupted traversal can resume without listing and requesting them again.
+// The file may contain checkpoints of multiple traversals, identified by their key.
+type traverserCheckpoint struct {
+	path      string
+	key       string
+	completed map[checkpointRecordKey]*checkpointRecord // Completed data objects of the previous, interrupted traversal
+	file      *os.File
+}
+
+type checkpointRecordKey struct {
+	securableType string
+	fullName      string
+}
+
+// traversalFilterParameters are the parameters that filter the traversed data objects
+var traversalFilterParameters = []string{
+	constants.DatabricksExcludeWorkspaces, constants.DatabricksIncludeWorkspaces,
+	constants.DatabricksExcludeMetastores, constants.DatabricksIncludeMetastores,
+	constants.DatabricksExcludeCatalogs, constants.DatabricksIncludeCatalogs,
+	constants.DatabricksExcludeSchemas, constants.DatabricksIncludeSchemas,
+	constants.DatabricksExcludeTables, constants.DatabricksIncludeTables,
+	constants.DatabricksDataObjectFilter,
+}
+
+// traversalKey identifies the traversal, so a checkpoint is only used to resume the same kind of traversal with the same filters
+func traversalKey(securableTypes set.Set[string], config *ds.DataSourceSyncConfig) string {
+	types := securableTypes.Slice()
+	slices.Sort(types)
+
+	parts := []string{strings.Join(types, ","), config.DataObjectParent, strings.Join(config.DataObjectExcludes, ",")}
+
+	for _, parameter := range traversalFilterParameters {
+		parts = append(parts, config.GetConfigMap().GetString(parameter))
+	}
+
+	return strings.Join(parts, "|")
+}
+
+func loadTraverserCheckpoint(path string, key string) (*traverserCheckpoint, error) {
+	checkpoint := &traverserCheckpoint{
+		path:      path,
+		key:       key,
+		completed: make(map[checkpointRecordKey]*checkpointRecord),
+	}
+
+	records, err := readCheckpointRecords(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if record.Key == key && time.Since(record.CreatedAt) < checkpointMaxAge {
+			checkpoint.completed[checkpointRecordKey{securableType: record.Type, fullName: record.FullName}] = record
+		}
+	}
+
+	if len(checkpoint.completed) > 0 {
+		logger.Info(fmt.Sprintf("Resuming traversal from checkpoint %s: %d metastores, catalogs and schemas already traversed", path, len(checkpoint.completed)))
+	}
+
+	return checkpoint, nil
+}
+
+func readCheckpointRecords(path string) ([]*checkpointRecord, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("open checkpoint %q: %w", path, err)
+	}
+
+	defer file.Close()
+
+	var records []*checkpointRecord
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<30)
+
+	for scanner.Scan() {
+		record := &checkpointRecord{}
+
+		// A partially written record is expected if the traversal was interrupted while writing
+		if json.Unmarshal(scanner.Bytes(), record) != nil {
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read checkpoint %q: %w", path, err)
+	}
+
+	return records, nil
+}
+
+// Get returns the record of the data object, if the data object was completely traversed by the interrupted traversal
+func (c *traverserCheckpoint) Get(securableType string, fullName string) (*checkpointRecord, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	record, found := c.completed[checkpointRecordKey{securableType: securableType, fullName: fullName}]
+
+	return record, found
+}
+
+// Complete stores the record of a completely traversed data object
+func (c *traverserCheckpoint) Complete(record *checkpointRecord) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.file == nil {
+		file, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("open checkpoint %q: %w", c.path, err)
+		}
+
+		c.file = file
+	}
+
+	record.Key = c.key
+	record.CreatedAt = time.Now()
+
+	content, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint of %s %q: %w", record.Type, record.FullName, err)
+	}
+
+	_, err = c.file.Write(append(content, '\n'))
+	if err != nil {
+		return fmt.Errorf("write checkpoint of %s %q: %w", record.Type, record.FullName, err)
+	}
+
+	return nil
+}
+
+// Clear removes the checkpoint of this traversal once it is completed. Checkpoints of other traversals are kept.
+func (c *traverserCheckpoint) Clear() error {
+	if c == nil {
+		return nil
+	}
+
+	err := c.Close()
+	if err != nil {
+		return err
+	}
+
+	records, err := readCheckpointRecords(c.path)
+	if err != nil {
+		return err
+	}
+
+	records = slices.DeleteFunc(records, func(record *checkpointRecord) bool {
+		return record.Key == c.key || time.Since(record.CreatedAt) >= checkpointMaxAge
+	})
+
+	if len(records) == 0 {
+		err = os.Remove(c.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove checkpoint %q: %w", c.path, err)
+		}
+
+		return nil
+	}
+
+	var content []byte
+
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("marshal checkpoint: %w", err)
+		}
+
+		content = append(append(content, line...), '\n')
+	}
+
+	err = os.WriteFile(c.path, content, 0600)
+	if err != nil {
+		return fmt.Errorf("write checkpoint %q: %w", c.path, err)
+	}
+
+	return nil
+}
+
+func (c *traverserCheckpoint) Close() error {
+	if c == nil || c.file == nil {
+		return nil
+	}
+
+	err := c.file.Close()
+	c.file = nil
+
+	if err != nil {
+		return fmt.Errorf("close checkpoint %q: %w", c.path, err)
+	}
+
+	return nil
+}
+
+// checkpointResponses keeps the responses of the requests of the visitor while visiting a data object.
+// The responses are stored in the checkpoint once the data object is completed, so the visitor does not request them again on resume.
+type checkpointResponses struct {
+	mutex     sync.Mutex
+	recorded  map[string]json.RawMessage // Responses of the interrupted traversal
+	responses map[string]json.RawMessage
+}
+
+type checkpointResponsesContextKey struct{}
+
+// withCheckpointResponses returns the context to visit a data object in. The responses of the record are replayed, if the data object was completed by the interrupted traversal.
+func withCheckpointResponses(ctx context.Context, record *checkpointRecord) (context.Context, *checkpointResponses) {
+	responses := &checkpointResponses{responses: make(map[string]json.RawMessage)}
+
+	if record != nil {
+		responses.recorded = record.Responses
+	}
+
+	return context.WithValue(ctx, checkpointResponsesContextKey{}, responses), responses
+}
+
+// Responses returns all responses of the requests of the visitor
+func (r *checkpointResponses) Responses() map[string]json.RawMessage {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return maps.Clone(r.responses)
+}
+
+func (r *checkpointResponses) get(key string) (json.RawMessage, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	response, found := r.recorded[key]
+	if found {
+		r.responses[key] = response
+	}
+
+	return response, found
+}
+
+func (r *checkpointResponses) put(key string, response json.RawMessage) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.responses[key] = response
+}
+
+// checkpointedResponse returns the response of the request from the checkpoint if the data object was completed by the interrupted traversal.
+// Otherwise, the request is executed and its response is kept to store in the checkpoint. The key identifies the request within the data object.
+func checkpointedResponse[T any](ctx context.Context, key string, request func() (T, error)) (T, error) {
+	responses, _ := ctx.Value(checkpointResponsesContextKey{}).(*checkpointResponses)
+	if responses == nil {
+		return request()
+	}
+
+	if recorded, found := responses.get(key); found {
+		var result T
+
+		if json.Unmarshal(recorded, &result) == nil {
+			return result, nil
+		}
+	}
+
+	result, err := request()
+	if err != nil {
+		return result, err
+	}
+
+	content, marshalErr := json.Marshal(result)
+	if marshalErr != nil {
+		logger.Debug(fmt.Sprintf("Unable to store response %q in checkpoint: %s", key, marshalErr.Error()))
+
+		return result, nil
+	}
+
+	responses.put(key, content)
+
+	return result, nil
+}
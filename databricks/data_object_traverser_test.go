@@ -1,10 +1,26 @@
 package databricks
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	ds "github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/golang-set/set"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/repo"
+	"cli-plugin-databricks/databricks/repo/types"
 )
 
 func TestObjectFilter_IncludeObject(t *testing.T) {
@@ -117,3 +133,372 @@ func TestObjectFilter_IncludeObject(t *testing.T) {
 		})
 	}
 }
+
+type recordingVisitor struct {
+	visited []string
+}
+
+func (r *recordingVisitor) VisitWorkspace(_ context.Context, workspace *provisioning.Workspace) error {
+	r.visited = append(r.visited, "workspace "+workspace.WorkspaceName)
+
+	return nil
+}
+
+func (r *recordingVisitor) VisitMetastore(_ context.Context, metastore *catalog.MetastoreInfo, _ []*provisioning.Workspace) error {
+	r.visited = append(r.visited, "metastore "+metastore.Name)
+
+	return nil
+}
+
+func (r *recordingVisitor) VisitCatalog(_ context.Context, c *catalog.CatalogInfo, _ *catalog.MetastoreInfo, _ *provisioning.Workspace) error {
+	r.visited = append(r.visited, "catalog "+c.FullName)
+
+	return nil
+}
+
+func (r *recordingVisitor) VisitSchema(_ context.Context, schema *catalog.SchemaInfo, _ *catalog.CatalogInfo, _ *provisioning.Workspace) error {
+	r.visited = append(r.visited, "schema "+schema.FullName)
+
+	return nil
+}
+
+func (r *recordingVisitor) VisitTable(_ context.Context, table *catalog.TableInfo, _ *catalog.SchemaInfo, _ *provisioning.Workspace) error {
+	r.visited = append(r.visited, "table "+table.FullName)
+
+	return nil
+}
+
+func (r *recordingVisitor) VisitColumn(_ context.Context, column *catalog.ColumnInfo, table *catalog.TableInfo, _ *provisioning.Workspace) error {
+	r.visited = append(r.visited, "column "+table.FullName+"."+column.Name)
+
+	return nil
+}
+
+func (r *recordingVisitor) VisitFunction(_ context.Context, function *catalog.FunctionInfo, _ *catalog.SchemaInfo, _ *provisioning.Workspace) error {
+	r.visited = append(r.visited, "function "+function.FullName)
+
+	return nil
+}
+
+func TestDataObjectTraverser_Traverse_Parallel(t *testing.T) {
+	// Given
+	accountRepo := newMockAccountRepository(t)
+	workspaceRepo := newMockWorkspaceRepository(t)
+
+	metastores := []catalog.MetastoreInfo{{Name: "metastore", MetastoreId: "metastore-id"}}
+	workspaces := []provisioning.Workspace{{WorkspaceId: 1, WorkspaceName: "workspace", DeploymentName: "deployment"}}
+
+	accountRepo.EXPECT().ListMetastores(mock.Anything).Return(metastores, nil).Once()
+	accountRepo.EXPECT().GetWorkspaces(mock.Anything).Return(workspaces, nil).Once()
+	accountRepo.EXPECT().GetWorkspaceMap(mock.Anything, metastores, workspaces).Return(map[string][]*provisioning.Workspace{"metastore-id": {&workspaces[0]}}, nil, nil).Twice()
+
+	var catalogs []catalog.CatalogInfo
+
+	var expected []string
+
+	for c := range 3 {
+		catalogName := fmt.Sprintf("catalog%d", c)
+		catalogs = append(catalogs, catalog.CatalogInfo{Name: catalogName, FullName: catalogName, MetastoreId: "metastore-id"})
+		expected = append(expected, "catalog "+catalogName)
+
+		var schemas []catalog.SchemaInfo
+
+		for sIdx := range 3 {
+			schemaName := fmt.Sprintf("schema%d", sIdx)
+			schemaFullName := catalogName + "." + schemaName
+			tableFullName := schemaFullName + ".table"
+			functionFullName := schemaFullName + ".function"
+
+			schemas = append(schemas, catalog.SchemaInfo{Name: schemaName, CatalogName: catalogName, FullName: schemaFullName, MetastoreId: "metastore-id"})
+			expected = append(expected, "schema "+schemaFullName, "table "+tableFullName, "column "+tableFullName+".column", "function "+functionFullName)
+
+			// Earlier schemas respond slower to verify results are visited in order
+			delay := time.Duration(3-sIdx) * time.Millisecond
+
+			workspaceRepo.EXPECT().ListAllTables(mock.Anything, catalogName, schemaName).RunAndReturn(func(context.Context, string, string) ([]catalog.TableInfo, error) {
+				time.Sleep(delay)
+
+				return []catalog.TableInfo{{Name: "table", FullName: tableFullName, MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged, Columns: []catalog.ColumnInfo{{Name: "column"}}}}, nil
+			}).Once()
+			workspaceRepo.EXPECT().ListFunctions(mock.Anything, catalogName, schemaName).Return(repo.ArrayToChannel([]catalog.FunctionInfo{{Name: "function", FullName: functionFullName, MetastoreId: "metastore-id"}})).Once()
+		}
+
+		workspaceRepo.EXPECT().ListSchemas(mock.Anything, catalogName).Return(repo.ArrayToChannel(schemas)).Once()
+	}
+
+	workspaceRepo.EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel(catalogs)).Once()
+
+	traverser, err := NewDataObjectTraverser(&ds.DataSourceSyncConfig{ConfigMap: &config.ConfigMap{Parameters: map[string]string{
+		constants.DatabricksCatalogParallelism: "2",
+		constants.DatabricksSchemaParallelism:  "3",
+	}}}, func() (accountRepository, error) {
+		return accountRepo, nil
+	}, func(*provisioning.Workspace) (workspaceRepository, error) {
+		return workspaceRepo, nil
+	}, createFullName)
+	require.NoError(t, err)
+
+	visitor := &recordingVisitor{}
+
+	// When
+	err = traverser.Traverse(context.Background(), visitor, func(traverserOptions *DataObjectTraverserOptions) {
+		traverserOptions.SecurableTypesToReturn = set.NewSet[string](constants.WorkspaceType, constants.MetastoreType, constants.CatalogType, ds.Schema, ds.Table, ds.Column, constants.FunctionType)
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, append([]string{"workspace workspace", "metastore metastore"}, expected...), visitor.visited)
+}
+
+// checkpointedVisitor requests a response for each table, to verify the responses of completed data objects are replayed from the checkpoint
+type checkpointedVisitor struct {
+	recordingVisitor
+}
+
+func (r *checkpointedVisitor) VisitTable(ctx context.Context, table *catalog.TableInfo, _ *catalog.SchemaInfo, _ *provisioning.Workspace) error {
+	response, err := checkpointedResponse(ctx, "table", func() (string, error) {
+		return "requested", nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.visited = append(r.visited, "table "+table.FullName+" "+response)
+
+	return nil
+}
+
+func TestDataObjectTraverser_Traverse_Checkpoint(t *testing.T) {
+	// Given
+	accountRepo := newMockAccountRepository(t)
+	workspaceRepo := newMockWorkspaceRepository(t)
+
+	metastores := []catalog.MetastoreInfo{{Name: "metastore", MetastoreId: "metastore-id"}}
+	workspaces := []provisioning.Workspace{{WorkspaceId: 1, WorkspaceName: "workspace", DeploymentName: "deployment"}}
+
+	accountRepo.EXPECT().ListMetastores(mock.Anything).Return(metastores, nil).Once()
+	accountRepo.EXPECT().GetWorkspaces(mock.Anything).Return(workspaces, nil).Once()
+	accountRepo.EXPECT().GetWorkspaceMap(mock.Anything, metastores, workspaces).Return(map[string][]*provisioning.Workspace{"metastore-id": {&workspaces[0]}}, nil, nil).Twice()
+
+	workspaceRepo.EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel([]catalog.CatalogInfo{
+		{Name: "catalog1", FullName: "catalog1", MetastoreId: "metastore-id"},
+		{Name: "catalog2", FullName: "catalog2", MetastoreId: "metastore-id"},
+	})).Once()
+
+	// Only the catalog and the schema that are not in the checkpoint should be listed
+	workspaceRepo.EXPECT().ListSchemas(mock.Anything, "catalog2").Return(repo.ArrayToChannel([]catalog.SchemaInfo{
+		{Name: "schema1", CatalogName: "catalog2", FullName: "catalog2.schema1", MetastoreId: "metastore-id"},
+		{Name: "schema2", CatalogName: "catalog2", FullName: "catalog2.schema2", MetastoreId: "metastore-id"},
+	})).Once()
+	workspaceRepo.EXPECT().ListAllTables(mock.Anything, "catalog2", "schema2").Return([]catalog.TableInfo{{Name: "table", FullName: "catalog2.schema2.table", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged}}, nil).Once()
+	workspaceRepo.EXPECT().ListFunctions(mock.Anything, "catalog2", "schema2").Return(repo.ArrayToChannel([]catalog.FunctionInfo{})).Once()
+
+	securableTypes := set.NewSet[string](constants.WorkspaceType, constants.MetastoreType, constants.CatalogType, ds.Schema, ds.Table, constants.FunctionType)
+
+	checkpointFile := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	syncConfig := &ds.DataSourceSyncConfig{ConfigMap: &config.ConfigMap{Parameters: map[string]string{
+		constants.DatabricksCheckpointFile: checkpointFile,
+	}}}
+
+	checkpointedSchema := func(catalogName string) *checkpointRecord {
+		return &checkpointRecord{
+			Type:      ds.Schema,
+			FullName:  createUniqueId("metastore-id", catalogName+".schema1"),
+			Tables:    []catalog.TableInfo{{Name: "table", FullName: catalogName + ".schema1.table", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged}},
+			Functions: []catalog.FunctionInfo{{Name: "function", FullName: catalogName + ".schema1.function", MetastoreId: "metastore-id"}},
+			Responses: map[string]json.RawMessage{"table": json.RawMessage(`"replayed"`)},
+		}
+	}
+
+	checkpoint, err := loadTraverserCheckpoint(checkpointFile, traversalKey(securableTypes, syncConfig))
+	require.NoError(t, err)
+	require.NoError(t, checkpoint.Complete(checkpointedSchema("catalog1")))
+	require.NoError(t, checkpoint.Complete(&checkpointRecord{
+		Type:     constants.CatalogType,
+		FullName: createUniqueId("metastore-id", "catalog1"),
+		Schemas:  []catalog.SchemaInfo{{Name: "schema1", CatalogName: "catalog1", FullName: "catalog1.schema1", MetastoreId: "metastore-id"}},
+	}))
+	require.NoError(t, checkpoint.Complete(checkpointedSchema("catalog2")))
+	require.NoError(t, checkpoint.Close())
+
+	// A checkpoint of a traversal with other filters should be ignored
+	otherCheckpoint, err := loadTraverserCheckpoint(checkpointFile, traversalKey(securableTypes, &ds.DataSourceSyncConfig{ConfigMap: &config.ConfigMap{Parameters: map[string]string{
+		constants.DatabricksIncludeSchemas: "schema2",
+	}}}))
+	require.NoError(t, err)
+	require.NoError(t, otherCheckpoint.Complete(&checkpointRecord{Type: ds.Schema, FullName: createUniqueId("metastore-id", "catalog2.schema2")}))
+	require.NoError(t, otherCheckpoint.Close())
+
+	traverser, err := NewDataObjectTraverser(syncConfig, func() (accountRepository, error) {
+		return accountRepo, nil
+	}, func(*provisioning.Workspace) (workspaceRepository, error) {
+		return workspaceRepo, nil
+	}, createFullName)
+	require.NoError(t, err)
+
+	visitor := &checkpointedVisitor{}
+
+	// When
+	err = traverser.Traverse(context.Background(), visitor, func(traverserOptions *DataObjectTraverserOptions) {
+		traverserOptions.SecurableTypesToReturn = securableTypes
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"workspace workspace",
+		"metastore metastore",
+		"catalog catalog1",
+		"schema catalog1.schema1",
+		"table catalog1.schema1.table replayed",
+		"function catalog1.schema1.function",
+		"catalog catalog2",
+		"schema catalog2.schema1",
+		"table catalog2.schema1.table replayed",
+		"function catalog2.schema1.function",
+		"schema catalog2.schema2",
+		"table catalog2.schema2.table requested",
+	}, visitor.visited)
+
+	records, err := readCheckpointRecords(checkpointFile)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.NotEqual(t, traversalKey(securableTypes, syncConfig), records[0].Key)
+}
+
+func TestDataObjectTraverser_Traverse_MetadataCache(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "cache.json")
+
+	traverse := func(schemas []catalog.SchemaInfo, setupTables func(workspaceRepo *mockWorkspaceRepository)) []string {
+		accountRepo := newMockAccountRepository(t)
+		workspaceRepo := newMockWorkspaceRepository(t)
+
+		metastores := []catalog.MetastoreInfo{{Name: "metastore", MetastoreId: "metastore-id"}}
+		workspaces := []provisioning.Workspace{{WorkspaceId: 1, WorkspaceName: "workspace", DeploymentName: "deployment"}}
+
+		accountRepo.EXPECT().ListMetastores(mock.Anything).Return(metastores, nil).Once()
+		accountRepo.EXPECT().GetWorkspaces(mock.Anything).Return(workspaces, nil).Once()
+		accountRepo.EXPECT().GetWorkspaceMap(mock.Anything, metastores, workspaces).Return(map[string][]*provisioning.Workspace{"metastore-id": {&workspaces[0]}}, nil, nil).Twice()
+
+		workspaceRepo.EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel([]catalog.CatalogInfo{{Name: "catalog", FullName: "catalog", MetastoreId: "metastore-id"}})).Once()
+		workspaceRepo.EXPECT().ListSchemas(mock.Anything, "catalog").Return(repo.ArrayToChannel(schemas)).Once()
+
+		setupTables(workspaceRepo)
+
+		traverser, err := NewDataObjectTraverser(&ds.DataSourceSyncConfig{ConfigMap: &config.ConfigMap{Parameters: map[string]string{
+			constants.DatabricksMetadataCacheFile: cacheFile,
+		}}}, func() (accountRepository, error) {
+			return accountRepo, nil
+		}, func(*provisioning.Workspace) (workspaceRepository, error) {
+			return workspaceRepo, nil
+		}, createFullName)
+		require.NoError(t, err)
+
+		visitor := &recordingVisitor{}
+
+		err = traverser.Traverse(context.Background(), visitor, func(traverserOptions *DataObjectTraverserOptions) {
+			traverserOptions.SecurableTypesToReturn = set.NewSet[string](constants.WorkspaceType, constants.MetastoreType, constants.CatalogType, ds.Schema, ds.Table)
+		})
+		require.NoError(t, err)
+
+		return visitor.visited
+	}
+
+	schemas := []catalog.SchemaInfo{
+		{Name: "schema1", CatalogName: "catalog", FullName: "catalog.schema1", MetastoreId: "metastore-id"},
+		{Name: "schema2", CatalogName: "catalog", FullName: "catalog.schema2", MetastoreId: "metastore-id"},
+	}
+
+	// Given
+	visited := traverse(schemas, func(workspaceRepo *mockWorkspaceRepository) {
+		workspaceRepo.EXPECT().ListAllTables(mock.Anything, "catalog", "schema1").Return([]catalog.TableInfo{{Name: "table", FullName: "catalog.schema1.table", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged, UpdatedAt: 1000, Columns: []catalog.ColumnInfo{{Name: "column"}}}}, nil).Once()
+		workspaceRepo.EXPECT().ListAllTables(mock.Anything, "catalog", "schema2").Return([]catalog.TableInfo{{Name: "table", FullName: "catalog.schema2.table", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged, UpdatedAt: 1000}}, nil).Once()
+	})
+
+	require.Equal(t, []string{"workspace workspace", "metastore metastore", "catalog catalog", "schema catalog.schema1", "table catalog.schema1.table", "schema catalog.schema2", "table catalog.schema2.table"}, visited)
+
+	// When
+	visited = traverse(schemas, func(workspaceRepo *mockWorkspaceRepository) {
+		workspaceRepo.EXPECT().ListTableSummaries(mock.Anything, "catalog", "schema1").Return([]catalog.TableInfo{{Name: "table", FullName: "catalog.schema1.table", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged, UpdatedAt: 1000}}, nil).Once()
+		workspaceRepo.EXPECT().ListTableSummaries(mock.Anything, "catalog", "schema2").Return([]catalog.TableInfo{{Name: "table", FullName: "catalog.schema2.table", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged, UpdatedAt: 2000}}, nil).Once()
+
+		// Only the schema with an updated table should be listed again
+		workspaceRepo.EXPECT().ListAllTables(mock.Anything, "catalog", "schema2").Return([]catalog.TableInfo{{Name: "table", FullName: "catalog.schema2.table", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged, UpdatedAt: 2000, Columns: []catalog.ColumnInfo{{Name: "column"}}}}, nil).Once()
+	})
+
+	// Then
+	assert.Equal(t, []string{"workspace workspace", "metastore metastore", "catalog catalog", "schema catalog.schema1", "table catalog.schema1.table", "schema catalog.schema2", "table catalog.schema2.table"}, visited)
+}
+
+func TestDataObjectTraverser_Traverse_DataObjectFilter(t *testing.T) {
+	// Given
+	accountRepo := newMockAccountRepository(t)
+	workspaceRepo := newMockWorkspaceRepository(t)
+
+	metastores := []catalog.MetastoreInfo{{Name: "metastore", MetastoreId: "metastore-id"}}
+	workspaces := []provisioning.Workspace{{WorkspaceId: 1, WorkspaceName: "workspace", DeploymentName: "deployment"}}
+
+	accountRepo.EXPECT().ListMetastores(mock.Anything).Return(metastores, nil).Once()
+	accountRepo.EXPECT().GetWorkspaces(mock.Anything).Return(workspaces, nil).Once()
+	accountRepo.EXPECT().GetWorkspaceMap(mock.Anything, metastores, workspaces).Return(map[string][]*provisioning.Workspace{"metastore-id": {&workspaces[0]}}, nil, nil).Twice()
+
+	workspaceRepo.EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel([]catalog.CatalogInfo{{Name: "catalog", FullName: "catalog", MetastoreId: "metastore-id"}})).Once()
+	workspaceRepo.EXPECT().ListSchemas(mock.Anything, "catalog").Return(repo.ArrayToChannel([]catalog.SchemaInfo{
+		{Name: "ignored", CatalogName: "catalog", FullName: "catalog.ignored", MetastoreId: "metastore-id"},
+		{Name: "schema", CatalogName: "catalog", FullName: "catalog.schema", MetastoreId: "metastore-id"},
+	})).Once()
+	workspaceRepo.EXPECT().ListAllTables(mock.Anything, "catalog", "schema").Return([]catalog.TableInfo{
+		{Name: "managed", FullName: "catalog.schema.managed", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged, Owner: "data-platform"},
+		{Name: "other_owner", FullName: "catalog.schema.other_owner", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged, Owner: "analyst@raito.io"},
+		{Name: "view", FullName: "catalog.schema.view", MetastoreId: "metastore-id", TableType: catalog.TableTypeView, Owner: "data-platform"},
+		{Name: "unknown_tags", FullName: "catalog.schema.unknown_tags", MetastoreId: "metastore-id", TableType: catalog.TableTypeManaged, Owner: "data-platform"},
+	}, nil).Once()
+
+	workspaceRepo.EXPECT().ListEntityTagAssignments(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error) {
+		if entityType == "schemas" && entityName == "catalog.ignored" {
+			return []types.EntityTagAssignment{{EntityType: entityType, EntityName: entityName, TagKey: "raito:ignore"}}, nil
+		}
+
+		// A table of which the tags cannot be loaded should be skipped instead of being matched without tags
+		if entityName == "catalog.schema.unknown_tags" {
+			return nil, errors.New("boom")
+		}
+
+		return nil, nil
+	})
+
+	traverser, err := NewDataObjectTraverser(&ds.DataSourceSyncConfig{ConfigMap: &config.ConfigMap{Parameters: map[string]string{
+		constants.DatabricksDataObjectFilter: `not tag("raito:ignore") and (type != "table" or (table_type == "MANAGED" and owner == "data-platform"))`,
+	}}}, func() (accountRepository, error) {
+		return accountRepo, nil
+	}, func(*provisioning.Workspace) (workspaceRepository, error) {
+		return workspaceRepo, nil
+	}, createFullName)
+	require.NoError(t, err)
+
+	visitor := &recordingVisitor{}
+
+	// When
+	err = traverser.Traverse(context.Background(), visitor, func(traverserOptions *DataObjectTraverserOptions) {
+		traverserOptions.SecurableTypesToReturn = set.NewSet[string](constants.WorkspaceType, constants.MetastoreType, constants.CatalogType, ds.Schema, ds.Table)
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"workspace workspace",
+		"metastore metastore",
+		"catalog catalog",
+		"schema catalog.schema",
+		"table catalog.schema.managed",
+	}, visitor.visited)
+}
+
+func TestNewDataObjectTraverser_InvalidDataObjectFilter(t *testing.T) {
+	_, err := NewDataObjectTraverser(&ds.DataSourceSyncConfig{ConfigMap: &config.ConfigMap{Parameters: map[string]string{
+		constants.DatabricksDataObjectFilter: `owner = "me"`,
+	}}}, nil, nil, createFullName)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "databricks-data-object-filter")
+}
@@ -3,6 +3,7 @@ package databricks
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -28,7 +29,9 @@ var _ wrappers.DataSourceSyncer = (*DataSourceSyncer)(nil)
 //go:generate go run github.com/vektra/mockery/v2 --name=dataSourceWorkspaceRepository
 type dataSourceWorkspaceRepository interface {
 	Ping(ctx context.Context) error
+	GetPermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string) (*catalog.PermissionsList, error)
 	SetPermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string, changes ...catalog.PermissionsChange) error
+	ListEntityTagAssignments(ctx context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error)
 	SqlWarehouseRepository(warehouseId string) repo.WarehouseRepository
 	Me(ctx context.Context) (*iam.User, error)
 	workspaceRepository
@@ -66,6 +69,8 @@ func (d *DataSourceSyncer) SyncDataSource(ctx context.Context, dataSourceHandler
 	d.config = config
 	configParams := config.ConfigMap
 
+	defer repo.LogThrottlingStats()
+
 	defer func() {
 		if err != nil {
 			logger.Error(fmt.Sprintf("SyncDataSource failed: %s", err.Error()))
@@ -90,7 +95,7 @@ func (d *DataSourceSyncer) SyncDataSource(ctx context.Context, dataSourceHandler
 		return fmt.Errorf("creating traverser: %w", err)
 	}
 
-	tags, err := NewDataSourceTagHandler(config.ConfigMap, d.workspaceRepoFactory)
+	tags, err := NewDataSourceTagHandler(config.ConfigMap, traverser.tagAssignments, d.workspaceRepoFactory)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to create tag handler: %s", err.Error()))
 
@@ -99,10 +104,22 @@ func (d *DataSourceSyncer) SyncDataSource(ctx context.Context, dataSourceHandler
 		}
 	}
 
+	lineage, err := NewDataSourceLineageHandler(config.ConfigMap, d.workspaceRepoFactory)
+	if err != nil {
+		return fmt.Errorf("creating lineage handler: %w", err)
+	}
+
+	tableDetails, err := NewDataSourceTableDetailHandler(config.ConfigMap, d.workspaceRepoFactory)
+	if err != nil {
+		return fmt.Errorf("creating table detail handler: %w", err)
+	}
+
 	visitor := DataSourceVisitor{
-		dataSourceHandler: dataSourceHandler,
-		syncer:            d,
-		tagHandler:        tags,
+		dataSourceHandler:  dataSourceHandler,
+		syncer:             d,
+		tagHandler:         tags,
+		lineageHandler:     lineage,
+		tableDetailHandler: tableDetails,
 	}
 
 	err = traverser.Traverse(ctx, visitor, func(traverserOptions *DataObjectTraverserOptions) {
@@ -113,6 +130,16 @@ func (d *DataSourceSyncer) SyncDataSource(ctx context.Context, dataSourceHandler
 		return fmt.Errorf("traversing: %w", err)
 	}
 
+	err = tags.SaveOwnedTags()
+	if err != nil {
+		return fmt.Errorf("save owned tags: %w", err)
+	}
+
+	err = lineage.SaveLineage()
+	if err != nil {
+		return fmt.Errorf("save lineage: %w", err)
+	}
+
 	return nil
 }
 
@@ -164,9 +191,11 @@ func getMetastoreAndFullnameOfUniqueId(uniqueId string) (string, string) {
 var _ DataObjectVisitor = (*DataSourceVisitor)(nil)
 
 type DataSourceVisitor struct {
-	dataSourceHandler wrappers.DataSourceObjectHandler
-	syncer            *DataSourceSyncer
-	tagHandler        *DataSourceTagHandler
+	dataSourceHandler  wrappers.DataSourceObjectHandler
+	syncer             *DataSourceSyncer
+	tagHandler         *DataSourceTagHandler
+	lineageHandler     *DataSourceLineageHandler
+	tableDetailHandler *DataSourceTableDetailHandler
 }
 
 func (d DataSourceVisitor) VisitWorkspace(_ context.Context, workspace *provisioning.Workspace) error {
@@ -180,9 +209,14 @@ func (d DataSourceVisitor) VisitWorkspace(_ context.Context, workspace *provisio
 	})
 }
 
-func (d DataSourceVisitor) VisitMetastore(_ context.Context, metastore *catalog.MetastoreInfo, _ []*provisioning.Workspace) error {
+func (d DataSourceVisitor) VisitMetastore(ctx context.Context, metastore *catalog.MetastoreInfo, workspaces []*provisioning.Workspace) error {
 	logger.Info(fmt.Sprintf("Found metastore %q: %+v", metastore.Name, metastore))
 
+	err := d.lineageHandler.LoadLineage(ctx, metastore, workspaces)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load lineage for metastore %q: %s", metastore.Name, err.Error()))
+	}
+
 	return d.dataSourceHandler.AddDataObjects(&ds.DataObject{
 		Name:       metastore.Name,
 		Type:       constants.MetastoreType,
@@ -206,11 +240,11 @@ func (d DataSourceVisitor) VisitCatalog(ctx context.Context, c *catalog.CatalogI
 		Description:      c.Comment,
 		FullName:         uniqueId,
 		Type:             constants.CatalogType,
-		Tags:             d.tagHandler.GetTag(c.FullName),
+		Tags:             slices.Concat(d.tagHandler.GetTag(ctx, c.FullName), catalogAttributeTags(c)),
 	})
 }
 
-func (d DataSourceVisitor) VisitSchema(_ context.Context, schema *catalog.SchemaInfo, c *catalog.CatalogInfo, _ *provisioning.Workspace) error {
+func (d DataSourceVisitor) VisitSchema(ctx context.Context, schema *catalog.SchemaInfo, c *catalog.CatalogInfo, _ *provisioning.Workspace) error {
 	uniqueId := createUniqueId(schema.MetastoreId, schema.FullName)
 	parentId := createUniqueId(c.MetastoreId, c.FullName)
 
@@ -221,11 +255,11 @@ func (d DataSourceVisitor) VisitSchema(_ context.Context, schema *catalog.Schema
 		Description:      schema.Comment,
 		FullName:         uniqueId,
 		Type:             ds.Schema,
-		Tags:             d.tagHandler.GetTag(schema.FullName),
+		Tags:             slices.Concat(d.tagHandler.GetTag(ctx, schema.FullName), schemaAttributeTags(schema)),
 	})
 }
 
-func (d DataSourceVisitor) VisitTable(_ context.Context, table *catalog.TableInfo, schema *catalog.SchemaInfo, _ *provisioning.Workspace) error {
+func (d DataSourceVisitor) VisitTable(ctx context.Context, table *catalog.TableInfo, schema *catalog.SchemaInfo, workspace *provisioning.Workspace) error {
 	databricksTableType := table.TableType
 	raitoTableType, found := TableTypeMap[databricksTableType]
 
@@ -248,18 +282,25 @@ func (d DataSourceVisitor) VisitTable(_ context.Context, table *catalog.TableInf
 	uniqueId := createUniqueId(table.MetastoreId, table.FullName)
 	parentId := createUniqueId(schema.MetastoreId, schema.FullName)
 
-	return d.dataSourceHandler.AddDataObjects(&ds.DataObject{
+	// The tags of the columns are loaded together with the tags of the table
+	d.tagHandler.LoadTableTags(ctx, table)
+
+	do := &ds.DataObject{
 		Name:             table.Name,
 		ExternalId:       uniqueId,
 		ParentExternalId: parentId,
 		Description:      table.Comment,
 		FullName:         uniqueId,
 		Type:             raitoTableType,
-		Tags:             d.tagHandler.GetTag(table.FullName),
-	})
+		Tags:             slices.Concat(d.tagHandler.GetTag(ctx, table.FullName), tableAttributeTags(table), d.tableDetailHandler.GetTableDetailTags(ctx, table, workspace)),
+	}
+
+	d.lineageHandler.AddDataObject(do)
+
+	return d.dataSourceHandler.AddDataObjects(do)
 }
 
-func (d DataSourceVisitor) VisitColumn(_ context.Context, column *catalog.ColumnInfo, table *catalog.TableInfo, _ *provisioning.Workspace) error {
+func (d DataSourceVisitor) VisitColumn(ctx context.Context, column *catalog.ColumnInfo, table *catalog.TableInfo, _ *provisioning.Workspace) error {
 	uniqueId := createTableUniqueId(table.MetastoreId, table.FullName, column.Name)
 	parentId := createUniqueId(table.MetastoreId, table.FullName)
 
@@ -268,16 +309,20 @@ func (d DataSourceVisitor) VisitColumn(_ context.Context, column *catalog.Column
 		d.syncer.functionUsedAsMaskOrFilter.Add(createUniqueId(table.MetastoreId, column.Mask.FunctionName))
 	}
 
-	return d.dataSourceHandler.AddDataObjects(&ds.DataObject{
+	do := &ds.DataObject{
 		Name:             column.Name,
 		ExternalId:       uniqueId,
 		ParentExternalId: parentId,
 		Description:      column.Comment,
 		FullName:         uniqueId,
 		Type:             ds.Column,
-		Tags:             d.tagHandler.GetTag(table.FullName + "." + column.Name),
+		Tags:             d.tagHandler.GetTag(ctx, table.FullName+"."+column.Name),
 		DataType:         ptr.String(column.TypeName.String()),
-	})
+	}
+
+	d.lineageHandler.AddDataObject(do)
+
+	return d.dataSourceHandler.AddDataObjects(do)
 }
 
 func (d DataSourceVisitor) VisitFunction(_ context.Context, function *catalog.FunctionInfo, schema *catalog.SchemaInfo, _ *provisioning.Workspace) error {
@@ -298,5 +343,6 @@ func (d DataSourceVisitor) VisitFunction(_ context.Context, function *catalog.Fu
 		Description:      function.Comment,
 		FullName:         uniqueId,
 		Type:             constants.FunctionType,
+		Tags:             functionAttributeTags(function),
 	})
 }
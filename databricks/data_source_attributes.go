@@ -0,0 +1,192 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	constants2 "github.com/raito-io/cli/base/constants"
+	"github.com/raito-io/cli/base/tag"
+	"github.com/raito-io/cli/base/util/config"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/repo"
+	types2 "cli-plugin-databricks/databricks/repo/types"
+	"cli-plugin-databricks/databricks/types"
+)
+
+const (
+	attributeTagCreatedAt       = "databricks_created_at"
+	attributeTagCreatedBy       = "databricks_created_by"
+	attributeTagUpdatedAt       = "databricks_updated_at"
+	attributeTagUpdatedBy       = "databricks_updated_by"
+	attributeTagFormat          = "databricks_format"
+	attributeTagStorageLocation = "databricks_storage_location"
+	attributeTagNumRows         = "databricks_num_rows"
+	attributeTagSizeInBytes     = "databricks_size_in_bytes"
+	attributeTagNumFiles        = "databricks_num_files"
+
+	tablePropertyNumRows = "spark.sql.statistics.numRows"
+)
+
+// securableAttributes contains the metadata that is available on all Unity Catalog securables
+type securableAttributes struct {
+	owner     string
+	createdAt int64
+	createdBy string
+	updatedAt int64
+	updatedBy string
+}
+
+// attributeTags returns the attributes as tags. The owner is exported as Raito owner tag.
+func (a securableAttributes) attributeTags() []*tag.Tag {
+	var tags []*tag.Tag
+
+	tags = appendAttributeTag(tags, constants2.RaitoOwnerTagKey, a.owner)
+	tags = appendAttributeTag(tags, attributeTagCreatedAt, formatTimestamp(a.createdAt))
+	tags = appendAttributeTag(tags, attributeTagCreatedBy, a.createdBy)
+	tags = appendAttributeTag(tags, attributeTagUpdatedAt, formatTimestamp(a.updatedAt))
+	tags = appendAttributeTag(tags, attributeTagUpdatedBy, a.updatedBy)
+
+	return tags
+}
+
+func catalogAttributeTags(c *catalog.CatalogInfo) []*tag.Tag {
+	tags := securableAttributes{owner: c.Owner, createdAt: c.CreatedAt, createdBy: c.CreatedBy, updatedAt: c.UpdatedAt, updatedBy: c.UpdatedBy}.attributeTags()
+
+	return appendAttributeTag(tags, attributeTagStorageLocation, c.StorageRoot)
+}
+
+func schemaAttributeTags(schema *catalog.SchemaInfo) []*tag.Tag {
+	tags := securableAttributes{owner: schema.Owner, createdAt: schema.CreatedAt, createdBy: schema.CreatedBy, updatedAt: schema.UpdatedAt, updatedBy: schema.UpdatedBy}.attributeTags()
+
+	return appendAttributeTag(tags, attributeTagStorageLocation, schema.StorageRoot)
+}
+
+func tableAttributeTags(table *catalog.TableInfo) []*tag.Tag {
+	tags := securableAttributes{owner: table.Owner, createdAt: table.CreatedAt, createdBy: table.CreatedBy, updatedAt: table.UpdatedAt, updatedBy: table.UpdatedBy}.attributeTags()
+
+	tags = appendAttributeTag(tags, attributeTagFormat, string(table.DataSourceFormat))
+	tags = appendAttributeTag(tags, attributeTagStorageLocation, table.StorageLocation)
+
+	return appendAttributeTag(tags, attributeTagNumRows, table.Properties[tablePropertyNumRows])
+}
+
+func functionAttributeTags(function *catalog.FunctionInfo) []*tag.Tag {
+	return securableAttributes{owner: function.Owner, createdAt: function.CreatedAt, createdBy: function.CreatedBy, updatedAt: function.UpdatedAt, updatedBy: function.UpdatedBy}.attributeTags()
+}
+
+func appendAttributeTag(tags []*tag.Tag, key string, value string) []*tag.Tag {
+	if value == "" {
+		return tags
+	}
+
+	return append(tags, &tag.Tag{
+		Key:    key,
+		Value:  value,
+		Source: constants.TagSource,
+	})
+}
+
+func formatTimestamp(epochMillis int64) string {
+	if epochMillis == 0 {
+		return ""
+	}
+
+	return time.UnixMilli(epochMillis).UTC().Format(time.RFC3339)
+}
+
+// DataSourceTableDetailHandler loads the size of Delta tables with DESCRIBE DETAIL, if enabled and a warehouse is configured for the workspace
+type DataSourceTableDetailHandler struct {
+	configMap            *config.ConfigMap
+	warehouseIdMap       map[string]string //workspace -> warehouse id
+	workspaceRepoFactory func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error)
+
+	enabled  bool
+	sqlRepos map[string]repo.WarehouseRepository // workspace -> warehouse repository
+}
+
+func NewDataSourceTableDetailHandler(configMap *config.ConfigMap, workspaceRepoFactory func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error)) (*DataSourceTableDetailHandler, error) {
+	handler := &DataSourceTableDetailHandler{
+		configMap:            configMap,
+		workspaceRepoFactory: workspaceRepoFactory,
+		enabled:              configMap.GetBoolWithDefault(constants.DatabricksTableDetails, false),
+		sqlRepos:             make(map[string]repo.WarehouseRepository),
+	}
+
+	if !handler.enabled {
+		return handler, nil
+	}
+
+	var warehouseIds []types.WarehouseDetails
+
+	if found, err := configMap.Unmarshal(constants.DatabricksSqlWarehouses, &warehouseIds); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", constants.DatabricksSqlWarehouses, err)
+	} else if !found {
+		logger.Warn("No warehouse id map found in config. Table details will not be loaded.")
+	}
+
+	handler.warehouseIdMap = make(map[string]string)
+	for _, details := range warehouseIds {
+		handler.warehouseIdMap[details.Workspace] = details.Warehouse
+	}
+
+	return handler, nil
+}
+
+// GetTableDetailTags returns the size and number of files of the Delta table as tags
+func (d *DataSourceTableDetailHandler) GetTableDetailTags(ctx context.Context, table *catalog.TableInfo, workspace *provisioning.Workspace) []*tag.Tag {
+	if !d.enabled || workspace == nil || table.DataSourceFormat != catalog.DataSourceFormatDelta || (table.TableType != catalog.TableTypeManaged && table.TableType != catalog.TableTypeExternal) {
+		return nil
+	}
+
+	sqlRepo, err := d.getSqlClient(workspace)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to get sql client to load details of table %q: %s", table.FullName, err.Error()))
+
+		return nil
+	}
+
+	if sqlRepo == nil {
+		return nil
+	}
+
+	detail, err := checkpointedResponse(ctx, "table-detail/"+table.FullName, func() (*types2.TableDetail, error) {
+		return sqlRepo.GetTableDetail(ctx, table.CatalogName, table.SchemaName, table.Name)
+	})
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to load details of table %q: %s", table.FullName, err.Error()))
+
+		return nil
+	}
+
+	var tags []*tag.Tag
+
+	if detail.SizeInBytes != nil {
+		tags = appendAttributeTag(tags, attributeTagSizeInBytes, strconv.FormatInt(*detail.SizeInBytes, 10))
+	}
+
+	if detail.NumFiles != nil {
+		tags = appendAttributeTag(tags, attributeTagNumFiles, strconv.FormatInt(*detail.NumFiles, 10))
+	}
+
+	return tags
+}
+
+func (d *DataSourceTableDetailHandler) getSqlClient(workspace *provisioning.Workspace) (repo.WarehouseRepository, error) {
+	if sqlRepo, found := d.sqlRepos[workspace.DeploymentName]; found {
+		return sqlRepo, nil
+	}
+
+	_, sqlRepo, err := getWorkspaceSqlClient(d.configMap, d.warehouseIdMap, d.workspaceRepoFactory, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	d.sqlRepos[workspace.DeploymentName] = sqlRepo
+
+	return sqlRepo, nil
+}
@@ -0,0 +1,98 @@
+package databricks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	"github.com/raito-io/cli/base/tag"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/repo"
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+func Test_tableAttributeTags(t *testing.T) {
+	tags := tableAttributeTags(&catalog.TableInfo{
+		Owner:            "data-platform",
+		CreatedAt:        1704067200000,
+		CreatedBy:        "creator@raito.io",
+		UpdatedAt:        1717200000000,
+		DataSourceFormat: catalog.DataSourceFormatDelta,
+		StorageLocation:  "s3://bucket/table",
+		Properties:       map[string]string{"spark.sql.statistics.numRows": "42", "delta.minReaderVersion": "1"},
+	})
+
+	assert.Equal(t, []*tag.Tag{
+		{Key: "raito_owner", Value: "data-platform", Source: constants.TagSource},
+		{Key: "databricks_created_at", Value: "2024-01-01T00:00:00Z", Source: constants.TagSource},
+		{Key: "databricks_created_by", Value: "creator@raito.io", Source: constants.TagSource},
+		{Key: "databricks_updated_at", Value: "2024-06-01T00:00:00Z", Source: constants.TagSource},
+		{Key: "databricks_format", Value: "DELTA", Source: constants.TagSource},
+		{Key: "databricks_storage_location", Value: "s3://bucket/table", Source: constants.TagSource},
+		{Key: "databricks_num_rows", Value: "42", Source: constants.TagSource},
+	}, tags)
+}
+
+func TestDataSourceTableDetailHandler_GetTableDetailTags(t *testing.T) {
+	// Given
+	workspaceRepoMock := newMockDataSourceWorkspaceRepository(t)
+	sqlRepoMock := repo.NewMockWarehouseRepository(t)
+
+	workspaceRepoMock.EXPECT().SqlWarehouseRepository("warehouseId").Return(sqlRepoMock).Once()
+	sqlRepoMock.EXPECT().GetTableDetail(mock.Anything, "catalog1", "schema1", "table1").Return(&types.TableDetail{SizeInBytes: ptr.Int64(1024), NumFiles: ptr.Int64(3)}, nil).Once()
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:     "AccountId",
+			constants.DatabricksUser:          "User",
+			constants.DatabricksPassword:      "Password",
+			constants.DatabricksPlatform:      "AWS",
+			constants.DatabricksSqlWarehouses: `[{"workspace": "test-deployment", "warehouse": "warehouseId"}]`,
+			constants.DatabricksTableDetails:  "true",
+		},
+	}
+
+	handler, err := NewDataSourceTableDetailHandler(configMap, func(repoCredentials *types.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error) {
+		if repoCredentials.Host == "https://test-deployment.cloud.databricks.com" {
+			return workspaceRepoMock, nil
+		}
+
+		return nil, errors.New("no workspace repository")
+	})
+	require.NoError(t, err)
+
+	workspace := &provisioning.Workspace{DeploymentName: "test-deployment"}
+
+	// When
+	tableTags := handler.GetTableDetailTags(context.Background(), &catalog.TableInfo{
+		Name:             "table1",
+		CatalogName:      "catalog1",
+		SchemaName:       "schema1",
+		FullName:         "catalog1.schema1.table1",
+		TableType:        catalog.TableTypeManaged,
+		DataSourceFormat: catalog.DataSourceFormatDelta,
+	}, workspace)
+
+	viewTags := handler.GetTableDetailTags(context.Background(), &catalog.TableInfo{
+		Name:        "view1",
+		CatalogName: "catalog1",
+		SchemaName:  "schema1",
+		FullName:    "catalog1.schema1.view1",
+		TableType:   catalog.TableTypeView,
+	}, workspace)
+
+	// Then
+	assert.Equal(t, []*tag.Tag{
+		{Key: "databricks_size_in_bytes", Value: "1024", Source: constants.TagSource},
+		{Key: "databricks_num_files", Value: "3", Source: constants.TagSource},
+	}, tableTags)
+	assert.Empty(t, viewTags)
+}
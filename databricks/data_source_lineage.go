@@ -0,0 +1,190 @@
+package databricks
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	ds "github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/golang-set/set"
+
+	"cli-plugin-databricks/databricks/constants"
+	types2 "cli-plugin-databricks/databricks/repo/types"
+	"cli-plugin-databricks/databricks/types"
+)
+
+const defaultLineageWindow = 30
+
+type LineageDataObject struct {
+	FullName string `json:"fullName"`
+	Type     string `json:"type"`
+}
+
+// Lineage is an upstream/downstream relation between two tables, or between two columns
+type Lineage struct {
+	Source LineageDataObject `json:"source"`
+	Target LineageDataObject `json:"target"`
+}
+
+type lineageKey struct {
+	source string
+	target string
+}
+
+// DataSourceLineageHandler collects the table and column lineage of each metastore from the lineage system tables.
+// Only lineage between data objects that are synced to Raito is written to the lineage file. The file is meant for other tooling, as Raito does not ingest lineage.
+type DataSourceLineageHandler struct {
+	configMap            *config.ConfigMap
+	warehouseIdMap       map[string]string //workspace -> warehouse id
+	workspaceRepoFactory func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error)
+
+	path            string
+	windowInDays    int
+	lineage         set.Set[lineageKey]
+	dataObjectTypes map[string]string // full name -> type of the synced tables and columns
+}
+
+func NewDataSourceLineageHandler(configMap *config.ConfigMap, workspaceRepoFactory func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error)) (*DataSourceLineageHandler, error) {
+	handler := &DataSourceLineageHandler{
+		configMap:            configMap,
+		workspaceRepoFactory: workspaceRepoFactory,
+		path:                 configMap.GetString(constants.DatabricksLineageFile),
+		windowInDays:         configMap.GetIntWithDefault(constants.DatabricksLineageWindow, defaultLineageWindow),
+		lineage:              set.NewSet[lineageKey](),
+		dataObjectTypes:      make(map[string]string),
+	}
+
+	if handler.path == "" {
+		return handler, nil
+	}
+
+	var warehouseIds []types.WarehouseDetails
+
+	if found, err := configMap.Unmarshal(constants.DatabricksSqlWarehouses, &warehouseIds); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", constants.DatabricksSqlWarehouses, err)
+	} else if !found {
+		logger.Warn("No warehouse id map found in config. Lineage will not be loaded.")
+	}
+
+	handler.warehouseIdMap = make(map[string]string)
+	for _, details := range warehouseIds {
+		handler.warehouseIdMap[details.Workspace] = details.Warehouse
+	}
+
+	return handler, nil
+}
+
+func (d *DataSourceLineageHandler) enabled() bool {
+	return d.path != ""
+}
+
+// LoadLineage loads the lineage of the metastore, using the warehouse of the first workspace of the metastore for which a warehouse is configured
+func (d *DataSourceLineageHandler) LoadLineage(ctx context.Context, metastore *catalog.MetastoreInfo, workspaces []*provisioning.Workspace) error {
+	if !d.enabled() {
+		return nil
+	}
+
+	idx := slices.IndexFunc(workspaces, func(workspace *provisioning.Workspace) bool {
+		_, found := d.warehouseIdMap[workspace.DeploymentName]
+
+		return found
+	})
+
+	if idx < 0 {
+		logger.Warn(fmt.Sprintf("No warehouse found for metastore %s. Will ignore lineage of metastore %q", metastore.MetastoreId, metastore.Name))
+
+		return nil
+	}
+
+	logger.Info(fmt.Sprintf("Loading lineage for metastore %s", metastore.Name))
+
+	_, sqlRepo, err := getWorkspaceSqlClient(d.configMap, d.warehouseIdMap, d.workspaceRepoFactory, workspaces[idx])
+	if err != nil {
+		return fmt.Errorf("get sql client: %w", err)
+	}
+
+	since := time.Now().AddDate(0, 0, -d.windowInDays)
+
+	err = sqlRepo.GetTableLineage(ctx, since, func(_ context.Context, sourceTable string, targetTable string) error {
+		d.lineage.Add(lineageKey{
+			source: createUniqueId(metastore.MetastoreId, sourceTable),
+			target: createUniqueId(metastore.MetastoreId, targetTable),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("get table lineage: %w", err)
+	}
+
+	err = sqlRepo.GetColumnLineage(ctx, since, func(_ context.Context, sourceTable string, sourceColumn string, targetTable string, targetColumn string) error {
+		d.lineage.Add(lineageKey{
+			source: createTableUniqueId(metastore.MetastoreId, sourceTable, sourceColumn),
+			target: createTableUniqueId(metastore.MetastoreId, targetTable, targetColumn),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("get column lineage: %w", err)
+	}
+
+	return nil
+}
+
+// AddDataObject registers a synced data object, so lineage on this data object can be exported
+func (d *DataSourceLineageHandler) AddDataObject(do *ds.DataObject) {
+	if !d.enabled() {
+		return
+	}
+
+	d.dataObjectTypes[do.FullName] = do.Type
+}
+
+// SaveLineage writes the lineage between synced data objects to the lineage file, if configured
+func (d *DataSourceLineageHandler) SaveLineage() error {
+	if !d.enabled() {
+		return nil
+	}
+
+	result := make([]Lineage, 0, len(d.lineage))
+
+	for key := range d.lineage {
+		sourceType, sourceFound := d.dataObjectTypes[key.source]
+		targetType, targetFound := d.dataObjectTypes[key.target]
+
+		if !sourceFound || !targetFound {
+			continue
+		}
+
+		result = append(result, Lineage{
+			Source: LineageDataObject{FullName: key.source, Type: sourceType},
+			Target: LineageDataObject{FullName: key.target, Type: targetType},
+		})
+	}
+
+	slices.SortFunc(result, func(a, b Lineage) int {
+		return cmp.Or(cmp.Compare(a.Source.FullName, b.Source.FullName), cmp.Compare(a.Target.FullName, b.Target.FullName))
+	})
+
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal lineage: %w", err)
+	}
+
+	err = os.WriteFile(d.path, content, 0600)
+	if err != nil {
+		return fmt.Errorf("write lineage file %q: %w", d.path, err)
+	}
+
+	logger.Info(fmt.Sprintf("Written %d lineage relations to %s", len(result), d.path))
+
+	return nil
+}
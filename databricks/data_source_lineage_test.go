@@ -0,0 +1,102 @@
+package databricks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	ds "github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/repo"
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+func TestDataSourceLineageHandler_LoadLineage(t *testing.T) {
+	// Given
+	lineageFile := filepath.Join(t.TempDir(), "lineage.json")
+
+	workspaceRepoMock := newMockDataSourceWorkspaceRepository(t)
+	sqlRepoMock := repo.NewMockWarehouseRepository(t)
+
+	workspaceRepoMock.EXPECT().SqlWarehouseRepository("warehouseId").Return(sqlRepoMock).Once()
+
+	sqlRepoMock.EXPECT().GetTableLineage(mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything).RunAndReturn(func(ctx context.Context, since time.Time, f func(context.Context, string, string) error) error {
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), since, time.Minute)
+
+		require.NoError(t, f(ctx, "catalog1.schema1.table1", "catalog1.schema1.table2"))
+		require.NoError(t, f(ctx, "catalog1.schema1.table2", "catalog2.schema1.excluded"))
+
+		return nil
+	}).Once()
+	sqlRepoMock.EXPECT().GetColumnLineage(mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything).RunAndReturn(func(ctx context.Context, _ time.Time, f func(context.Context, string, string, string, string) error) error {
+		require.NoError(t, f(ctx, "catalog1.schema1.table1", "column1", "catalog1.schema1.table2", "column2"))
+
+		return nil
+	}).Once()
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:     "AccountId",
+			constants.DatabricksUser:          "User",
+			constants.DatabricksPassword:      "Password",
+			constants.DatabricksPlatform:      "AWS",
+			constants.DatabricksSqlWarehouses: `[{"workspace": "test-deployment", "warehouse": "warehouseId"}]`,
+			constants.DatabricksLineageFile:   lineageFile,
+			constants.DatabricksLineageWindow: "7",
+		},
+	}
+
+	handler, err := NewDataSourceLineageHandler(configMap, func(repoCredentials *types.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error) {
+		if repoCredentials.Host == "https://test-deployment.cloud.databricks.com" {
+			return workspaceRepoMock, nil
+		}
+
+		return nil, errors.New("no workspace repository")
+	})
+	require.NoError(t, err)
+
+	// When
+	err = handler.LoadLineage(context.Background(), &catalog.MetastoreInfo{MetastoreId: "metastore1", Name: "metastore"}, []*provisioning.Workspace{
+		{DeploymentName: "other-deployment"},
+		{DeploymentName: "test-deployment"},
+	})
+	require.NoError(t, err)
+
+	handler.AddDataObject(&ds.DataObject{FullName: "metastore1.catalog1.schema1.table1", Type: ds.Table})
+	handler.AddDataObject(&ds.DataObject{FullName: "metastore1.catalog1.schema1.table2", Type: ds.View})
+	handler.AddDataObject(&ds.DataObject{FullName: "metastore1.catalog1.schema1.table1.column1", Type: ds.Column})
+	handler.AddDataObject(&ds.DataObject{FullName: "metastore1.catalog1.schema1.table2.column2", Type: ds.Column})
+
+	err = handler.SaveLineage()
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(lineageFile)
+	require.NoError(t, err)
+
+	var lineage []Lineage
+	require.NoError(t, json.Unmarshal(content, &lineage))
+
+	assert.Equal(t, []Lineage{
+		{
+			Source: LineageDataObject{FullName: "metastore1.catalog1.schema1.table1", Type: ds.Table},
+			Target: LineageDataObject{FullName: "metastore1.catalog1.schema1.table2", Type: ds.View},
+		},
+		{
+			Source: LineageDataObject{FullName: "metastore1.catalog1.schema1.table1.column1", Type: ds.Column},
+			Target: LineageDataObject{FullName: "metastore1.catalog1.schema1.table2.column2", Type: ds.Column},
+		},
+	}, lineage)
+}
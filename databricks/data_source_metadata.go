@@ -16,10 +16,13 @@ var databricks_metadata = ds.MetaData{
 	DataObjectTypes: []*ds.DataObjectType{
 		{
 			// Account
-			Name:        ds.Datasource,
-			Type:        ds.Datasource,
-			Permissions: []*ds.DataObjectTypePermission{},
-			Children:    []string{constants.MetastoreType, constants.WorkspaceType},
+			Name: ds.Datasource,
+			Type: ds.Datasource,
+			Permissions: []*ds.DataObjectTypePermission{
+				&AccountAdminPermission,
+				&MarketplaceAdminPermission,
+			},
+			Children: []string{constants.MetastoreType, constants.WorkspaceType},
 		},
 		{
 			Name: constants.WorkspaceType,
@@ -35,6 +38,10 @@ var databricks_metadata = ds.MetaData{
 					Permission:  "ADMIN",
 					Description: "Assigned to workspace with role ADMIN",
 				},
+				&WorkspaceAccessEntitlement,
+				&DatabricksSqlAccessEntitlement,
+				&AllowClusterCreateEntitlement,
+				&AllowInstancePoolCreateEntitlement,
 			},
 		},
 		{
@@ -54,6 +61,7 @@ var databricks_metadata = ds.MetaData{
 				&UseProviderPermission,
 				&UseRecipientPermission,
 				&UseSharePermission,
+				&MetastoreAdminPermission,
 			},
 			Children: []string{"catalog"},
 		},
@@ -220,7 +228,16 @@ var databricks_metadata = ds.MetaData{
 			CanBeCreated:                  true,
 			CanBeAssumed:                  false,
 			CanAssumeMultiple:             false,
-			AllowedWhoAccessProviderTypes: []string{access_provider.AclSet},
+			AllowedWhoAccessProviderTypes: []string{access_provider.AclSet, access_provider.Role},
+		},
+		{
+			Type:                          access_provider.Role,
+			Label:                         "Account Group",
+			IsNamedEntity:                 true,
+			CanBeCreated:                  true,
+			CanBeAssumed:                  true,
+			CanAssumeMultiple:             true,
+			AllowedWhoAccessProviderTypes: []string{access_provider.Role},
 		},
 	},
 	MaskingMetadata: &ds.MaskingMetadata{
@@ -241,13 +258,71 @@ var databricks_metadata = ds.MetaData{
 			},
 		},
 		DefaultMaskExternalName: masks.DefaultMaskId,
-		ApplicableTypes:         []string{ds.Table, ds.View, constants.MaterializedViewType},
+		ApplicableTypes:         []string{ds.Table, ds.View, constants.MaterializedViewType, constants.CatalogType, ds.Schema},
 	},
 	FilterMetadata: &ds.FilterMetadata{
-		ApplicableTypes: []string{ds.Table, ds.View, constants.MaterializedViewType},
+		ApplicableTypes: []string{ds.Table, ds.View, constants.MaterializedViewType, constants.CatalogType, ds.Schema},
 	},
 }
 
+// Entitlements
+// WorkspaceAccessEntitlement as defined on https://docs.databricks.com/en/security/auth/entitlements.html
+var WorkspaceAccessEntitlement = ds.DataObjectTypePermission{
+	Permission:             "WORKSPACE ACCESS",
+	Description:            "Allows a user or service principal to access the Databricks workspace.",
+	UsageGlobalPermissions: []string{ds.Read},
+	CannotBeGranted:        false,
+}
+
+// DatabricksSqlAccessEntitlement as defined on https://docs.databricks.com/en/security/auth/entitlements.html
+var DatabricksSqlAccessEntitlement = ds.DataObjectTypePermission{
+	Permission:             "DATABRICKS SQL ACCESS",
+	Description:            "Allows a user or service principal to access Databricks SQL.",
+	UsageGlobalPermissions: []string{ds.Read},
+	CannotBeGranted:        false,
+}
+
+// AllowClusterCreateEntitlement as defined on https://docs.databricks.com/en/security/auth/entitlements.html
+var AllowClusterCreateEntitlement = ds.DataObjectTypePermission{
+	Permission:             "ALLOW CLUSTER CREATE",
+	Description:            "Allows a user or service principal to create unrestricted clusters.",
+	UsageGlobalPermissions: []string{ds.Admin},
+	CannotBeGranted:        false,
+}
+
+// AllowInstancePoolCreateEntitlement as defined on https://docs.databricks.com/en/security/auth/entitlements.html
+var AllowInstancePoolCreateEntitlement = ds.DataObjectTypePermission{
+	Permission:             "ALLOW INSTANCE POOL CREATE",
+	Description:            "Allows a user or service principal to create instance pools.",
+	UsageGlobalPermissions: []string{ds.Admin},
+	CannotBeGranted:        false,
+}
+
+// Account roles
+// AccountAdminPermission as defined on https://docs.databricks.com/en/admin/users-groups/users.html#assign-account-admin-roles-to-a-user
+var AccountAdminPermission = ds.DataObjectTypePermission{
+	Permission:             "ACCOUNT ADMIN",
+	Description:            "Allows a user, service principal or group to manage the Databricks account, including workspaces, metastores and identities. Only granted if databricks-manage-account-roles is enabled.",
+	UsageGlobalPermissions: []string{ds.Admin},
+	CannotBeGranted:        false,
+}
+
+// MarketplaceAdminPermission as defined on https://docs.databricks.com/en/marketplace/get-started-provider.html#assign-the-marketplace-admin-role
+var MarketplaceAdminPermission = ds.DataObjectTypePermission{
+	Permission:             "MARKETPLACE ADMIN",
+	Description:            "Allows a user, service principal or group to manage the Databricks Marketplace listings and provider profile of the account. Only granted if databricks-manage-account-roles is enabled.",
+	UsageGlobalPermissions: []string{ds.Admin},
+	CannotBeGranted:        false,
+}
+
+// MetastoreAdminPermission as defined on https://docs.databricks.com/en/data-governance/unity-catalog/manage-privileges/admin-privileges.html#metastore-admins
+var MetastoreAdminPermission = ds.DataObjectTypePermission{
+	Permission:             "METASTORE ADMIN",
+	Description:            "The owner of the metastore, with all privileges on the metastore and the ability to transfer ownership of its securables. Can only be granted to one principal. Only granted if databricks-manage-account-roles is enabled.",
+	UsageGlobalPermissions: []string{ds.Admin},
+	CannotBeGranted:        false,
+}
+
 // Permissions
 // AllPrivilegesPermission as defined on https://docs.databricks.com/en/data-governance/unity-catalog/manage-privileges/privileges.html#all-privileges
 var AllPrivilegesPermission = ds.DataObjectTypePermission{
@@ -2,7 +2,12 @@ package databricks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/databricks/databricks-sdk-go/service/catalog"
 	"github.com/databricks/databricks-sdk-go/service/iam"
@@ -17,20 +22,39 @@ import (
 	"cli-plugin-databricks/databricks/utils"
 )
 
+type tagLoadingStrategy string
+
+const (
+	tagLoadingWarehouse tagLoadingStrategy = "warehouse"
+	tagLoadingRest      tagLoadingStrategy = "rest"
+)
+
 type DataSourceTagHandler struct {
 	configMap            *config.ConfigMap
 	warehouseIdMap       map[string]string //workspace -> warehouse id
 	workspaceRepoFactory func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error)
+	tagLoading           tagLoadingStrategy
 
-	tagCache map[string][]*tag.Tag
+	tagCache       map[string][]*tag.Tag
+	tagAssignments *entityTagCache               // Tag assignments loaded through the REST API, shared with the data object filter
+	metastoreId    string                        // Metastore of the catalog of which the tags are loaded
+	restRepo       dataSourceWorkspaceRepository // Set if the tags of the current catalog are loaded lazily through the REST API
+
+	exportTags map[types.TagKey]string // Raito tags to apply, keyed on data object full name and tag key
+	ownedTags  *types.OwnedTagState
 }
 
-func NewDataSourceTagHandler(configMap *config.ConfigMap, workspaceRepoFactory func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error)) (*DataSourceTagHandler, error) {
+func NewDataSourceTagHandler(configMap *config.ConfigMap, tagAssignments *entityTagCache, workspaceRepoFactory func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error)) (*DataSourceTagHandler, error) {
+	tagLoading := tagLoadingStrategy(configMap.GetStringWithDefault(constants.DatabricksTagLoading, string(tagLoadingWarehouse)))
+	if tagLoading != tagLoadingWarehouse && tagLoading != tagLoadingRest {
+		return nil, fmt.Errorf("unsupported tag loading strategy %q, expected %q or %q", tagLoading, tagLoadingWarehouse, tagLoadingRest)
+	}
+
 	var warehouseIds []types.WarehouseDetails
 
 	if found, err := configMap.Unmarshal(constants.DatabricksSqlWarehouses, &warehouseIds); err != nil {
 		return nil, fmt.Errorf("unmarshal %s: %w", constants.DatabricksSqlWarehouses, err)
-	} else if !found {
+	} else if !found && tagLoading == tagLoadingWarehouse {
 		logger.Warn("No warehouse id map found in config. Tags will not be loaded.")
 	}
 
@@ -39,15 +63,70 @@ func NewDataSourceTagHandler(configMap *config.ConfigMap, workspaceRepoFactory f
 		warehouseIdMap[details.Workspace] = details.Warehouse
 	}
 
-	return &DataSourceTagHandler{
+	handler := &DataSourceTagHandler{
 		configMap:            configMap,
 		warehouseIdMap:       warehouseIdMap,
 		workspaceRepoFactory: workspaceRepoFactory,
+		tagLoading:           tagLoading,
 		tagCache:             make(map[string][]*tag.Tag),
-	}, nil
+		tagAssignments:       tagAssignments,
+	}
+
+	if exportFile := configMap.GetString(constants.DatabricksTagExportFile); exportFile != "" {
+		stateFile := configMap.GetString(constants.DatabricksTagExportStateFile)
+		if stateFile == "" {
+			return nil, fmt.Errorf("%s is required when %s is set", constants.DatabricksTagExportStateFile, constants.DatabricksTagExportFile)
+		}
+
+		exportTags, err := loadTagExportFile(exportFile)
+		if err != nil {
+			return nil, err
+		}
+
+		ownedTags, err := types.LoadOwnedTagState(stateFile)
+		if err != nil {
+			return nil, err
+		}
+
+		handler.exportTags = exportTags
+		handler.ownedTags = ownedTags
+	}
+
+	return handler, nil
+}
+
+// loadTagExportFile reads the Raito tags to apply. Tags originating from Databricks itself are ignored.
+func loadTagExportFile(path string) (map[types.TagKey]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tag export file %q: %w", path, err)
+	}
+
+	var tags []tag.TagImportObject
+
+	err = json.Unmarshal(content, &tags)
+	if err != nil {
+		return nil, fmt.Errorf("parse tag export file %q: %w", path, err)
+	}
+
+	result := make(map[types.TagKey]string)
+
+	for _, t := range tags {
+		if t.DataObjectFullName == nil || t.Source == constants.TagSource {
+			continue
+		}
+
+		result[types.TagKey{FullName: *t.DataObjectFullName, Key: t.Key}] = t.StringValue
+	}
+
+	return result, nil
 }
 
 func (d *DataSourceTagHandler) LoadTags(ctx context.Context, workspace *provisioning.Workspace, c *catalog.CatalogInfo) error {
+	if d.tagLoading == tagLoadingRest {
+		return d.loadRestTags(ctx, workspace, c)
+	}
+
 	if d.warehouseIdMap == nil {
 		return nil
 	}
@@ -55,6 +134,8 @@ func (d *DataSourceTagHandler) LoadTags(ctx context.Context, workspace *provisio
 	logger.Info(fmt.Sprintf("Loading tags for catalog %s", c.FullName))
 
 	d.tagCache = make(map[string][]*tag.Tag)
+	d.metastoreId = c.MetastoreId
+	d.restRepo = nil
 
 	workspaceRepo, sqlRepo, err := d.getSqlClient(workspace)
 	if err != nil {
@@ -67,38 +148,315 @@ func (d *DataSourceTagHandler) LoadTags(ctx context.Context, workspace *provisio
 		return nil
 	}
 
+	catalogTags, err := checkpointedResponse(ctx, "tags", func() ([]catalogTag, error) {
+		return d.queryCatalogTags(ctx, workspaceRepo, sqlRepo, c)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range catalogTags {
+		d.tagCache[t.FullName] = append(d.tagCache[t.FullName], &tag.Tag{
+			Key:    t.Key,
+			Value:  t.Value,
+			Source: constants.TagSource,
+		})
+	}
+
+	if d.ownedTags != nil {
+		d.syncTagsToTarget(ctx, sqlRepo, c)
+	}
+
+	return nil
+}
+
+type catalogTag struct {
+	FullName string `json:"fullName"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+}
+
+// queryCatalogTags queries the tags of all data objects within the catalog through the SQL warehouse
+func (d *DataSourceTagHandler) queryCatalogTags(ctx context.Context, workspaceRepo dataSourceWorkspaceRepository, sqlRepo repo.WarehouseRepository, c *catalog.CatalogInfo) ([]catalogTag, error) {
 	me, err := workspaceRepo.Me(ctx)
 	if err != nil {
-		return fmt.Errorf("get me: %w", err)
+		return nil, fmt.Errorf("get me: %w", err)
 	}
 
-	err = d.setRequiredPermissions(ctx, workspaceRepo, me, c)
+	granted, err := d.grantRequiredPermissions(ctx, workspaceRepo, me, c)
+	defer d.revokeGrantedPermissions(ctx, workspaceRepo, me, granted)
+
 	if err != nil {
-		return fmt.Errorf("set required permissions: %w", err)
+		return nil, fmt.Errorf("set required permissions: %w", err)
 	}
 
+	var catalogTags []catalogTag
+
 	err = sqlRepo.GetTags(ctx, c.Name, func(ctx context.Context, fullName string, key string, value string) error {
-		d.tagCache[fullName] = append(d.tagCache[fullName], &tag.Tag{
-			Key:    key,
-			Value:  value,
-			Source: constants.TagSource,
-		})
+		catalogTags = append(catalogTags, catalogTag{FullName: fullName, Key: key, Value: value})
 
 		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("get tags: %w", err)
+		return nil, fmt.Errorf("get tags: %w", err)
+	}
+
+	return catalogTags, nil
+}
+
+// loadRestTags prepares the lazy loading of the tags within the catalog through the entity tag assignments API.
+// No SQL warehouse is required, except to apply Raito tags.
+func (d *DataSourceTagHandler) loadRestTags(ctx context.Context, workspace *provisioning.Workspace, c *catalog.CatalogInfo) error {
+	d.tagCache = make(map[string][]*tag.Tag)
+	d.metastoreId = c.MetastoreId
+	d.restRepo = nil
+
+	workspaceRepo, sqlRepo, err := d.getSqlClient(workspace)
+	if err != nil {
+		return fmt.Errorf("get workspace client: %w", err)
+	}
+
+	d.restRepo = workspaceRepo
+
+	if d.ownedTags != nil {
+		if sqlRepo == nil {
+			logger.Warn(fmt.Sprintf("No warehouse found for metastore %s. Raito tags will not be applied on catalog %q", c.MetastoreId, c.Name))
+		} else {
+			d.syncTagsToTarget(ctx, sqlRepo, c)
+		}
 	}
 
 	return nil
 }
 
-func (d *DataSourceTagHandler) GetTag(fullName string) []*tag.Tag {
+// GetTag returns the Databricks tags of the data object. Tags owned by Raito are not included.
+func (d *DataSourceTagHandler) GetTag(ctx context.Context, fullName string) []*tag.Tag {
+	tags := d.entityTags(ctx, fullName)
+
+	if d.ownedTags == nil {
+		return tags
+	}
+
+	var result []*tag.Tag
+
+	for _, t := range tags {
+		if _, owned := d.ownedTags.Get(types.TagKey{FullName: createUniqueId(d.metastoreId, fullName), Key: t.Key}); !owned {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// LoadTableTags loads the tags of the table and its columns in one batch, if tags are loaded through the entity tag assignments API
+func (d *DataSourceTagHandler) LoadTableTags(ctx context.Context, table *catalog.TableInfo) {
+	if d.restRepo == nil {
+		return
+	}
+
+	fullNames := slices.DeleteFunc(tableTagBatch(table), func(fullName string) bool {
+		_, found := d.tagCache[fullName]
+
+		return found
+	})
+
+	d.cacheEntityTags(d.tagAssignments.load(ctx, d.restRepo, d.metastoreId, fullNames))
+}
+
+// entityTags returns the current tags of the data object (full name without metastore)
+func (d *DataSourceTagHandler) entityTags(ctx context.Context, fullName string) []*tag.Tag {
+	if tags, found := d.tagCache[fullName]; found || d.restRepo == nil {
+		return tags
+	}
+
+	d.cacheEntityTags(d.tagAssignments.load(ctx, d.restRepo, d.metastoreId, []string{fullName}))
+
 	return d.tagCache[fullName]
 }
 
+func (d *DataSourceTagHandler) cacheEntityTags(results map[string]entityTagResult) {
+	for fullName, result := range results {
+		if result.Err != nil {
+			logger.Warn(fmt.Sprintf("Failed to load tags of %q: %s", fullName, result.Err.Error()))
+		}
+
+		tags := make([]*tag.Tag, 0, len(result.Assignments))
+
+		for _, assignment := range result.Assignments {
+			tags = append(tags, &tag.Tag{
+				Key:    assignment.TagKey,
+				Value:  assignment.TagValue,
+				Source: constants.TagSource,
+			})
+		}
+
+		d.tagCache[fullName] = tags
+	}
+}
+
+func tagEntityType(fullName string) string {
+	switch strings.Count(fullName, ".") {
+	case 0:
+		return "catalogs"
+	case 1:
+		return "schemas"
+	case 2:
+		return "tables"
+	default:
+		return "columns"
+	}
+}
+
+// syncTagsToTarget applies the Raito tags within the catalog and removes the Raito tags that are no longer required.
+// Only tags owned by Raito are updated or removed.
+func (d *DataSourceTagHandler) syncTagsToTarget(ctx context.Context, sqlRepo repo.WarehouseRepository, c *catalog.CatalogInfo) {
+	catalogId := createUniqueId(c.MetastoreId, c.Name)
+	inCatalog := func(fullName string) bool {
+		return fullName == catalogId || strings.HasPrefix(fullName, catalogId+".")
+	}
+
+	toSet := make(map[string]map[string]string) // data object full name -> tag key -> value
+	toUnset := make(map[string][]string)        // data object full name -> tag keys
+
+	for key, value := range d.exportTags {
+		if !inCatalog(key.FullName) {
+			continue
+		}
+
+		currentValue, found := d.currentTagValue(ctx, key)
+		ownedValue, owned := d.ownedTags.Get(key)
+
+		switch {
+		case found && !owned:
+			if currentValue != value {
+				logger.Warn(fmt.Sprintf("Tag %q on %q is not set by Raito and will not be overwritten", key.Key, key.FullName))
+			}
+
+			continue
+		case found && currentValue != ownedValue:
+			logger.Warn(fmt.Sprintf("Tag %q on %q is changed outside Raito and will not be overwritten", key.Key, key.FullName))
+			d.ownedTags.Release(key, value)
+
+			continue
+		case found && currentValue == value:
+			continue
+		case !found && owned:
+			logger.Warn(fmt.Sprintf("Tag %q on %q is removed outside Raito and will not be applied again", key.Key, key.FullName))
+			d.ownedTags.Release(key, value)
+
+			continue
+		case !found && d.ownedTags.IsReleased(key, value):
+			continue
+		}
+
+		if _, ok := toSet[key.FullName]; !ok {
+			toSet[key.FullName] = make(map[string]string)
+		}
+
+		toSet[key.FullName][key.Key] = value
+	}
+
+	// Released tags are forgotten once Raito exports another value or no longer exports the tag
+	for _, key := range d.ownedTags.ReleasedKeys(inCatalog) {
+		if value, required := d.exportTags[key]; !required || !d.ownedTags.IsReleased(key, value) {
+			d.ownedTags.Delete(key)
+		}
+	}
+
+	for _, key := range d.ownedTags.Keys(inCatalog) {
+		if _, required := d.exportTags[key]; required {
+			continue
+		}
+
+		ownedValue, _ := d.ownedTags.Get(key)
+
+		if currentValue, found := d.currentTagValue(ctx, key); found && currentValue == ownedValue {
+			toUnset[key.FullName] = append(toUnset[key.FullName], key.Key)
+		} else {
+			// The tag is removed or changed outside Raito
+			d.ownedTags.Delete(key)
+		}
+	}
+
+	for _, fullName := range slices.Sorted(maps.Keys(toSet)) {
+		_, name := getMetastoreAndFullnameOfUniqueId(fullName)
+
+		err := sqlRepo.SetTags(ctx, name, toSet[fullName])
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to set tags on %q: %s", fullName, err.Error()))
+
+			continue
+		}
+
+		for key, value := range toSet[fullName] {
+			d.ownedTags.Set(types.TagKey{FullName: fullName, Key: key}, value)
+			d.removeCachedTag(name, key)
+			d.tagCache[name] = append(d.tagCache[name], &tag.Tag{Key: key, Value: value, Source: constants.TagSource})
+		}
+
+		d.invalidateTagAssignments(name)
+	}
+
+	for _, fullName := range slices.Sorted(maps.Keys(toUnset)) {
+		_, name := getMetastoreAndFullnameOfUniqueId(fullName)
+
+		err := sqlRepo.UnsetTags(ctx, name, toUnset[fullName])
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to unset tags on %q: %s", fullName, err.Error()))
+
+			continue
+		}
+
+		for _, key := range toUnset[fullName] {
+			d.ownedTags.Delete(types.TagKey{FullName: fullName, Key: key})
+			d.removeCachedTag(name, key)
+		}
+
+		d.invalidateTagAssignments(name)
+	}
+}
+
+func (d *DataSourceTagHandler) removeCachedTag(fullName string, key string) {
+	d.tagCache[fullName] = slices.DeleteFunc(d.tagCache[fullName], func(t *tag.Tag) bool {
+		return t.Key == key
+	})
+}
+
+// invalidateTagAssignments removes the changed tags of the data object from the shared cache, so the data object filter loads the new tags
+func (d *DataSourceTagHandler) invalidateTagAssignments(fullName string) {
+	if d.tagAssignments != nil {
+		d.tagAssignments.invalidate(d.metastoreId, fullName)
+	}
+}
+
+func (d *DataSourceTagHandler) currentTagValue(ctx context.Context, key types.TagKey) (string, bool) {
+	_, name := getMetastoreAndFullnameOfUniqueId(key.FullName)
+
+	for _, t := range d.entityTags(ctx, name) {
+		if t.Key == key.Key {
+			return t.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// SaveOwnedTags persists the tags owned by Raito, if tags are exported
+func (d *DataSourceTagHandler) SaveOwnedTags() error {
+	if d.ownedTags == nil {
+		return nil
+	}
+
+	return d.ownedTags.Save()
+}
+
 func (d *DataSourceTagHandler) getSqlClient(workspace *provisioning.Workspace) (dataSourceWorkspaceRepository, repo.WarehouseRepository, error) {
-	pltfrm, _, repoCredentials, err := utils.GetAndValidateParameters(d.configMap)
+	return getWorkspaceSqlClient(d.configMap, d.warehouseIdMap, d.workspaceRepoFactory, workspace)
+}
+
+// getWorkspaceSqlClient creates the workspace repository and, if a warehouse is configured for the workspace, the warehouse repository
+func getWorkspaceSqlClient(configMap *config.ConfigMap, warehouseIdMap map[string]string, workspaceRepoFactory func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error), workspace *provisioning.Workspace) (dataSourceWorkspaceRepository, repo.WarehouseRepository, error) {
+	pltfrm, _, repoCredentials, err := utils.GetAndValidateParameters(configMap)
 	if err != nil {
 		return nil, nil, fmt.Errorf("get credentials: %w", err)
 	}
@@ -108,34 +466,79 @@ func (d *DataSourceTagHandler) getSqlClient(workspace *provisioning.Workspace) (
 		return nil, nil, fmt.Errorf("initialize workspace credentials: %w", err)
 	}
 
-	workspaceRepo, err := d.workspaceRepoFactory(workspaceCredentials, workspace.WorkspaceId)
+	workspaceRepo, err := workspaceRepoFactory(workspaceCredentials, workspace.WorkspaceId)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create workspace repo: %w", err)
 	}
 
-	if warehouseId, found := d.warehouseIdMap[workspace.DeploymentName]; found {
+	if warehouseId, found := warehouseIdMap[workspace.DeploymentName]; found {
 		return workspaceRepo, workspaceRepo.SqlWarehouseRepository(warehouseId), nil
 	}
 
 	return workspaceRepo, nil, nil
 }
 
-func (d *DataSourceTagHandler) setRequiredPermissions(ctx context.Context, workspaceRepo dataSourceWorkspaceRepository, me *iam.User, c *catalog.CatalogInfo) error {
-	err := workspaceRepo.SetPermissionsOnResource(ctx, catalog.SecurableTypeCatalog, c.FullName, catalog.PermissionsChange{
-		Add:       []catalog.Privilege{catalog.PrivilegeUseCatalog},
-		Principal: me.UserName,
-	})
-	if err != nil {
-		return fmt.Errorf("use catalog permission: %w", err)
+type temporaryPermission struct {
+	securableType catalog.SecurableType
+	fullName      string
+	privileges    []catalog.Privilege
+}
+
+// grantRequiredPermissions grants the privileges required to read the information_schema tag tables, if not yet granted.
+// The granted privileges are returned so they can be revoked once the tags are loaded.
+func (d *DataSourceTagHandler) grantRequiredPermissions(ctx context.Context, workspaceRepo dataSourceWorkspaceRepository, me *iam.User, c *catalog.CatalogInfo) ([]temporaryPermission, error) {
+	required := []temporaryPermission{
+		{securableType: catalog.SecurableTypeCatalog, fullName: c.FullName, privileges: []catalog.Privilege{catalog.PrivilegeUseCatalog}},
+		{securableType: catalog.SecurableTypeSchema, fullName: fmt.Sprintf("%s.%s", c.FullName, "information_schema"), privileges: []catalog.Privilege{catalog.PrivilegeSelect, catalog.PrivilegeUseSchema}},
 	}
 
-	err = workspaceRepo.SetPermissionsOnResource(ctx, catalog.SecurableTypeSchema, fmt.Sprintf("%s.%s", c.FullName, "information_schema"), catalog.PermissionsChange{
-		Add:       []catalog.Privilege{catalog.PrivilegeSelect, catalog.PrivilegeUseSchema},
-		Principal: me.UserName,
-	})
-	if err != nil {
-		return fmt.Errorf("use schema permission: %w", err)
+	var granted []temporaryPermission
+
+	for _, permission := range required {
+		current, err := workspaceRepo.GetPermissionsOnResource(ctx, permission.securableType, permission.fullName)
+		if err != nil {
+			return granted, fmt.Errorf("get permissions on %s %q: %w", permission.securableType, permission.fullName, err)
+		}
+
+		missing := slices.Clone(permission.privileges)
+
+		for _, assignment := range current.PrivilegeAssignments {
+			if assignment.Principal == me.UserName {
+				missing = slices.DeleteFunc(missing, func(privilege catalog.Privilege) bool {
+					return slices.Contains(assignment.Privileges, privilege)
+				})
+			}
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Temporarily granting %v on %s %q to %q to load tags", missing, permission.securableType, permission.fullName, me.UserName))
+
+		err = workspaceRepo.SetPermissionsOnResource(ctx, permission.securableType, permission.fullName, catalog.PermissionsChange{
+			Add:       missing,
+			Principal: me.UserName,
+		})
+		if err != nil {
+			return granted, fmt.Errorf("grant %v on %s %q: %w", missing, permission.securableType, permission.fullName, err)
+		}
+
+		granted = append(granted, temporaryPermission{securableType: permission.securableType, fullName: permission.fullName, privileges: missing})
 	}
 
-	return nil
+	return granted, nil
+}
+
+func (d *DataSourceTagHandler) revokeGrantedPermissions(ctx context.Context, workspaceRepo dataSourceWorkspaceRepository, me *iam.User, granted []temporaryPermission) {
+	// Revoke in reverse order, so the catalog usage is revoked last
+	for _, permission := range slices.Backward(granted) {
+		err := workspaceRepo.SetPermissionsOnResource(ctx, permission.securableType, permission.fullName, catalog.PermissionsChange{
+			Remove:    permission.privileges,
+			Principal: me.UserName,
+		})
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to revoke temporary privileges %v on %s %q from %q. Please revoke them manually: %s", permission.privileges, permission.securableType, permission.fullName, me.UserName, err.Error()))
+		}
+	}
 }
@@ -3,6 +3,8 @@ package databricks
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
@@ -16,8 +18,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/filters"
 	"cli-plugin-databricks/databricks/repo"
 	"cli-plugin-databricks/databricks/repo/types"
+	types2 "cli-plugin-databricks/databricks/types"
 )
 
 func TestDataSourceTagHandler_LoadTags(t *testing.T) {
@@ -50,12 +54,27 @@ func TestDataSourceTagHandler_LoadTags(t *testing.T) {
 
 	workspaceRepoMock.EXPECT().SqlWarehouseRepository("warehouseId").Return(sqlRepoMock)
 	workspaceRepoMock.EXPECT().Me(mock.Anything).Return(&iam.User{UserName: "raito-user"}, nil).Once()
+	workspaceRepoMock.EXPECT().GetPermissionsOnResource(mock.Anything, catalog.SecurableTypeCatalog, "catalog1").Return(&catalog.PermissionsList{}, nil).Once()
+	workspaceRepoMock.EXPECT().GetPermissionsOnResource(mock.Anything, catalog.SecurableTypeSchema, "catalog1.information_schema").Return(&catalog.PermissionsList{
+		PrivilegeAssignments: []catalog.PrivilegeAssignment{
+			{Principal: "raito-user", Privileges: []catalog.Privilege{catalog.PrivilegeUseSchema}},
+			{Principal: "other-user", Privileges: []catalog.Privilege{catalog.PrivilegeSelect}},
+		},
+	}, nil).Once()
 	workspaceRepoMock.EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeCatalog, "catalog1", catalog.PermissionsChange{
 		Add:       []catalog.Privilege{"USE_CATALOG"},
 		Principal: "raito-user",
 	}).Return(nil).Once()
 	workspaceRepoMock.EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeSchema, "catalog1.information_schema", catalog.PermissionsChange{
-		Add:       []catalog.Privilege{"SELECT", "USE_SCHEMA"},
+		Add:       []catalog.Privilege{"SELECT"},
+		Principal: "raito-user",
+	}).Return(nil).Once()
+	workspaceRepoMock.EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeSchema, "catalog1.information_schema", catalog.PermissionsChange{
+		Remove:    []catalog.Privilege{"SELECT"},
+		Principal: "raito-user",
+	}).Return(nil).Once()
+	workspaceRepoMock.EXPECT().SetPermissionsOnResource(mock.Anything, catalog.SecurableTypeCatalog, "catalog1", catalog.PermissionsChange{
+		Remove:    []catalog.Privilege{"USE_CATALOG"},
 		Principal: "raito-user",
 	}).Return(nil).Once()
 
@@ -225,7 +244,227 @@ func TestDataSourceTagHandler_GetTag(t *testing.T) {
 			d := &DataSourceTagHandler{
 				tagCache: tt.fields.tagCache,
 			}
-			assert.Equalf(t, tt.want, d.GetTag(tt.args.fullName), "GetTag(%v)", tt.args.fullName)
+			assert.Equalf(t, tt.want, d.GetTag(context.Background(), tt.args.fullName), "GetTag(%v)", tt.args.fullName)
 		})
 	}
 }
+
+func TestDataSourceTagHandler_LoadTags_withTagExport(t *testing.T) {
+	// Given
+	c := catalog.CatalogInfo{
+		MetastoreId: "metastore1",
+		Name:        "catalog1",
+		FullName:    "catalog1",
+	}
+
+	stateFile := filepath.Join(t.TempDir(), "tags.json")
+	err := os.WriteFile(stateFile, []byte(`[
+		{"fullName": "metastore1.catalog1", "key": "tier", "value": "gold", "released": true},
+		{"fullName": "metastore1.catalog1.schema1", "key": "domain", "value": "sales"},
+		{"fullName": "metastore1.catalog1.schema1", "key": "old", "value": "x"},
+		{"fullName": "metastore1.catalog1.schema1.table1", "key": "pii", "value": "true"},
+		{"fullName": "metastore1.catalog2", "key": "pii", "value": "true"}
+	]`), 0600)
+	require.NoError(t, err)
+
+	ownedTags, err := types2.LoadOwnedTagState(stateFile)
+	require.NoError(t, err)
+
+	workspaceRepoMock := newMockDataSourceWorkspaceRepository(t)
+	sqlRepoMock := repo.NewMockWarehouseRepository(t)
+
+	sqlRepoMock.EXPECT().GetTags(mock.Anything, c.FullName, mock.Anything).RunAndReturn(func(ctx context.Context, s string, f func(context.Context, string, string, string) error) error {
+		require.NoError(t, f(ctx, "catalog1", "owner", "team"))
+		require.NoError(t, f(ctx, "catalog1.schema1", "old", "x"))
+		require.NoError(t, f(ctx, "catalog1.schema1.table1", "pii", "true"))
+
+		return nil
+	})
+	sqlRepoMock.EXPECT().SetTags(mock.Anything, "catalog1.schema1.table1", map[string]string{"pii": "false"}).Return(nil).Once()
+	sqlRepoMock.EXPECT().SetTags(mock.Anything, "catalog1.schema1.table1.column1", map[string]string{"classification": "confidential"}).Return(nil).Once()
+	sqlRepoMock.EXPECT().UnsetTags(mock.Anything, "catalog1.schema1", []string{"old"}).Return(nil).Once()
+
+	workspaceRepoMock.EXPECT().SqlWarehouseRepository("warehouseId").Return(sqlRepoMock)
+	workspaceRepoMock.EXPECT().Me(mock.Anything).Return(&iam.User{UserName: "raito-user"}, nil).Once()
+	workspaceRepoMock.EXPECT().GetPermissionsOnResource(mock.Anything, mock.Anything, mock.Anything).Return(&catalog.PermissionsList{
+		PrivilegeAssignments: []catalog.PrivilegeAssignment{
+			{Principal: "raito-user", Privileges: []catalog.Privilege{catalog.PrivilegeUseCatalog, catalog.PrivilegeSelect, catalog.PrivilegeUseSchema}},
+		},
+	}, nil).Twice()
+
+	dstg := DataSourceTagHandler{
+		tagCache: make(map[string][]*tag.Tag),
+		configMap: &config.ConfigMap{
+			Parameters: map[string]string{
+				constants.DatabricksAccountId: "AccountId",
+				constants.DatabricksUser:      "User",
+				constants.DatabricksPassword:  "Password",
+				constants.DatabricksPlatform:  "AWS",
+			},
+		},
+		workspaceRepoFactory: func(repoCredentials *types.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error) {
+			return workspaceRepoMock, nil
+		},
+		warehouseIdMap: map[string]string{"test-deployment": "warehouseId"},
+		exportTags: map[types2.TagKey]string{
+			{FullName: "metastore1.catalog1", Key: "owner"}:                                 "other-team",
+			{FullName: "metastore1.catalog1", Key: "tier"}:                                  "gold",
+			{FullName: "metastore1.catalog1.schema1", Key: "domain"}:                        "sales",
+			{FullName: "metastore1.catalog1.schema1.table1", Key: "pii"}:                    "false",
+			{FullName: "metastore1.catalog1.schema1.table1.column1", Key: "classification"}: "confidential",
+		},
+		ownedTags: ownedTags,
+	}
+
+	// When
+	err = dstg.LoadTags(context.Background(), &provisioning.Workspace{
+		WorkspaceName:  "workspaceId",
+		DeploymentName: "test-deployment",
+	}, &c)
+	require.NoError(t, err)
+
+	err = dstg.SaveOwnedTags()
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, []*tag.Tag{{Key: "owner", Value: "team", Source: constants.TagSource}}, dstg.GetTag(context.Background(), "catalog1"))
+	assert.Empty(t, dstg.GetTag(context.Background(), "catalog1.schema1"))
+	assert.Empty(t, dstg.GetTag(context.Background(), "catalog1.schema1.table1"))
+
+	content, err := os.ReadFile(stateFile)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[
+		{"fullName": "metastore1.catalog1", "key": "tier", "value": "gold", "released": true},
+		{"fullName": "metastore1.catalog1.schema1", "key": "domain", "value": "sales", "released": true},
+		{"fullName": "metastore1.catalog1.schema1.table1", "key": "pii", "value": "false"},
+		{"fullName": "metastore1.catalog1.schema1.table1.column1", "key": "classification", "value": "confidential"},
+		{"fullName": "metastore1.catalog2", "key": "pii", "value": "true"}
+	]`, string(content))
+}
+
+func TestDataSourceTagHandler_LoadTags_withRestTagLoading(t *testing.T) {
+	// Given
+	c := catalog.CatalogInfo{
+		MetastoreId: "metastore1",
+		Name:        "catalog1",
+		FullName:    "catalog1",
+	}
+
+	workspaceRepoMock := newMockDataSourceWorkspaceRepository(t)
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "catalogs", "catalog1").Return(nil, nil).Once()
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "tables", "catalog1.schema1.table1").Return([]types.EntityTagAssignment{
+		{EntityType: "tables", EntityName: "catalog1.schema1.table1", TagKey: "pii", TagValue: "true"},
+	}, nil).Once()
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "columns", "catalog1.schema1.table1.column1").Return([]types.EntityTagAssignment{
+		{EntityType: "columns", EntityName: "catalog1.schema1.table1.column1", TagKey: "classification"},
+	}, nil).Once()
+
+	dstg := DataSourceTagHandler{
+		tagCache: make(map[string][]*tag.Tag),
+		configMap: &config.ConfigMap{
+			Parameters: map[string]string{
+				constants.DatabricksAccountId: "AccountId",
+				constants.DatabricksUser:      "User",
+				constants.DatabricksPassword:  "Password",
+				constants.DatabricksPlatform:  "AWS",
+			},
+		},
+		workspaceRepoFactory: func(repoCredentials *types.RepositoryCredentials, workspaceId int64) (dataSourceWorkspaceRepository, error) {
+			return workspaceRepoMock, nil
+		},
+		warehouseIdMap: map[string]string{},
+		tagLoading:     tagLoadingRest,
+		tagAssignments: newEntityTagCache(),
+	}
+
+	// When
+	err := dstg.LoadTags(context.Background(), &provisioning.Workspace{
+		WorkspaceName:  "workspaceId",
+		DeploymentName: "test-deployment",
+	}, &c)
+	require.NoError(t, err)
+
+	// Then
+	assert.Empty(t, dstg.GetTag(context.Background(), "catalog1"))
+	assert.Equal(t, []*tag.Tag{{Key: "pii", Value: "true", Source: constants.TagSource}}, dstg.GetTag(context.Background(), "catalog1.schema1.table1"))
+	assert.Equal(t, []*tag.Tag{{Key: "pii", Value: "true", Source: constants.TagSource}}, dstg.GetTag(context.Background(), "catalog1.schema1.table1"))
+	assert.Equal(t, []*tag.Tag{{Key: "classification", Value: "", Source: constants.TagSource}}, dstg.GetTag(context.Background(), "catalog1.schema1.table1.column1"))
+}
+
+func TestDataSourceTagHandler_LoadTableTags(t *testing.T) {
+	// Given
+	table := catalog.TableInfo{
+		FullName: "catalog1.schema1.table1",
+		Columns:  []catalog.ColumnInfo{{Name: "column1"}, {Name: "column2"}},
+	}
+
+	workspaceRepoMock := newMockDataSourceWorkspaceRepository(t)
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "tables", "catalog1.schema1.table1").Return([]types.EntityTagAssignment{
+		{EntityType: "tables", EntityName: "catalog1.schema1.table1", TagKey: "pii", TagValue: "true"},
+	}, nil).Once()
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "columns", "catalog1.schema1.table1.column1").Return([]types.EntityTagAssignment{
+		{EntityType: "columns", EntityName: "catalog1.schema1.table1.column1", TagKey: "classification", TagValue: "confidential"},
+	}, nil).Once()
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "columns", "catalog1.schema1.table1.column2").Return(nil, errors.New("boom")).Once()
+
+	dstg := DataSourceTagHandler{
+		tagCache:       make(map[string][]*tag.Tag),
+		tagAssignments: newEntityTagCache(),
+		tagLoading:     tagLoadingRest,
+		restRepo:       workspaceRepoMock,
+	}
+
+	// When
+	dstg.LoadTableTags(context.Background(), &table)
+
+	// Then
+	assert.Equal(t, []*tag.Tag{{Key: "pii", Value: "true", Source: constants.TagSource}}, dstg.GetTag(context.Background(), "catalog1.schema1.table1"))
+	assert.Equal(t, []*tag.Tag{{Key: "classification", Value: "confidential", Source: constants.TagSource}}, dstg.GetTag(context.Background(), "catalog1.schema1.table1.column1"))
+	assert.Empty(t, dstg.GetTag(context.Background(), "catalog1.schema1.table1.column2"))
+}
+
+func TestDataSourceTagHandler_LoadTableTags_sharedWithDataObjectFilter(t *testing.T) {
+	// Given
+	table := catalog.TableInfo{
+		MetastoreId: "metastore1",
+		FullName:    "catalog1.schema1.table1",
+		Columns:     []catalog.ColumnInfo{{Name: "column1"}, {Name: "column2"}},
+	}
+
+	dataObjectFilter, err := filters.Parse(`not tag("raito:ignore")`)
+	require.NoError(t, err)
+
+	// The tags loaded for the data object filter are not requested again, except the tags that failed to load
+	workspaceRepoMock := newMockDataSourceWorkspaceRepository(t)
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "tables", "catalog1.schema1.table1").Return([]types.EntityTagAssignment{
+		{EntityType: "tables", EntityName: "catalog1.schema1.table1", TagKey: "pii", TagValue: "true"},
+	}, nil).Once()
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "columns", "catalog1.schema1.table1.column1").Return([]types.EntityTagAssignment{
+		{EntityType: "columns", EntityName: "catalog1.schema1.table1.column1", TagKey: "classification", TagValue: "confidential"},
+	}, nil).Once()
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "columns", "catalog1.schema1.table1.column2").Return(nil, errors.New("boom")).Once()
+	workspaceRepoMock.EXPECT().ListEntityTagAssignments(mock.Anything, "columns", "catalog1.schema1.table1.column2").Return([]types.EntityTagAssignment{
+		{EntityType: "columns", EntityName: "catalog1.schema1.table1.column2", TagKey: "classification", TagValue: "public"},
+	}, nil).Once()
+
+	tagAssignments := newEntityTagCache()
+
+	dstg := DataSourceTagHandler{
+		tagCache:       make(map[string][]*tag.Tag),
+		tagAssignments: tagAssignments,
+		tagLoading:     tagLoadingRest,
+		metastoreId:    "metastore1",
+		restRepo:       workspaceRepoMock,
+	}
+
+	// When
+	filterTags := loadDataObjectFilterTags(context.Background(), dataObjectFilter, tagAssignments, workspaceRepoMock, table.MetastoreId, tableTagBatch(&table))
+	dstg.LoadTableTags(context.Background(), &table)
+
+	// Then
+	assert.Len(t, filterTags, 3)
+	assert.Equal(t, []*tag.Tag{{Key: "pii", Value: "true", Source: constants.TagSource}}, dstg.GetTag(context.Background(), "catalog1.schema1.table1"))
+	assert.Equal(t, []*tag.Tag{{Key: "classification", Value: "confidential", Source: constants.TagSource}}, dstg.GetTag(context.Background(), "catalog1.schema1.table1.column1"))
+	assert.Equal(t, []*tag.Tag{{Key: "classification", Value: "public", Source: constants.TagSource}}, dstg.GetTag(context.Background(), "catalog1.schema1.table1.column2"))
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/raito-io/golang-set/set"
 
 	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/filters"
 	"cli-plugin-databricks/databricks/platform"
 	"cli-plugin-databricks/databricks/repo"
 	"cli-plugin-databricks/databricks/repo/types"
@@ -43,6 +44,7 @@ type dataUsageWorkspaceRepository interface {
 	ListCatalogs(ctx context.Context) <-chan repo.ChannelItem[catalog.CatalogInfo]
 	ListSchemas(ctx context.Context, catalogName string) <-chan repo.ChannelItem[catalog.SchemaInfo]
 	ListTables(ctx context.Context, catalogName string, schemaName string) <-chan repo.ChannelItem[catalog.TableInfo]
+	ListEntityTagAssignments(ctx context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error)
 }
 
 var _ wrappers.DataUsageSyncer = (*DataUsageSyncer)(nil)
@@ -64,6 +66,8 @@ func NewDataUsageSyncer() *DataUsageSyncer {
 }
 
 func (d *DataUsageSyncer) SyncDataUsage(ctx context.Context, fileCreator wrappers.DataUsageStatementHandler, configParams *config.ConfigMap) (err error) {
+	defer repo.LogThrottlingStats()
+
 	defer func() {
 		if err != nil {
 			logger.Error(fmt.Sprintf("SyncDataUsage failed: %s", err.Error()))
@@ -80,6 +84,11 @@ func (d *DataUsageSyncer) SyncDataUsage(ctx context.Context, fileCreator wrapper
 		return fmt.Errorf("metastore filter: %w", err)
 	}
 
+	dataObjectFilter, err := parseDataObjectFilter(configParams)
+	if err != nil {
+		return fmt.Errorf("data object filter: %w", err)
+	}
+
 	metastores, workspaces, workspaceMetastoreMap, err := d.loadMetastores(ctx, configParams)
 	if err != nil {
 		return err
@@ -111,7 +120,7 @@ func (d *DataUsageSyncer) SyncDataUsage(ctx context.Context, fileCreator wrapper
 
 		logger.Info(fmt.Sprintf("Syncing data usage for workspace %s", workspaces[wi].DeploymentName))
 
-		err = d.syncWorkspace(ctx, &workspaces[wi], &metastore, fileCreator, configParams)
+		err = d.syncWorkspace(ctx, &workspaces[wi], &metastore, dataObjectFilter, fileCreator, configParams)
 		if err != nil {
 			logger.Warn(fmt.Sprintf("Sync data usage for metastore %s in workspace %s failed: %s", metastore.Name, workspaces[wi].WorkspaceName, err.Error()))
 		}
@@ -120,7 +129,7 @@ func (d *DataUsageSyncer) SyncDataUsage(ctx context.Context, fileCreator wrapper
 	return nil
 }
 
-func (d *DataUsageSyncer) syncWorkspace(ctx context.Context, workspace *provisioning.Workspace, metastore *catalog.MetastoreInfo, fileCreator wrappers.DataUsageStatementHandler, configParams *config.ConfigMap) error {
+func (d *DataUsageSyncer) syncWorkspace(ctx context.Context, workspace *provisioning.Workspace, metastore *catalog.MetastoreInfo, dataObjectFilter *filters.Expression, fileCreator wrappers.DataUsageStatementHandler, configParams *config.ConfigMap) error {
 	logger.Info(fmt.Sprintf("Syncing workspace %s", workspace.DeploymentName))
 
 	pltfrm, _, repoCredentials, err := utils.GetAndValidateParameters(configParams)
@@ -153,7 +162,7 @@ func (d *DataUsageSyncer) syncWorkspace(ctx context.Context, workspace *provisio
 
 	userLastUsage := make(map[string]*UserDefaults)
 
-	tableInfoMap, err := d.getTableInfoMap(ctx, repo)
+	tableInfoMap, excludedDataObjects, err := d.getTableInfoMap(ctx, repo, metastore.MetastoreId, dataObjectFilter)
 	if err != nil {
 		return fmt.Errorf("get table info map: %w", err)
 	}
@@ -196,6 +205,16 @@ func (d *DataUsageSyncer) syncWorkspace(ctx context.Context, workspace *provisio
 			return nil //nolint: nilerr
 		}
 
+		if len(whatItems) > 0 && len(excludedDataObjects) > 0 {
+			whatItems = excludeFilteredDataObjects(whatItems, excludedDataObjects, metastore)
+
+			if len(whatItems) == 0 {
+				logger.Debug(fmt.Sprintf("Ignoring query %s as it only accesses data objects excluded by the data object filter", queryInfo.QueryId))
+
+				return nil
+			}
+		}
+
 		if len(whatItems) > 0 {
 			err = fileCreator.AddStatements([]data_usage.Statement{
 				{
@@ -244,42 +263,114 @@ func (d *DataUsageSyncer) syncWorkspace(ctx context.Context, workspace *provisio
 	return nil
 }
 
-func (d *DataUsageSyncer) getTableInfoMap(ctx context.Context, workspaceRepo dataUsageWorkspaceRepository) (map[string][]catalog.TableInfo, error) {
+// getTableInfoMap lists all tables by name. The catalogs, schemas and tables that do not match the data object filter are returned as excluded data objects.
+// Excluded tables are still listed, so table names in queries are resolved the same way, regardless of the filter.
+func (d *DataUsageSyncer) getTableInfoMap(ctx context.Context, workspaceRepo dataUsageWorkspaceRepository, metastoreId string, dataObjectFilter *filters.Expression) (map[string][]catalog.TableInfo, set.Set[string], error) {
 	tableSchemaCatalogMap := make(map[string][]catalog.TableInfo)
+	excludedDataObjects := set.NewSet[string]()
 
-	catalogs := workspaceRepo.ListCatalogs(ctx)
+	catalogs, err := collectChannelItems(workspaceRepo.ListCatalogs(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("list catalogs: %w", err)
+	}
 
-	for ci := range catalogs {
-		if ci.HasError() {
-			return nil, fmt.Errorf("list catalogs: %w", ci.Error())
+	tagCache := newEntityTagCache()
+
+	catalogTags := loadDataObjectFilterTags(ctx, dataObjectFilter, tagCache, workspaceRepo, metastoreId, fullNamesOf(catalogs, func(c *catalog.CatalogInfo) string { return c.Name }))
+
+	for _, catalogItem := range catalogs {
+		catalogExcluded := excludedByDataObjectFilter(dataObjectFilter, catalogTags, catalogFilterObject(catalogItem))
+		if catalogExcluded {
+			excludedDataObjects.Add(catalogItem.Name)
 		}
 
-		catalogItem := ci.I
+		schemas, schemasErr := collectChannelItems(workspaceRepo.ListSchemas(ctx, catalogItem.Name))
+		if schemasErr != nil {
+			return nil, nil, fmt.Errorf("list schemas: %w", schemasErr)
+		}
+
+		var schemaTags map[string]entityTagResult
+		if !catalogExcluded {
+			schemaTags = loadDataObjectFilterTags(ctx, dataObjectFilter, tagCache, workspaceRepo, metastoreId, fullNamesOf(schemas, func(schema *catalog.SchemaInfo) string { return schema.FullName }))
+		}
 
-		schemas := workspaceRepo.ListSchemas(ctx, catalogItem.Name)
+		for _, schemaItem := range schemas {
+			schemaExcluded := catalogExcluded
+			if !schemaExcluded && excludedByDataObjectFilter(dataObjectFilter, schemaTags, schemaFilterObject(schemaItem)) {
+				schemaExcluded = true
 
-		for si := range schemas {
-			if si.HasError() {
-				return nil, fmt.Errorf("list schemas: %w", si.Error())
+				excludedDataObjects.Add(schemaItem.FullName)
 			}
 
-			schemaItem := si.I
+			tables, tablesErr := collectChannelItems(workspaceRepo.ListTables(ctx, catalogItem.Name, schemaItem.Name))
+			if tablesErr != nil {
+				return nil, nil, fmt.Errorf("list tables: %w", tablesErr)
+			}
 
-			tables := workspaceRepo.ListTables(ctx, catalogItem.Name, schemaItem.Name)
+			var tableTags map[string]entityTagResult
+			if !schemaExcluded {
+				tableTags = loadDataObjectFilterTags(ctx, dataObjectFilter, tagCache, workspaceRepo, metastoreId, fullNamesOf(tables, func(table *catalog.TableInfo) string { return table.FullName }))
+			}
 
-			for ti := range tables {
-				if ti.HasError() {
-					return nil, fmt.Errorf("list tables: %w", ti.Error())
+			for _, tableItem := range tables {
+				if !schemaExcluded && excludedByDataObjectFilter(dataObjectFilter, tableTags, tableFilterObject(tableItem)) {
+					excludedDataObjects.Add(tableItem.FullName)
 				}
 
-				tableItem := ti.Item()
+				tableSchemaCatalogMap[tableItem.Name] = append(tableSchemaCatalogMap[tableItem.Name], *tableItem)
+			}
+		}
+	}
 
-				tableSchemaCatalogMap[tableItem.Name] = append(tableSchemaCatalogMap[tableItem.Name], tableItem)
+	return tableSchemaCatalogMap, excludedDataObjects, nil
+}
+
+// excludedByDataObjectFilter returns true if the data object does not match the data object filter.
+// Data objects of which the filter could not be evaluated are excluded as well, so their usage is never imported unfiltered.
+func excludedByDataObjectFilter(expression *filters.Expression, tags map[string]entityTagResult, object *filters.Object) bool {
+	match, err := matchDataObjectFilter(expression, tags, object)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Unable to evaluate the data object filter on %q: %s. Will exclude its usage", object.Path, err.Error()))
+
+		return true
+	}
+
+	return !match
+}
+
+func fullNamesOf[T any](items []*T, fullName func(item *T) string) []string {
+	fullNames := make([]string, 0, len(items))
+
+	for _, item := range items {
+		fullNames = append(fullNames, fullName(item))
+	}
+
+	return fullNames
+}
+
+// excludeFilteredDataObjects removes the accessed data objects that are excluded themselves, or of which the catalog or schema is excluded
+func excludeFilteredDataObjects(items []data_usage.UsageDataObjectItem, excludedDataObjects set.Set[string], metastore *catalog.MetastoreInfo) []data_usage.UsageDataObjectItem {
+	result := make([]data_usage.UsageDataObjectItem, 0, len(items))
+
+	for _, item := range items {
+		nameParts := strings.Split(strings.TrimPrefix(item.DataObject.FullName, metastore.MetastoreId+"."), ".")
+
+		excluded := false
+
+		for i := range nameParts {
+			if excludedDataObjects.Contains(strings.Join(nameParts[:i+1], ".")) {
+				excluded = true
+
+				break
 			}
 		}
+
+		if !excluded {
+			result = append(result, item)
+		}
 	}
 
-	return tableSchemaCatalogMap, nil
+	return result
 }
 
 var useCatalogRegex = regexp.MustCompile(`(?im)^use\s+(catalog|database)\s+(?P<catalog>.*)$`)
@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/filters"
 	"cli-plugin-databricks/databricks/platform"
 	"cli-plugin-databricks/databricks/repo"
 	"cli-plugin-databricks/databricks/repo/types"
@@ -319,7 +320,7 @@ func TestDataUsageSyncer_syncWorkspace(t *testing.T) {
 	}).Once()
 
 	// When
-	err := duSyncer.syncWorkspace(context.Background(), &provisioning.Workspace{WorkspaceId: 42, DeploymentName: deployment, WorkspaceName: "workspaceName", WorkspaceStatus: "RUNNING"}, &catalog.MetastoreInfo{Name: "Metastore1", MetastoreId: metastoreId}, fileCreatorMock, configMap)
+	err := duSyncer.syncWorkspace(context.Background(), &provisioning.Workspace{WorkspaceId: 42, DeploymentName: deployment, WorkspaceName: "workspaceName", WorkspaceStatus: "RUNNING"}, &catalog.MetastoreInfo{Name: "Metastore1", MetastoreId: metastoreId}, nil, fileCreatorMock, configMap)
 
 	// Then
 	require.NoError(t, err)
@@ -956,3 +957,48 @@ UPDATE table1 SET column1 = 'Blablabla' WHERE column1 = 'Blab'`
 
 	assert.Equal(t, result, expected)
 }
+
+func TestDataUsageSyncer_getTableInfoMap_DataObjectFilter(t *testing.T) {
+	// Given
+	workspaceRepoMock := newMockDataUsageWorkspaceRepository(t)
+
+	workspaceRepoMock.EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel([]catalog.CatalogInfo{
+		{Name: "catalog1", Owner: "data-platform"},
+		{Name: "catalog2", Owner: "analyst@raito.io"},
+	})).Once()
+	workspaceRepoMock.EXPECT().ListSchemas(mock.Anything, "catalog1").Return(repo.ArrayToChannel([]catalog.SchemaInfo{{Name: "schema1", CatalogName: "catalog1", FullName: "catalog1.schema1", Owner: "data-platform"}})).Once()
+	workspaceRepoMock.EXPECT().ListSchemas(mock.Anything, "catalog2").Return(repo.ArrayToChannel([]catalog.SchemaInfo{{Name: "schema1", CatalogName: "catalog2", FullName: "catalog2.schema1", Owner: "data-platform"}})).Once()
+	workspaceRepoMock.EXPECT().ListTables(mock.Anything, "catalog1", "schema1").Return(repo.ArrayToChannel([]catalog.TableInfo{
+		{Name: "table1", CatalogName: "catalog1", SchemaName: "schema1", FullName: "catalog1.schema1.table1", Owner: "data-platform"},
+		{Name: "table2", CatalogName: "catalog1", SchemaName: "schema1", FullName: "catalog1.schema1.table2", Owner: "analyst@raito.io"},
+	})).Once()
+	workspaceRepoMock.EXPECT().ListTables(mock.Anything, "catalog2", "schema1").Return(repo.ArrayToChannel([]catalog.TableInfo{
+		{Name: "table1", CatalogName: "catalog2", SchemaName: "schema1", FullName: "catalog2.schema1.table1", Owner: "data-platform"},
+	})).Once()
+
+	dataObjectFilter, err := filters.Parse(`owner == "data-platform"`)
+	require.NoError(t, err)
+
+	metastore := &catalog.MetastoreInfo{MetastoreId: "metastoreId1"}
+
+	syncer := NewDataUsageSyncer()
+
+	// When
+	tableInfoMap, excludedDataObjects, err := syncer.getTableInfoMap(context.Background(), workspaceRepoMock, "metastore-id", dataObjectFilter)
+
+	// Then
+	require.NoError(t, err)
+	assert.Len(t, tableInfoMap["table1"], 2)
+	assert.Len(t, tableInfoMap["table2"], 1)
+	assert.ElementsMatch(t, []string{"catalog2", "catalog1.schema1.table2"}, excludedDataObjects.Slice())
+
+	items := excludeFilteredDataObjects([]data_usage.UsageDataObjectItem{
+		{DataObject: data_usage.UsageDataObjectReference{FullName: "metastoreId1.catalog1.schema1.table1", Type: data_source.Table}},
+		{DataObject: data_usage.UsageDataObjectReference{FullName: "metastoreId1.catalog1.schema1.table2", Type: data_source.Table}},
+		{DataObject: data_usage.UsageDataObjectReference{FullName: "metastoreId1.catalog2.schema1.table1", Type: data_source.Table}},
+	}, excludedDataObjects, metastore)
+
+	assert.Equal(t, []data_usage.UsageDataObjectItem{
+		{DataObject: data_usage.UsageDataObjectReference{FullName: "metastoreId1.catalog1.schema1.table1", Type: data_source.Table}},
+	}, items)
+}
@@ -0,0 +1,117 @@
+package databricks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"golang.org/x/sync/errgroup"
+
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+const entityTagLoadingConcurrency = 8
+
+type entityTagResult struct {
+	Assignments []types.EntityTagAssignment
+	Err         error
+}
+
+// loadEntityTagAssignments loads the tag assignments of the data objects (full names without metastore).
+// The entity tag assignments API only returns the tags of a single entity, so the requests of a batch are executed concurrently.
+func loadEntityTagAssignments(ctx context.Context, tagRepo tagAssignmentRepository, fullNames []string) map[string]entityTagResult {
+	results := make([]entityTagResult, len(fullNames))
+
+	var group errgroup.Group
+	group.SetLimit(entityTagLoadingConcurrency)
+
+	for i, fullName := range fullNames {
+		group.Go(func() error {
+			results[i].Assignments, results[i].Err = checkpointedResponse(ctx, "entity-tags/"+fullName, func() ([]types.EntityTagAssignment, error) {
+				return tagRepo.ListEntityTagAssignments(ctx, tagEntityType(fullName), fullName)
+			})
+
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+
+	resultMap := make(map[string]entityTagResult, len(fullNames))
+
+	for i, fullName := range fullNames {
+		resultMap[fullName] = results[i]
+	}
+
+	return resultMap
+}
+
+// entityTagCache caches the tag assignments loaded through the entity tag assignments API, by metastore and full name.
+// It is shared by the data object filter and the tag handler, so the tags of a data object are only requested once per sync.
+type entityTagCache struct {
+	mutex   sync.Mutex
+	results map[string]entityTagResult // Unique id (metastore id and full name) -> tag assignments
+}
+
+func newEntityTagCache() *entityTagCache {
+	return &entityTagCache{results: make(map[string]entityTagResult)}
+}
+
+// load returns the tag assignments of the data objects (full names without metastore) of the metastore.
+// Only the tags that are not cached yet are requested. Failed requests are not cached, so they are retried by the next load.
+func (c *entityTagCache) load(ctx context.Context, tagRepo tagAssignmentRepository, metastoreId string, fullNames []string) map[string]entityTagResult {
+	resultMap := make(map[string]entityTagResult, len(fullNames))
+
+	var missing []string
+
+	c.mutex.Lock()
+
+	for _, fullName := range fullNames {
+		if result, found := c.results[createUniqueId(metastoreId, fullName)]; found {
+			resultMap[fullName] = result
+		} else {
+			missing = append(missing, fullName)
+		}
+	}
+
+	c.mutex.Unlock()
+
+	if len(missing) == 0 {
+		return resultMap
+	}
+
+	loaded := loadEntityTagAssignments(ctx, tagRepo, missing)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for fullName, result := range loaded {
+		if result.Err == nil {
+			c.results[createUniqueId(metastoreId, fullName)] = result
+		}
+
+		resultMap[fullName] = result
+	}
+
+	return resultMap
+}
+
+// invalidate removes the tag assignments of the data object (full name without metastore) from the cache, once its tags are changed
+func (c *entityTagCache) invalidate(metastoreId string, fullName string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.results, createUniqueId(metastoreId, fullName))
+}
+
+// tableTagBatch returns the full names of the table and its columns, of which the tags are loaded in one batch
+func tableTagBatch(table *catalog.TableInfo) []string {
+	fullNames := make([]string, 0, len(table.Columns)+1)
+	fullNames = append(fullNames, table.FullName)
+
+	for i := range table.Columns {
+		fullNames = append(fullNames, table.FullName+"."+table.Columns[i].Name)
+	}
+
+	return fullNames
+}
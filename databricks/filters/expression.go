@@ -0,0 +1,418 @@
+package filters
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Object contains the properties of a data object an Expression is evaluated on
+type Object struct {
+	Path      string // Full name without metastore, e.g. catalog.schema.table
+	Name      string
+	Type      string // catalog, schema, table, column or function
+	TableType string // MANAGED, EXTERNAL, VIEW, ... Only set for tables
+	Owner     string
+	Comment   string
+	Tags      map[string]string
+}
+
+// Expression is a parsed data object filter expression, e.g. `type == "table" and owner == "data-platform" and not tag("raito:ignore")`.
+//
+// The following grammar is supported:
+//
+//	expression := term { ("or" | "||") term }
+//	term       := factor { ("and" | "&&") factor }
+//	factor     := ("not" | "!") factor | "(" expression ")" | field operator string | "tag" "(" string [ "," string ] ")"
+//	field      := "path" | "name" | "type" | "table_type" | "owner" | "comment"
+//	operator   := "==" | "!=" | "=~" | "!~"
+//
+// == and != compare case-insensitively, =~ and !~ match a regular expression against the complete value.
+// tag("key") matches data objects with the tag, tag("key", "value") data objects with the tag set to that value.
+type Expression struct {
+	root     node
+	usesTags bool
+}
+
+// Parse parses the filter expression
+func Parse(input string) (*Expression, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %s at position %d", p.peek(), p.peek().position)
+	}
+
+	return &Expression{root: root, usesTags: p.usesTags}, nil
+}
+
+// Match returns true if the data object matches the expression
+func (e *Expression) Match(object *Object) bool {
+	return e.root.match(object)
+}
+
+// UsesTags returns true if the expression refers to tags, so tags need to be loaded before matching
+func (e *Expression) UsesTags() bool {
+	return e.usesTags
+}
+
+type node interface {
+	match(object *Object) bool
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) match(object *Object) bool {
+	return n.left.match(object) || n.right.match(object)
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) match(object *Object) bool {
+	return n.left.match(object) && n.right.match(object)
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) match(object *Object) bool {
+	return !n.operand.match(object)
+}
+
+type compareNode struct {
+	field  string
+	equal  bool
+	value  string
+	regexp *regexp.Regexp // Set for =~ and !~
+}
+
+func (n compareNode) match(object *Object) bool {
+	var actual string
+
+	switch n.field {
+	case "path":
+		actual = object.Path
+	case "name":
+		actual = object.Name
+	case "type":
+		actual = object.Type
+	case "table_type":
+		actual = object.TableType
+	case "owner":
+		actual = object.Owner
+	case "comment":
+		actual = object.Comment
+	}
+
+	if n.regexp != nil {
+		return n.regexp.MatchString(actual) == n.equal
+	}
+
+	return strings.EqualFold(actual, n.value) == n.equal
+}
+
+type tagNode struct {
+	key      string
+	value    string
+	hasValue bool
+}
+
+func (n tagNode) match(object *Object) bool {
+	value, found := object.Tags[n.key]
+	if !found {
+		return false
+	}
+
+	return !n.hasValue || value == n.value
+}
+
+var fields = map[string]struct{}{"path": {}, "name": {}, "type": {}, "table_type": {}, "owner": {}, "comment": {}}
+
+type parser struct {
+	tokens   []token
+	index    int
+	usesTags bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.index]
+
+	if t.kind != tokenEnd {
+		p.index++
+	}
+
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, value string) (token, error) {
+	t := p.next()
+	if t.kind != kind || (value != "" && t.value != value) {
+		expected := value
+		if expected == "" {
+			expected = string(kind)
+		}
+
+		return t, fmt.Errorf("expected %s at position %d, got %s", expected, t.position, t)
+	}
+
+	return t, nil
+}
+
+func (p *parser) parseExpression() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().isOperator("or", "||") {
+		p.next()
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().isOperator("and", "&&") {
+		p.next()
+
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseFactor() (node, error) {
+	t := p.next()
+
+	switch {
+	case t.isOperator("not", "!"):
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		return notNode{operand: operand}, nil
+	case t.kind == tokenSymbol && t.value == "(":
+		expression, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.expect(tokenSymbol, ")")
+		if err != nil {
+			return nil, err
+		}
+
+		return expression, nil
+	case t.kind == tokenIdentifier && t.value == "tag":
+		return p.parseTag()
+	case t.kind == tokenIdentifier:
+		return p.parseComparison(t)
+	default:
+		return nil, fmt.Errorf("unexpected %s at position %d", t, t.position)
+	}
+}
+
+func (p *parser) parseTag() (node, error) {
+	p.usesTags = true
+
+	_, err := p.expect(tokenSymbol, "(")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := p.expect(tokenString, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := tagNode{key: key.value}
+
+	if p.peek().kind == tokenSymbol && p.peek().value == "," {
+		p.next()
+
+		value, err := p.expect(tokenString, "")
+		if err != nil {
+			return nil, err
+		}
+
+		result.value = value.value
+		result.hasValue = true
+	}
+
+	_, err = p.expect(tokenSymbol, ")")
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *parser) parseComparison(field token) (node, error) {
+	if _, found := fields[field.value]; !found {
+		return nil, fmt.Errorf("unknown field %q at position %d", field.value, field.position)
+	}
+
+	operator, err := p.expect(tokenSymbol, "")
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := p.expect(tokenString, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := compareNode{field: field.value, value: value.value}
+
+	switch operator.value {
+	case "==", "!=":
+		result.equal = operator.value == "=="
+	case "=~", "!~":
+		result.equal = operator.value == "=~"
+
+		result.regexp, err = regexp.Compile("^(?:" + value.value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q at position %d: %w", value.value, value.position, err)
+		}
+	default:
+		return nil, fmt.Errorf("expected comparison operator at position %d, got %s", operator.position, operator)
+	}
+
+	return result, nil
+}
+
+type tokenKind string
+
+const (
+	tokenIdentifier tokenKind = "identifier"
+	tokenString     tokenKind = "string"
+	tokenSymbol     tokenKind = "operator"
+	tokenEnd        tokenKind = "end of expression"
+)
+
+type token struct {
+	kind     tokenKind
+	value    string
+	position int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEnd {
+		return string(t.kind)
+	}
+
+	return fmt.Sprintf("%s %q", t.kind, t.value)
+}
+
+func (t token) isOperator(keyword string, symbol string) bool {
+	return (t.kind == tokenIdentifier && t.value == keyword) || (t.kind == tokenSymbol && t.value == symbol)
+}
+
+var symbols = []string{"==", "!=", "=~", "!~", "&&", "||", "!", "(", ")", ","}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			value, end, err := readString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: value, position: i})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdentifier, value: strings.ToLower(string(runes[start:i])), position: start})
+		default:
+			symbol := ""
+
+			for _, s := range symbols {
+				if strings.HasPrefix(string(runes[i:]), s) {
+					symbol = s
+
+					break
+				}
+			}
+
+			if symbol == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+
+			tokens = append(tokens, token{kind: tokenSymbol, value: symbol, position: i})
+			i += len([]rune(symbol))
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, position: len(runes)}), nil
+}
+
+// readString reads the double quoted string starting at start. Quotes and backslashes can be escaped with a backslash.
+func readString(runes []rune, start int) (string, int, error) {
+	var value strings.Builder
+
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				value.WriteRune(runes[i])
+			}
+		case '"':
+			return value.String(), i + 1, nil
+		default:
+			value.WriteRune(runes[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string at position %d", start)
+}
@@ -0,0 +1,94 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression_Match(t *testing.T) {
+	managedTable := &Object{
+		Path:      "catalog.schema.table",
+		Name:      "table",
+		Type:      "table",
+		TableType: "MANAGED",
+		Owner:     "data-platform",
+		Comment:   "Customer orders",
+		Tags:      map[string]string{"domain": "sales"},
+	}
+
+	ignoredView := &Object{
+		Path:      "catalog.schema.view",
+		Name:      "view",
+		Type:      "table",
+		TableType: "VIEW",
+		Owner:     "analyst@raito.io",
+		Tags:      map[string]string{"raito:ignore": ""},
+	}
+
+	schema := &Object{
+		Path: "catalog.schema",
+		Name: "schema",
+		Type: "schema",
+	}
+
+	tests := []struct {
+		expression string
+		expected   []bool // managed table, ignored view, schema
+	}{
+		{expression: `not tag("raito:ignore")`, expected: []bool{true, false, true}},
+		{expression: `type != "table" or (table_type == "managed" and owner == "data-platform")`, expected: []bool{true, false, true}},
+		{expression: `type == "table" && owner =~ ".*@raito\\.io"`, expected: []bool{false, true, false}},
+		{expression: `tag("domain", "sales") || path == "catalog.schema"`, expected: []bool{true, false, true}},
+		{expression: `tag("domain", "finance")`, expected: []bool{false, false, false}},
+		{expression: `comment =~ "(?i).*orders.*"`, expected: []bool{true, false, false}},
+		{expression: `path !~ "catalog\\.schema\\..*" AND NOT name == "schema"`, expected: []bool{false, false, false}},
+		{expression: `!(name == "view")`, expected: []bool{true, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			expression, err := Parse(tt.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, []bool{expression.Match(managedTable), expression.Match(ignoredView), expression.Match(schema)})
+		})
+	}
+}
+
+func TestExpression_UsesTags(t *testing.T) {
+	withTags, err := Parse(`type == "table" and not tag("raito:ignore")`)
+	require.NoError(t, err)
+
+	withoutTags, err := Parse(`type == "table"`)
+	require.NoError(t, err)
+
+	assert.True(t, withTags.UsesTags())
+	assert.False(t, withoutTags.UsesTags())
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{expression: ``, err: "unexpected end of expression at position 0"},
+		{expression: `size == "1"`, err: `unknown field "size" at position 0`},
+		{expression: `type == table`, err: `expected string at position 8, got identifier "table"`},
+		{expression: `type == "table" owner == "me"`, err: `unexpected identifier "owner" at position 16`},
+		{expression: `(type == "table"`, err: "expected ) at position 16, got end of expression"},
+		{expression: `name =~ "("`, err: `invalid regular expression "(" at position 8`},
+		{expression: `tag("key"`, err: "expected ) at position 9, got end of expression"},
+		{expression: `name == "unterminated`, err: "unterminated string at position 8"},
+		{expression: `name = "x"`, err: `unexpected character '=' at position 5`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := Parse(tt.expression)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
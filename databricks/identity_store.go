@@ -3,13 +3,18 @@ package databricks
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/databricks/databricks-sdk-go/service/iam"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
 	is "github.com/raito-io/cli/base/identity_store"
+	"github.com/raito-io/cli/base/tag"
 	"github.com/raito-io/cli/base/util/config"
 	"github.com/raito-io/cli/base/wrappers"
 
+	"cli-plugin-databricks/databricks/constants"
 	"cli-plugin-databricks/databricks/platform"
 	"cli-plugin-databricks/databricks/repo"
 	"cli-plugin-databricks/databricks/repo/types"
@@ -19,15 +24,34 @@ import (
 
 var _ wrappers.IdentityStoreSyncer = (*IdentityStoreSyncer)(nil)
 
+const (
+	identityTagActive       = "databricks_active"
+	identityTagExternalId   = "databricks_external_id"
+	identityTagEntitlements = "databricks_entitlements"
+	identityTagRoles        = "databricks_roles"
+	identityTagWorkspace    = "databricks_workspace"
+)
+
+type missingEmailStrategy string
+
+const (
+	missingEmailUsername missingEmailStrategy = "username"
+	missingEmailEmpty    missingEmailStrategy = "empty"
+	missingEmailSkip     missingEmailStrategy = "skip"
+	missingEmailFail     missingEmailStrategy = "fail"
+)
+
 //go:generate go run github.com/vektra/mockery/v2 --name=identityStoreAccountRepository
 type identityStoreAccountRepository interface {
 	ListUsers(ctx context.Context, optFn ...func(options *types.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User]
 	ListGroups(ctx context.Context, optFn ...func(options *types.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group]
 	ListServicePrincipals(ctx context.Context, optFn ...func(options *types.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal]
+	GetWorkspaces(ctx context.Context) ([]provisioning.Workspace, error)
 }
 
 type IdentityStoreSyncer struct {
-	accountRepoFactory func(pltfrm platform.DatabricksPlatform, accountId string, repoCredentials *types.RepositoryCredentials) (identityStoreAccountRepository, error)
+	accountRepoFactory   func(pltfrm platform.DatabricksPlatform, accountId string, repoCredentials *types.RepositoryCredentials) (identityStoreAccountRepository, error)
+	workspaceRepoFactory func(repoCredentials *types.RepositoryCredentials, workspaceId int64) (identityStoreWorkspaceRepository, error)
 }
 
 func NewIdentityStoreSyncer() *IdentityStoreSyncer {
@@ -35,39 +59,75 @@ func NewIdentityStoreSyncer() *IdentityStoreSyncer {
 		accountRepoFactory: func(pltfrm platform.DatabricksPlatform, accountId string, repoCredentials *types.RepositoryCredentials) (identityStoreAccountRepository, error) {
 			return repo.NewAccountRepository(pltfrm, repoCredentials, accountId)
 		},
+		workspaceRepoFactory: func(repoCredentials *types.RepositoryCredentials, workspaceId int64) (identityStoreWorkspaceRepository, error) {
+			return repo.NewWorkspaceRepository(repoCredentials, workspaceId)
+		},
 	}
 }
 
-func (i *IdentityStoreSyncer) GetIdentityStoreMetaData(_ context.Context, _ *config.ConfigMap) (*is.MetaData, error) {
+// GetIdentityStoreMetaData returns the metadata of the identity store.
+// Databricks identities are typically provisioned by an identity provider, so they can be linked to the identities of the identity store of that identity provider.
+// Databricks can only act as master identity store if explicitly enabled, for accounts without an identity provider.
+func (i *IdentityStoreSyncer) GetIdentityStoreMetaData(_ context.Context, configMap *config.ConfigMap) (*is.MetaData, error) {
 	return &is.MetaData{
 		Type:        "databricks",
-		CanBeMaster: false,
-		CanBeLinked: false,
+		CanBeMaster: configMap.GetBoolWithDefault(constants.DatabricksIdentityStoreMaster, false),
+		CanBeLinked: configMap.GetBoolWithDefault(constants.DatabricksLinkByExternalId, false),
 	}, nil
 }
 
 func (i *IdentityStoreSyncer) SyncIdentityStore(ctx context.Context, identityHandler wrappers.IdentityStoreIdentityHandler, configMap *config.ConfigMap) error {
+	defer repo.LogThrottlingStats()
+
 	pltfrm, accountId, repoCredentials, err := utils2.GetAndValidateParameters(configMap)
 	if err != nil {
 		return err
 	}
 
+	emailStrategy := missingEmailStrategy(configMap.GetStringWithDefault(constants.DatabricksMissingEmailStrategy, string(missingEmailUsername)))
+	if !slices.Contains([]missingEmailStrategy{missingEmailUsername, missingEmailEmpty, missingEmailSkip, missingEmailFail}, emailStrategy) {
+		return fmt.Errorf("unsupported missing email strategy %q, expected %q, %q, %q or %q", emailStrategy, missingEmailUsername, missingEmailEmpty, missingEmailSkip, missingEmailFail)
+	}
+
+	linkByExternalId := configMap.GetBoolWithDefault(constants.DatabricksLinkByExternalId, false)
+
 	accountRepo, err := i.accountRepoFactory(pltfrm, accountId, &repoCredentials)
 	if err != nil {
 		return fmt.Errorf("account repository factory: %w", err)
 	}
 
-	userMemberMap, err := i.getGroups(ctx, identityHandler, accountRepo)
+	identities, err := listAccountIdentities(ctx, accountRepo)
+	if err != nil {
+		return err
+	}
+
+	var workspaceGroupParents map[string][]string
+
+	if configMap.GetBoolWithDefault(constants.DatabricksWorkspaceLocalIdentities, false) {
+		workspaceFilter, err := NewObjectFilter(configMap.GetString(constants.DatabricksExcludeWorkspaces), configMap.GetString(constants.DatabricksIncludeWorkspaces))
+		if err != nil {
+			return fmt.Errorf("workspace filter: %w", err)
+		}
+
+		workspaceGroupParents, err = i.getWorkspaceGroups(ctx, identityHandler, accountRepo, identities, func(workspace *provisioning.Workspace) (identityStoreWorkspaceRepository, error) {
+			return utils2.InitWorkspaceRepo(ctx, repoCredentials, pltfrm, workspace, i.workspaceRepoFactory)
+		}, workspaceFilter)
+		if err != nil {
+			return fmt.Errorf("load workspace-local groups: %w", err)
+		}
+	}
+
+	userMemberMap, err := i.getGroups(identityHandler, identities.groups, linkByExternalId, workspaceGroupParents)
 	if err != nil {
 		return fmt.Errorf("load groups: %w", err)
 	}
 
-	err = i.getUsers(ctx, identityHandler, userMemberMap, accountRepo)
+	err = i.getUsers(identityHandler, identities.users, userMemberMap, emailStrategy, linkByExternalId)
 	if err != nil {
 		return fmt.Errorf("load users: %w", err)
 	}
 
-	err = i.getServicePrincipals(ctx, identityHandler, userMemberMap, accountRepo)
+	err = i.getServicePrincipals(identityHandler, identities.servicePrincipals, userMemberMap, linkByExternalId)
 	if err != nil {
 		return fmt.Errorf("load service principals: %w", err)
 	}
@@ -75,31 +135,24 @@ func (i *IdentityStoreSyncer) SyncIdentityStore(ctx context.Context, identityHan
 	return nil
 }
 
-func (i *IdentityStoreSyncer) getGroups(ctx context.Context, identityHandler wrappers.IdentityStoreIdentityHandler, repo identityStoreAccountRepository) (map[string][]string, error) {
-	channelCtx, cancelFn := context.WithCancel(ctx)
-	defer cancelFn()
-
-	groupsChannel := repo.ListGroups(channelCtx)
-
+// getGroups imports the account groups and returns the groups of each user and service principal, by id.
+// workspaceGroupParents contains the workspace-local groups of account principals, by id.
+func (i *IdentityStoreSyncer) getGroups(identityHandler wrappers.IdentityStoreIdentityHandler, groups []*iam.Group, linkByExternalId bool, workspaceGroupParents map[string][]string) (map[string][]string, error) {
 	dependencyTree := utils.NewDependencyTree[string]()
 	groupMap := make(map[string]iam.Group)
 	groupParents := make(map[string][]string)
 	userParents := make(map[string][]string)
 
-	for groupItem := range groupsChannel {
-		if groupItem.HasError() {
-			return nil, fmt.Errorf("list group item: %w", groupItem.Error())
-		}
-
-		group := groupItem.Item()
+	for _, group := range groups {
+		groupExternalId := identityExternalId(group.Id, groupIdpExternalId(group), linkByExternalId)
 		membergroups := make([]string, 0, len(group.Members))
 
 		for _, member := range group.Members {
 			if strings.HasPrefix(member.Ref, "Groups/") {
 				membergroups = append(membergroups, member.Value)
-				groupParents[member.Value] = append(groupParents[member.Value], group.Id)
+				groupParents[member.Value] = append(groupParents[member.Value], groupExternalId)
 			} else {
-				userParents[member.Value] = append(userParents[member.Value], group.Id)
+				userParents[member.Value] = append(userParents[member.Value], groupExternalId)
 			}
 		}
 
@@ -108,7 +161,7 @@ func (i *IdentityStoreSyncer) getGroups(ctx context.Context, identityHandler wra
 			return nil, fmt.Errorf("add member groups to dependency tree: %w", err)
 		}
 
-		groupMap[group.Id] = group
+		groupMap[group.Id] = *group
 	}
 
 	err := dependencyTree.DependencyCleanup()
@@ -122,8 +175,9 @@ func (i *IdentityStoreSyncer) getGroups(ctx context.Context, identityHandler wra
 		return identityHandler.AddGroups(&is.Group{
 			Name:                   group.DisplayName,
 			DisplayName:            group.DisplayName,
-			ExternalId:             group.Id,
-			ParentGroupExternalIds: groupParents[groupId],
+			ExternalId:             identityExternalId(group.Id, groupIdpExternalId(&group), linkByExternalId),
+			ParentGroupExternalIds: append(groupParents[groupId], workspaceGroupParents[groupId]...),
+			Tags:                   identityTags(nil, groupIdpExternalId(&group), group.Entitlements, group.Roles),
 		})
 	})
 
@@ -131,33 +185,22 @@ func (i *IdentityStoreSyncer) getGroups(ctx context.Context, identityHandler wra
 		return nil, fmt.Errorf("breadth first traversal: %w", err)
 	}
 
-	return userParents, nil
-}
-
-func (i *IdentityStoreSyncer) getUsers(ctx context.Context, identityHandler wrappers.IdentityStoreIdentityHandler, userParentMap map[string][]string, repo identityStoreAccountRepository) error {
-	channelCtx, cancelFn := context.WithCancel(ctx)
-	defer cancelFn()
-
-	usersChannel := repo.ListUsers(channelCtx)
-
-	for userItem := range usersChannel {
-		if userItem.HasError() {
-			return fmt.Errorf("list user item: %w", userItem.Error())
+	for id, parents := range workspaceGroupParents {
+		if _, isGroup := groupMap[id]; !isGroup {
+			userParents[id] = append(userParents[id], parents...)
 		}
+	}
 
-		user := userItem.Item()
-
-		var primaryEmail string
-
-		for _, email := range user.Emails {
-			if email.Primary {
-				primaryEmail = email.Value
-				break
-			}
-		}
+	return userParents, nil
+}
 
-		if primaryEmail == "" {
-			return fmt.Errorf("user %s has no primary email", user.Name)
+func (i *IdentityStoreSyncer) getUsers(identityHandler wrappers.IdentityStoreIdentityHandler, users []*iam.User, userParentMap map[string][]string, emailStrategy missingEmailStrategy, linkByExternalId bool) error {
+	for _, user := range users {
+		email, ok, err := userEmail(user, emailStrategy)
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
 		}
 
 		name := user.DisplayName
@@ -171,12 +214,13 @@ func (i *IdentityStoreSyncer) getUsers(ctx context.Context, identityHandler wrap
 			}
 		}
 
-		err := identityHandler.AddUsers(&is.User{
+		err = identityHandler.AddUsers(&is.User{
 			Name:             name,
-			Email:            primaryEmail,
-			ExternalId:       user.Id,
+			Email:            email,
+			ExternalId:       identityExternalId(user.Id, user.ExternalId, linkByExternalId),
 			UserName:         user.UserName,
 			GroupExternalIds: userParentMap[user.Id],
+			Tags:             identityTags(&user.Active, user.ExternalId, user.Entitlements, user.Roles),
 		})
 
 		if err != nil {
@@ -187,18 +231,8 @@ func (i *IdentityStoreSyncer) getUsers(ctx context.Context, identityHandler wrap
 	return nil
 }
 
-func (i *IdentityStoreSyncer) getServicePrincipals(ctx context.Context, identityHandler wrappers.IdentityStoreIdentityHandler, userParentMap map[string][]string, repo identityStoreAccountRepository) error {
-	channelCtx, cancelFn := context.WithCancel(ctx)
-	defer cancelFn()
-
-	servicePrincipalsChannel := repo.ListServicePrincipals(channelCtx)
-
-	for servicePrincipalItem := range servicePrincipalsChannel {
-		if servicePrincipalItem.HasError() {
-			return fmt.Errorf("list service principal item: %w", servicePrincipalItem.Error())
-		}
-
-		sp := servicePrincipalItem.Item()
+func (i *IdentityStoreSyncer) getServicePrincipals(identityHandler wrappers.IdentityStoreIdentityHandler, servicePrincipals []*iam.ServicePrincipal, userParentMap map[string][]string, linkByExternalId bool) error {
+	for _, sp := range servicePrincipals {
 		name := sp.DisplayName
 
 		if name == "" {
@@ -209,9 +243,10 @@ func (i *IdentityStoreSyncer) getServicePrincipals(ctx context.Context, identity
 		err := identityHandler.AddUsers(&is.User{
 			Name:             name,
 			Email:            sp.ApplicationId,
-			ExternalId:       sp.Id,
+			ExternalId:       identityExternalId(sp.Id, sp.ExternalId, linkByExternalId),
 			UserName:         sp.ApplicationId,
 			GroupExternalIds: userParentMap[sp.Id],
+			Tags:             identityTags(&sp.Active, sp.ExternalId, sp.Entitlements, sp.Roles),
 		})
 
 		if err != nil {
@@ -221,3 +256,83 @@ func (i *IdentityStoreSyncer) getServicePrincipals(ctx context.Context, identity
 
 	return nil
 }
+
+// identityExternalId returns the id of the user, service principal or group in the identity provider if linkByExternalId is enabled and the identity is provisioned through SCIM.
+// Otherwise, the Databricks id is returned.
+func identityExternalId(id string, idpExternalId string, linkByExternalId bool) string {
+	if linkByExternalId && idpExternalId != "" {
+		return idpExternalId
+	}
+
+	return id
+}
+
+// groupIdpExternalId returns the externalId set by the identity provider, ignoring the role id of groups backing Raito roles
+func groupIdpExternalId(group *iam.Group) string {
+	if isRoleGroup(group) {
+		return ""
+	}
+
+	return group.ExternalId
+}
+
+// userEmail returns the primary email of the user. If the user has no primary email, the first email is used.
+// Users without any email are handled according to the missing email strategy. False is returned if the user should be skipped.
+func userEmail(user *iam.User, emailStrategy missingEmailStrategy) (string, bool, error) {
+	for _, email := range user.Emails {
+		if email.Primary && email.Value != "" {
+			return email.Value, true, nil
+		}
+	}
+
+	for _, email := range user.Emails {
+		if email.Value != "" {
+			return email.Value, true, nil
+		}
+	}
+
+	switch emailStrategy {
+	case missingEmailFail:
+		return "", false, fmt.Errorf("user %s has no email", user.Id)
+	case missingEmailSkip:
+		logger.Warn(fmt.Sprintf("user %s has no email. Will skip user.", user.Id))
+
+		return "", false, nil
+	case missingEmailEmpty:
+		logger.Warn(fmt.Sprintf("user %s has no email. Will import user without email.", user.Id))
+
+		return "", true, nil
+	default:
+		logger.Warn(fmt.Sprintf("user %s has no email. Will use username instead.", user.Id))
+
+		return user.UserName, true, nil
+	}
+}
+
+// identityTags returns the status, the id in the identity provider, entitlements and roles of a user, service principal or group as tags
+func identityTags(active *bool, externalId string, entitlements []iam.ComplexValue, roles []iam.ComplexValue) []*tag.Tag {
+	var tags []*tag.Tag
+
+	if active != nil {
+		tags = appendAttributeTag(tags, identityTagActive, strconv.FormatBool(*active))
+	}
+
+	tags = appendAttributeTag(tags, identityTagExternalId, externalId)
+	tags = appendAttributeTag(tags, identityTagEntitlements, joinComplexValues(entitlements))
+
+	return appendAttributeTag(tags, identityTagRoles, joinComplexValues(roles))
+}
+
+func joinComplexValues(values []iam.ComplexValue) string {
+	result := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if value.Value != "" {
+			result = append(result, value.Value)
+		}
+	}
+
+	slices.Sort(result)
+
+	return strings.Join(result, ",")
+}
@@ -2,10 +2,14 @@ package databricks
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/databricks/databricks-sdk-go/service/iam"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
 	"github.com/raito-io/cli/base/identity_store"
+	"github.com/raito-io/cli/base/tag"
 	"github.com/raito-io/cli/base/util/config"
 	"github.com/raito-io/cli/base/wrappers/mocks"
 	"github.com/stretchr/testify/assert"
@@ -48,6 +52,8 @@ func TestIdentityStoreSyncer_SyncIdentityStore(t *testing.T) {
 		{
 			Id:          "gid1",
 			DisplayName: "group-1",
+			ExternalId:  "idp-group1",
+			Roles:       []iam.ComplexValue{{Value: "arn:aws:iam::123:instance-profile/profile"}},
 			Members: []iam.ComplexValue{{
 				Value:   "idUser1",
 				Display: "user1",
@@ -58,9 +64,13 @@ func TestIdentityStoreSyncer_SyncIdentityStore(t *testing.T) {
 
 	mockRepo.EXPECT().ListUsers(mock.Anything).Return(repo.ArrayToChannel([]iam.User{
 		{
-			DisplayName: "user1",
-			Id:          "idUser1",
-			UserName:    "username1",
+			DisplayName:  "user1",
+			Id:           "idUser1",
+			UserName:     "username1",
+			Active:       true,
+			ExternalId:   "idp-user1",
+			Entitlements: []iam.ComplexValue{{Value: "workspace-access"}, {Value: "databricks-sql-access"}},
+			Roles:        []iam.ComplexValue{{Value: "account_admin"}},
 			Emails: []iam.ComplexValue{
 				{
 					Type:    "private",
@@ -125,6 +135,12 @@ func TestIdentityStoreSyncer_SyncIdentityStore(t *testing.T) {
 			ExternalId:       "idUser1",
 			UserName:         "username1",
 			GroupExternalIds: []string{"gid1"},
+			Tags: []*tag.Tag{
+				{Key: "databricks_active", Value: "true", Source: constants.TagSource},
+				{Key: "databricks_external_id", Value: "idp-user1", Source: constants.TagSource},
+				{Key: "databricks_entitlements", Value: "databricks-sql-access,workspace-access", Source: constants.TagSource},
+				{Key: "databricks_roles", Value: "account_admin", Source: constants.TagSource},
+			},
 		},
 		{
 			Name:             "user2",
@@ -132,6 +148,7 @@ func TestIdentityStoreSyncer_SyncIdentityStore(t *testing.T) {
 			ExternalId:       "idUser2",
 			UserName:         "username2",
 			GroupExternalIds: []string{"gid2"},
+			Tags:             []*tag.Tag{{Key: "databricks_active", Value: "false", Source: constants.TagSource}},
 		},
 		{
 			Name:       "Service Principal 1",
@@ -141,12 +158,14 @@ func TestIdentityStoreSyncer_SyncIdentityStore(t *testing.T) {
 			GroupExternalIds: []string{
 				"gid2",
 			},
+			Tags: []*tag.Tag{{Key: "databricks_active", Value: "true", Source: constants.TagSource}},
 		},
 		{
 			Name:       "Service Principal 2",
 			Email:      "someApplicationId2",
 			ExternalId: "ServicePrincipalId2",
 			UserName:   "someApplicationId2",
+			Tags:       []*tag.Tag{{Key: "databricks_active", Value: "true", Source: constants.TagSource}},
 		},
 	})
 
@@ -156,6 +175,10 @@ func TestIdentityStoreSyncer_SyncIdentityStore(t *testing.T) {
 			DisplayName:            "group-1",
 			ExternalId:             "gid1",
 			ParentGroupExternalIds: []string{"gid2"},
+			Tags: []*tag.Tag{
+				{Key: "databricks_external_id", Value: "idp-group1", Source: constants.TagSource},
+				{Key: "databricks_roles", Value: "arn:aws:iam::123:instance-profile/profile", Source: constants.TagSource},
+			},
 		},
 		{
 			Name:                   "group-2",
@@ -166,6 +189,259 @@ func TestIdentityStoreSyncer_SyncIdentityStore(t *testing.T) {
 	}, identityHandlerMock.Groups)
 }
 
+func TestIdentityStoreSyncer_SyncIdentityStore_MissingEmail(t *testing.T) {
+	users := []iam.User{
+		{DisplayName: "user1", Id: "idUser1", UserName: "username1"},
+		{DisplayName: "user2", Id: "idUser2", UserName: "username2", Emails: []iam.ComplexValue{{Type: "work", Value: "user2@test.com"}}},
+	}
+
+	tests := []struct {
+		name           string
+		strategy       string
+		expectedEmails map[string]string // user id -> email
+		expectedErr    string
+	}{
+		{name: "default username", strategy: "", expectedEmails: map[string]string{"idUser1": "username1", "idUser2": "user2@test.com"}},
+		{name: "empty", strategy: "empty", expectedEmails: map[string]string{"idUser1": "", "idUser2": "user2@test.com"}},
+		{name: "skip", strategy: "skip", expectedEmails: map[string]string{"idUser2": "user2@test.com"}},
+		{name: "fail", strategy: "fail", expectedErr: "user idUser1 has no email"},
+		{name: "unknown strategy", strategy: "unknown", expectedErr: "unsupported missing email strategy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			service, mockRepo := createIdentityStoreSyncer(t)
+			identityHandlerMock := mocks.NewSimpleIdentityStoreIdentityHandler(t, 1)
+
+			if tt.strategy != "unknown" {
+				mockRepo.EXPECT().ListGroups(mock.Anything).Return(repo.ArrayToChannel([]iam.Group{})).Once()
+				mockRepo.EXPECT().ListUsers(mock.Anything).Return(repo.ArrayToChannel(users)).Once()
+				mockRepo.EXPECT().ListServicePrincipals(mock.Anything).Return(repo.ArrayToChannel([]iam.ServicePrincipal{})).Once()
+			}
+
+			configMap := &config.ConfigMap{
+				Parameters: map[string]string{
+					constants.DatabricksAccountId: "AccountId",
+					constants.DatabricksUser:      "User",
+					constants.DatabricksPassword:  "Password",
+					constants.DatabricksPlatform:  "AWS",
+				},
+			}
+
+			if tt.strategy != "" {
+				configMap.Parameters[constants.DatabricksMissingEmailStrategy] = tt.strategy
+			}
+
+			// When
+			err := service.SyncIdentityStore(context.Background(), identityHandlerMock, configMap)
+
+			// Then
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			emails := make(map[string]string)
+			for _, user := range identityHandlerMock.Users {
+				emails[user.ExternalId] = user.Email
+			}
+
+			assert.Equal(t, tt.expectedEmails, emails)
+		})
+	}
+}
+
+func TestIdentityStoreSyncer_GetIdentityStoreMetaData(t *testing.T) {
+	service, _ := createIdentityStoreSyncer(t)
+
+	metaData, err := service.GetIdentityStoreMetaData(context.Background(), &config.ConfigMap{Parameters: map[string]string{}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "databricks", metaData.Type)
+	assert.False(t, metaData.CanBeLinked)
+	assert.False(t, metaData.CanBeMaster)
+
+	metaData, err = service.GetIdentityStoreMetaData(context.Background(), &config.ConfigMap{Parameters: map[string]string{constants.DatabricksIdentityStoreMaster: "true"}})
+	require.NoError(t, err)
+
+	assert.False(t, metaData.CanBeLinked)
+	assert.True(t, metaData.CanBeMaster)
+
+	metaData, err = service.GetIdentityStoreMetaData(context.Background(), &config.ConfigMap{Parameters: map[string]string{constants.DatabricksLinkByExternalId: "true"}})
+	require.NoError(t, err)
+
+	assert.True(t, metaData.CanBeLinked)
+	assert.False(t, metaData.CanBeMaster)
+}
+
+func TestIdentityStoreSyncer_SyncIdentityStore_LinkByExternalId(t *testing.T) {
+	// Given
+	service, mockRepo := createIdentityStoreSyncer(t)
+	identityHandlerMock := mocks.NewSimpleIdentityStoreIdentityHandler(t, 1)
+
+	mockRepo.EXPECT().ListGroups(mock.Anything).Return(repo.ArrayToChannel([]iam.Group{
+		{
+			Id:          "gid1",
+			DisplayName: "group-1",
+			ExternalId:  "idp-group1",
+			Members: []iam.ComplexValue{
+				{Value: "idUser1", Ref: "Users/idUser1"},
+				{Value: "gid2", Ref: "Groups/gid2"},
+				{Value: "ServicePrincipalId1", Ref: "ServicePrincipals/ServicePrincipalId1"},
+			},
+		},
+		{
+			Id:          "gid2",
+			DisplayName: "group-2",
+			Members:     []iam.ComplexValue{{Value: "idUser2", Ref: "Users/idUser2"}},
+		},
+	})).Once()
+
+	mockRepo.EXPECT().ListUsers(mock.Anything).Return(repo.ArrayToChannel([]iam.User{
+		{DisplayName: "user1", Id: "idUser1", UserName: "user1@test.com", ExternalId: "idp-user1", Emails: []iam.ComplexValue{{Value: "user1@test.com", Primary: true}}},
+		{DisplayName: "user2", Id: "idUser2", UserName: "user2@test.com", Emails: []iam.ComplexValue{{Value: "user2@test.com", Primary: true}}},
+	})).Once()
+
+	mockRepo.EXPECT().ListServicePrincipals(mock.Anything).Return(repo.ArrayToChannel([]iam.ServicePrincipal{
+		{DisplayName: "Service Principal 1", Id: "ServicePrincipalId1", ApplicationId: "someApplicationId1", ExternalId: "idp-sp1"},
+	})).Once()
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:        "AccountId",
+			constants.DatabricksUser:             "User",
+			constants.DatabricksPassword:         "Password",
+			constants.DatabricksPlatform:         "AWS",
+			constants.DatabricksLinkByExternalId: "true",
+		},
+	}
+
+	// When
+	err := service.SyncIdentityStore(context.Background(), identityHandlerMock, configMap)
+
+	// Then
+	require.NoError(t, err)
+
+	users := make(map[string][]string)
+	for _, user := range identityHandlerMock.Users {
+		users[user.ExternalId] = user.GroupExternalIds
+	}
+
+	assert.Equal(t, map[string][]string{
+		"idp-user1": {"idp-group1"},
+		"idUser2":   {"gid2"},
+		"idp-sp1":   {"idp-group1"},
+	}, users)
+
+	groups := make(map[string][]string)
+	for _, group := range identityHandlerMock.Groups {
+		groups[group.ExternalId] = group.ParentGroupExternalIds
+	}
+
+	assert.Equal(t, map[string][]string{
+		"idp-group1": nil,
+		"gid2":       {"idp-group1"},
+	}, groups)
+}
+
+func TestIdentityStoreSyncer_SyncIdentityStore_WorkspaceLocalGroups(t *testing.T) {
+	// Given
+	service, mockRepo := createIdentityStoreSyncer(t)
+	identityHandlerMock := mocks.NewSimpleIdentityStoreIdentityHandler(t, 1)
+
+	federatedRepo := newMockIdentityStoreWorkspaceRepository(t)
+	legacyRepo := newMockIdentityStoreWorkspaceRepository(t)
+	failingRepo := newMockIdentityStoreWorkspaceRepository(t)
+
+	service.workspaceRepoFactory = func(repoCredentials *repo2.RepositoryCredentials, workspaceId int64) (identityStoreWorkspaceRepository, error) {
+		switch workspaceId {
+		case 1:
+			return federatedRepo, nil
+		case 2:
+			return legacyRepo, nil
+		case 4:
+			return failingRepo, nil
+		}
+
+		return nil, fmt.Errorf("unexpected workspace %d", workspaceId)
+	}
+
+	mockRepo.EXPECT().GetWorkspaces(mock.Anything).Return([]provisioning.Workspace{
+		{WorkspaceId: 1, WorkspaceName: "ws-federated", DeploymentName: "federated"},
+		{WorkspaceId: 2, WorkspaceName: "ws-legacy", DeploymentName: "legacy"},
+		{WorkspaceId: 3, WorkspaceName: "ws-excluded", DeploymentName: "excluded"},
+		{WorkspaceId: 4, WorkspaceName: "ws-failing", DeploymentName: "failing"},
+	}, nil).Once()
+
+	mockRepo.EXPECT().ListUsers(mock.Anything).Return(repo.ArrayToChannel([]iam.User{{Id: "idUser1", DisplayName: "user1", UserName: "user1@test.com", Active: true, Emails: []iam.ComplexValue{{Value: "user1@test.com", Primary: true}}}})).Once()
+	mockRepo.EXPECT().ListServicePrincipals(mock.Anything).Return(repo.ArrayToChannel([]iam.ServicePrincipal{{Id: "ServicePrincipalId1", DisplayName: "Service Principal 1", ApplicationId: "app-1", Active: true}})).Once()
+	mockRepo.EXPECT().ListGroups(mock.Anything).Return(repo.ArrayToChannel([]iam.Group{{Id: "gid1", DisplayName: "group-1"}})).Once()
+
+	federatedRepo.EXPECT().Ping(mock.Anything).Return(nil).Once()
+	federatedRepo.EXPECT().ListGroups(mock.Anything).Return(repo.ArrayToChannel([]iam.Group{
+		{Id: "gid1", DisplayName: "group-1", Meta: &iam.ResourceMeta{ResourceType: "Group"}},
+		{Id: "lgid1", DisplayName: "admins", Meta: &iam.ResourceMeta{ResourceType: "WorkspaceGroup"}, Members: []iam.ComplexValue{{Value: "idUser1"}, {Value: "gid1"}, {Value: "lgid2"}}},
+		{Id: "lgid2", DisplayName: "analysts", Meta: &iam.ResourceMeta{ResourceType: "WorkspaceGroup"}, Members: []iam.ComplexValue{{Value: "ServicePrincipalId1"}}},
+	})).Once()
+
+	// Workspace without identity federation, so the members are linked by user name
+	legacyRepo.EXPECT().Ping(mock.Anything).Return(nil).Once()
+	legacyRepo.EXPECT().ListGroups(mock.Anything).Return(repo.ArrayToChannel([]iam.Group{
+		{Id: "lgid1", DisplayName: "users", Meta: &iam.ResourceMeta{ResourceType: "WorkspaceGroup"}, Members: []iam.ComplexValue{{Value: "wsUser1"}, {Value: "wsUnknown", Display: "unknown"}}},
+	})).Once()
+	legacyRepo.EXPECT().ListUsers(mock.Anything).Return(repo.ArrayToChannel([]iam.User{{Id: "wsUser1", UserName: "user1@test.com"}, {Id: "wsUnknown", UserName: "unknown@test.com"}})).Once()
+	legacyRepo.EXPECT().ListServicePrincipals(mock.Anything).Return(repo.ArrayToChannel([]iam.ServicePrincipal{})).Once()
+
+	// Workspace of which the SCIM API fails, so its workspace-local groups are skipped
+	failingRepo.EXPECT().Ping(mock.Anything).Return(nil).Once()
+	failingRepo.EXPECT().ListGroups(mock.Anything).RunAndReturn(func(ctx context.Context, optFn ...func(*repo2.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
+		items := make(chan repo.ChannelItem[iam.Group], 1)
+		items <- repo.ChannelItem[iam.Group]{Err: errors.New("scim error")}
+		close(items)
+
+		return items
+	}).Once()
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:                "AccountId",
+			constants.DatabricksUser:                     "User",
+			constants.DatabricksPassword:                 "Password",
+			constants.DatabricksPlatform:                 "AWS",
+			constants.DatabricksWorkspaceLocalIdentities: "true",
+			constants.DatabricksExcludeWorkspaces:        "ws-excluded",
+		},
+	}
+
+	// When
+	err := service.SyncIdentityStore(context.Background(), identityHandlerMock, configMap)
+
+	// Then
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []identity_store.Group{
+		{Name: "group-1", DisplayName: "group-1", ExternalId: "gid1", ParentGroupExternalIds: []string{"1/lgid1"}},
+		{Name: "ws-federated/admins", DisplayName: "ws-federated/admins", ExternalId: "1/lgid1", Tags: []*tag.Tag{{Key: "databricks_workspace", Value: "ws-federated", Source: constants.TagSource}}},
+		{Name: "ws-federated/analysts", DisplayName: "ws-federated/analysts", ExternalId: "1/lgid2", ParentGroupExternalIds: []string{"1/lgid1"}, Tags: []*tag.Tag{{Key: "databricks_workspace", Value: "ws-federated", Source: constants.TagSource}}},
+		{Name: "ws-legacy/users", DisplayName: "ws-legacy/users", ExternalId: "2/lgid1", Tags: []*tag.Tag{{Key: "databricks_workspace", Value: "ws-legacy", Source: constants.TagSource}}},
+	}, identityHandlerMock.Groups)
+
+	userGroups := make(map[string][]string)
+	for _, user := range identityHandlerMock.Users {
+		userGroups[user.ExternalId] = user.GroupExternalIds
+	}
+
+	assert.Equal(t, map[string][]string{
+		"idUser1":             {"1/lgid1", "2/lgid1"},
+		"ServicePrincipalId1": {"1/lgid2"},
+	}, userGroups)
+}
+
 func createIdentityStoreSyncer(t *testing.T) (*IdentityStoreSyncer, *mockIdentityStoreAccountRepository) {
 	t.Helper()
 
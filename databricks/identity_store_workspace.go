@@ -0,0 +1,231 @@
+package databricks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/databricks/databricks-sdk-go/service/iam"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	is "github.com/raito-io/cli/base/identity_store"
+	"github.com/raito-io/cli/base/wrappers"
+	"github.com/raito-io/golang-set/set"
+
+	"cli-plugin-databricks/databricks/repo"
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+// workspaceGroupResourceType is the SCIM resource type of workspace-local groups. Account groups assigned to a workspace have resource type Group.
+const workspaceGroupResourceType = "WorkspaceGroup"
+
+//go:generate go run github.com/vektra/mockery/v2 --name=identityStoreWorkspaceRepository
+type identityStoreWorkspaceRepository interface {
+	Ping(ctx context.Context) error
+	ListUsers(ctx context.Context, optFn ...func(options *types.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User]
+	ListGroups(ctx context.Context, optFn ...func(options *types.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group]
+	ListServicePrincipals(ctx context.Context, optFn ...func(options *types.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal]
+}
+
+// accountPrincipals contains the ids of all account users, service principals and groups
+type accountPrincipals struct {
+	ids       set.Set[string]
+	idsByName map[string]string // User name or application id -> id
+}
+
+// getWorkspaceGroups imports the workspace-local groups of all selected workspaces, namespaced by workspace.
+// Members of the local groups are linked to the account principals, by id if the workspace uses identity federation or by user name or application id otherwise.
+// The workspace-local groups of each account principal are returned by account id.
+func (i *IdentityStoreSyncer) getWorkspaceGroups(ctx context.Context, identityHandler wrappers.IdentityStoreIdentityHandler, accountRepo identityStoreAccountRepository, identities *accountIdentities, workspaceRepoFn func(workspace *provisioning.Workspace) (identityStoreWorkspaceRepository, error), workspaceFilter ObjectFilter) (map[string][]string, error) {
+	workspaces, err := accountRepo.GetWorkspaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get workspaces: %w", err)
+	}
+
+	principals := loadAccountPrincipals(identities)
+
+	parents := make(map[string][]string)
+
+	for wi := range workspaces {
+		workspace := &workspaces[wi]
+
+		if !workspaceFilter.IncludeObject(workspace.WorkspaceName) {
+			continue
+		}
+
+		workspaceRepo, err := workspaceRepoFn(workspace)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to login for workspace %s: %s. Will skip workspace-local groups of workspace.", workspace.WorkspaceName, err))
+
+			continue
+		}
+
+		groups, memberParents, err := loadWorkspaceLocalGroups(ctx, workspace, workspaceRepo, principals)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to load workspace-local groups of workspace %s: %s. Will skip workspace-local groups of workspace.", workspace.WorkspaceName, err))
+
+			continue
+		}
+
+		for _, group := range groups {
+			err = identityHandler.AddGroups(group)
+			if err != nil {
+				return nil, fmt.Errorf("workspace %q: %w", workspace.WorkspaceName, err)
+			}
+		}
+
+		for principalId, groupIds := range memberParents {
+			parents[principalId] = append(parents[principalId], groupIds...)
+		}
+	}
+
+	return parents, nil
+}
+
+// loadWorkspaceLocalGroups returns the workspace-local groups of the workspace and the local groups of each account principal, by account id.
+// Nothing is returned if the workspace cannot be loaded completely, so a workspace is never imported partially.
+func loadWorkspaceLocalGroups(ctx context.Context, workspace *provisioning.Workspace, workspaceRepo identityStoreWorkspaceRepository, principals *accountPrincipals) ([]*is.Group, map[string][]string, error) {
+	groups, err := collectChannelItems(workspaceRepo.ListGroups(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("list groups: %w", err)
+	}
+
+	localGroupIds := make(map[string]string) // Local group id -> external id
+
+	for _, group := range groups {
+		if group.Meta != nil && group.Meta.ResourceType == workspaceGroupResourceType {
+			localGroupIds[group.Id] = workspaceGroupExternalId(workspace, group.Id)
+		}
+	}
+
+	if len(localGroupIds) == 0 {
+		return nil, nil, nil
+	}
+
+	logger.Info(fmt.Sprintf("Importing %d workspace-local groups of workspace %q", len(localGroupIds), workspace.WorkspaceName))
+
+	// The names of the workspace principals are only needed to link members of workspaces without identity federation
+	var workspaceNames map[string]string
+
+	resolveMember := func(memberId string) (string, bool, error) {
+		if principals.ids.Contains(memberId) {
+			return memberId, true, nil
+		}
+
+		if workspaceNames == nil {
+			names, loadErr := loadWorkspacePrincipalNames(ctx, workspaceRepo)
+			if loadErr != nil {
+				return "", false, loadErr
+			}
+
+			workspaceNames = names
+		}
+
+		accountId, found := principals.idsByName[workspaceNames[memberId]]
+
+		return accountId, found, nil
+	}
+
+	localParents := make(map[string][]string)
+	memberParents := make(map[string][]string)
+
+	for _, group := range groups {
+		externalId, isLocal := localGroupIds[group.Id]
+		if !isLocal {
+			continue
+		}
+
+		for _, member := range group.Members {
+			if _, found := localGroupIds[member.Value]; found {
+				localParents[member.Value] = append(localParents[member.Value], externalId)
+
+				continue
+			}
+
+			accountId, found, err := resolveMember(member.Value)
+			if err != nil {
+				return nil, nil, err
+			} else if !found {
+				logger.Warn(fmt.Sprintf("Member %q of workspace-local group %q in workspace %q not found in account. Will ignore member.", member.Display, group.DisplayName, workspace.WorkspaceName))
+
+				continue
+			}
+
+			memberParents[accountId] = append(memberParents[accountId], externalId)
+		}
+	}
+
+	localGroups := make([]*is.Group, 0, len(localGroupIds))
+
+	for _, group := range groups {
+		externalId, isLocal := localGroupIds[group.Id]
+		if !isLocal {
+			continue
+		}
+
+		name := fmt.Sprintf("%s/%s", workspace.WorkspaceName, group.DisplayName)
+
+		localGroups = append(localGroups, &is.Group{
+			Name:                   name,
+			DisplayName:            name,
+			ExternalId:             externalId,
+			ParentGroupExternalIds: localParents[group.Id],
+			Tags:                   appendAttributeTag(identityTags(nil, group.ExternalId, group.Entitlements, group.Roles), identityTagWorkspace, workspace.WorkspaceName),
+		})
+	}
+
+	return localGroups, memberParents, nil
+}
+
+// workspaceGroupExternalId returns the external id of a workspace-local group. Local group ids are only unique within their workspace.
+func workspaceGroupExternalId(workspace *provisioning.Workspace, groupId string) string {
+	return strconv.FormatInt(workspace.WorkspaceId, 10) + "/" + groupId
+}
+
+// loadAccountPrincipals indexes the account users, service principals and groups by id, and the users and service principals by name
+func loadAccountPrincipals(identities *accountIdentities) *accountPrincipals {
+	principals := &accountPrincipals{
+		ids:       set.NewSet[string](),
+		idsByName: make(map[string]string),
+	}
+
+	for _, user := range identities.users {
+		principals.ids.Add(user.Id)
+		principals.idsByName[user.UserName] = user.Id
+	}
+
+	for _, servicePrincipal := range identities.servicePrincipals {
+		principals.ids.Add(servicePrincipal.Id)
+		principals.idsByName[servicePrincipal.ApplicationId] = servicePrincipal.Id
+	}
+
+	for _, group := range identities.groups {
+		principals.ids.Add(group.Id)
+	}
+
+	return principals
+}
+
+// loadWorkspacePrincipalNames returns the user name or application id of the users and service principals of the workspace, by workspace id
+func loadWorkspacePrincipalNames(ctx context.Context, workspaceRepo identityStoreWorkspaceRepository) (map[string]string, error) {
+	names := make(map[string]string)
+
+	users, err := collectChannelItems(workspaceRepo.ListUsers(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+
+	for _, user := range users {
+		names[user.Id] = user.UserName
+	}
+
+	servicePrincipals, err := collectChannelItems(workspaceRepo.ListServicePrincipals(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list service principals: %w", err)
+	}
+
+	for _, servicePrincipal := range servicePrincipals {
+		names[servicePrincipal.Id] = servicePrincipal.ApplicationId
+	}
+
+	return names, nil
+}
@@ -31,6 +31,170 @@ func (_m *mockDataAccessAccountRepository) EXPECT() *mockDataAccessAccountReposi
 	return &mockDataAccessAccountRepository_Expecter{mock: &_m.Mock}
 }
 
+// CreateGroup provides a mock function with given fields: ctx, displayName
+func (_m *mockDataAccessAccountRepository) CreateGroup(ctx context.Context, displayName string) (*iam.Group, error) {
+	ret := _m.Called(ctx, displayName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateGroup")
+	}
+
+	var r0 *iam.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*iam.Group, error)); ok {
+		return rf(ctx, displayName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *iam.Group); ok {
+		r0 = rf(ctx, displayName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, displayName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataAccessAccountRepository_CreateGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateGroup'
+type mockDataAccessAccountRepository_CreateGroup_Call struct {
+	*mock.Call
+}
+
+// CreateGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - displayName string
+func (_e *mockDataAccessAccountRepository_Expecter) CreateGroup(ctx interface{}, displayName interface{}) *mockDataAccessAccountRepository_CreateGroup_Call {
+	return &mockDataAccessAccountRepository_CreateGroup_Call{Call: _e.mock.On("CreateGroup", ctx, displayName)}
+}
+
+func (_c *mockDataAccessAccountRepository_CreateGroup_Call) Run(run func(ctx context.Context, displayName string)) *mockDataAccessAccountRepository_CreateGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_CreateGroup_Call) Return(_a0 *iam.Group, _a1 error) *mockDataAccessAccountRepository_CreateGroup_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_CreateGroup_Call) RunAndReturn(run func(context.Context, string) (*iam.Group, error)) *mockDataAccessAccountRepository_CreateGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteGroup provides a mock function with given fields: ctx, groupId
+func (_m *mockDataAccessAccountRepository) DeleteGroup(ctx context.Context, groupId string) error {
+	ret := _m.Called(ctx, groupId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, groupId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessAccountRepository_DeleteGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteGroup'
+type mockDataAccessAccountRepository_DeleteGroup_Call struct {
+	*mock.Call
+}
+
+// DeleteGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - groupId string
+func (_e *mockDataAccessAccountRepository_Expecter) DeleteGroup(ctx interface{}, groupId interface{}) *mockDataAccessAccountRepository_DeleteGroup_Call {
+	return &mockDataAccessAccountRepository_DeleteGroup_Call{Call: _e.mock.On("DeleteGroup", ctx, groupId)}
+}
+
+func (_c *mockDataAccessAccountRepository_DeleteGroup_Call) Run(run func(ctx context.Context, groupId string)) *mockDataAccessAccountRepository_DeleteGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_DeleteGroup_Call) Return(_a0 error) *mockDataAccessAccountRepository_DeleteGroup_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_DeleteGroup_Call) RunAndReturn(run func(context.Context, string) error) *mockDataAccessAccountRepository_DeleteGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAccountRuleSet provides a mock function with given fields: ctx
+func (_m *mockDataAccessAccountRepository) GetAccountRuleSet(ctx context.Context) (*iam.RuleSetResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountRuleSet")
+	}
+
+	var r0 *iam.RuleSetResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*iam.RuleSetResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *iam.RuleSetResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.RuleSetResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataAccessAccountRepository_GetAccountRuleSet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccountRuleSet'
+type mockDataAccessAccountRepository_GetAccountRuleSet_Call struct {
+	*mock.Call
+}
+
+// GetAccountRuleSet is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockDataAccessAccountRepository_Expecter) GetAccountRuleSet(ctx interface{}) *mockDataAccessAccountRepository_GetAccountRuleSet_Call {
+	return &mockDataAccessAccountRepository_GetAccountRuleSet_Call{Call: _e.mock.On("GetAccountRuleSet", ctx)}
+}
+
+func (_c *mockDataAccessAccountRepository_GetAccountRuleSet_Call) Run(run func(ctx context.Context)) *mockDataAccessAccountRepository_GetAccountRuleSet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_GetAccountRuleSet_Call) Return(_a0 *iam.RuleSetResponse, _a1 error) *mockDataAccessAccountRepository_GetAccountRuleSet_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_GetAccountRuleSet_Call) RunAndReturn(run func(context.Context) (*iam.RuleSetResponse, error)) *mockDataAccessAccountRepository_GetAccountRuleSet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetWorkspaceByName provides a mock function with given fields: ctx, workspaceName
 func (_m *mockDataAccessAccountRepository) GetWorkspaceByName(ctx context.Context, workspaceName string) (*provisioning.Workspace, error) {
 	ret := _m.Called(ctx, workspaceName)
@@ -523,6 +687,346 @@ func (_c *mockDataAccessAccountRepository_ListWorkspaceAssignments_Call) RunAndR
 	return _c
 }
 
+// UpdateAccountRuleSet provides a mock function with given fields: ctx, etag, grantRules
+func (_m *mockDataAccessAccountRepository) UpdateAccountRuleSet(ctx context.Context, etag string, grantRules []iam.GrantRule) error {
+	ret := _m.Called(ctx, etag, grantRules)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAccountRuleSet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []iam.GrantRule) error); ok {
+		r0 = rf(ctx, etag, grantRules)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessAccountRepository_UpdateAccountRuleSet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAccountRuleSet'
+type mockDataAccessAccountRepository_UpdateAccountRuleSet_Call struct {
+	*mock.Call
+}
+
+// UpdateAccountRuleSet is a helper method to define mock.On call
+//   - ctx context.Context
+//   - etag string
+//   - grantRules []iam.GrantRule
+func (_e *mockDataAccessAccountRepository_Expecter) UpdateAccountRuleSet(ctx interface{}, etag interface{}, grantRules interface{}) *mockDataAccessAccountRepository_UpdateAccountRuleSet_Call {
+	return &mockDataAccessAccountRepository_UpdateAccountRuleSet_Call{Call: _e.mock.On("UpdateAccountRuleSet", ctx, etag, grantRules)}
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateAccountRuleSet_Call) Run(run func(ctx context.Context, etag string, grantRules []iam.GrantRule)) *mockDataAccessAccountRepository_UpdateAccountRuleSet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]iam.GrantRule))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateAccountRuleSet_Call) Return(_a0 error) *mockDataAccessAccountRepository_UpdateAccountRuleSet_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateAccountRuleSet_Call) RunAndReturn(run func(context.Context, string, []iam.GrantRule) error) *mockDataAccessAccountRepository_UpdateAccountRuleSet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateGroupExternalId provides a mock function with given fields: ctx, groupId, externalId
+func (_m *mockDataAccessAccountRepository) UpdateGroupExternalId(ctx context.Context, groupId string, externalId string) error {
+	ret := _m.Called(ctx, groupId, externalId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateGroupExternalId")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, groupId, externalId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessAccountRepository_UpdateGroupExternalId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateGroupExternalId'
+type mockDataAccessAccountRepository_UpdateGroupExternalId_Call struct {
+	*mock.Call
+}
+
+// UpdateGroupExternalId is a helper method to define mock.On call
+//   - ctx context.Context
+//   - groupId string
+//   - externalId string
+func (_e *mockDataAccessAccountRepository_Expecter) UpdateGroupExternalId(ctx interface{}, groupId interface{}, externalId interface{}) *mockDataAccessAccountRepository_UpdateGroupExternalId_Call {
+	return &mockDataAccessAccountRepository_UpdateGroupExternalId_Call{Call: _e.mock.On("UpdateGroupExternalId", ctx, groupId, externalId)}
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateGroupExternalId_Call) Run(run func(ctx context.Context, groupId string, externalId string)) *mockDataAccessAccountRepository_UpdateGroupExternalId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateGroupExternalId_Call) Return(_a0 error) *mockDataAccessAccountRepository_UpdateGroupExternalId_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateGroupExternalId_Call) RunAndReturn(run func(context.Context, string, string) error) *mockDataAccessAccountRepository_UpdateGroupExternalId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateGroupMembers provides a mock function with given fields: ctx, groupId, add, remove
+func (_m *mockDataAccessAccountRepository) UpdateGroupMembers(ctx context.Context, groupId string, add []string, remove []string) error {
+	ret := _m.Called(ctx, groupId, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateGroupMembers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, []string) error); ok {
+		r0 = rf(ctx, groupId, add, remove)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessAccountRepository_UpdateGroupMembers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateGroupMembers'
+type mockDataAccessAccountRepository_UpdateGroupMembers_Call struct {
+	*mock.Call
+}
+
+// UpdateGroupMembers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - groupId string
+//   - add []string
+//   - remove []string
+func (_e *mockDataAccessAccountRepository_Expecter) UpdateGroupMembers(ctx interface{}, groupId interface{}, add interface{}, remove interface{}) *mockDataAccessAccountRepository_UpdateGroupMembers_Call {
+	return &mockDataAccessAccountRepository_UpdateGroupMembers_Call{Call: _e.mock.On("UpdateGroupMembers", ctx, groupId, add, remove)}
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateGroupMembers_Call) Run(run func(ctx context.Context, groupId string, add []string, remove []string)) *mockDataAccessAccountRepository_UpdateGroupMembers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateGroupMembers_Call) Return(_a0 error) *mockDataAccessAccountRepository_UpdateGroupMembers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateGroupMembers_Call) RunAndReturn(run func(context.Context, string, []string, []string) error) *mockDataAccessAccountRepository_UpdateGroupMembers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateGroupRoles provides a mock function with given fields: ctx, groupId, add, remove
+func (_m *mockDataAccessAccountRepository) UpdateGroupRoles(ctx context.Context, groupId string, add []string, remove []string) error {
+	ret := _m.Called(ctx, groupId, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateGroupRoles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, []string) error); ok {
+		r0 = rf(ctx, groupId, add, remove)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessAccountRepository_UpdateGroupRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateGroupRoles'
+type mockDataAccessAccountRepository_UpdateGroupRoles_Call struct {
+	*mock.Call
+}
+
+// UpdateGroupRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - groupId string
+//   - add []string
+//   - remove []string
+func (_e *mockDataAccessAccountRepository_Expecter) UpdateGroupRoles(ctx interface{}, groupId interface{}, add interface{}, remove interface{}) *mockDataAccessAccountRepository_UpdateGroupRoles_Call {
+	return &mockDataAccessAccountRepository_UpdateGroupRoles_Call{Call: _e.mock.On("UpdateGroupRoles", ctx, groupId, add, remove)}
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateGroupRoles_Call) Run(run func(ctx context.Context, groupId string, add []string, remove []string)) *mockDataAccessAccountRepository_UpdateGroupRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateGroupRoles_Call) Return(_a0 error) *mockDataAccessAccountRepository_UpdateGroupRoles_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateGroupRoles_Call) RunAndReturn(run func(context.Context, string, []string, []string) error) *mockDataAccessAccountRepository_UpdateGroupRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateMetastoreOwner provides a mock function with given fields: ctx, metastoreId, owner
+func (_m *mockDataAccessAccountRepository) UpdateMetastoreOwner(ctx context.Context, metastoreId string, owner string) error {
+	ret := _m.Called(ctx, metastoreId, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMetastoreOwner")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, metastoreId, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessAccountRepository_UpdateMetastoreOwner_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMetastoreOwner'
+type mockDataAccessAccountRepository_UpdateMetastoreOwner_Call struct {
+	*mock.Call
+}
+
+// UpdateMetastoreOwner is a helper method to define mock.On call
+//   - ctx context.Context
+//   - metastoreId string
+//   - owner string
+func (_e *mockDataAccessAccountRepository_Expecter) UpdateMetastoreOwner(ctx interface{}, metastoreId interface{}, owner interface{}) *mockDataAccessAccountRepository_UpdateMetastoreOwner_Call {
+	return &mockDataAccessAccountRepository_UpdateMetastoreOwner_Call{Call: _e.mock.On("UpdateMetastoreOwner", ctx, metastoreId, owner)}
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateMetastoreOwner_Call) Run(run func(ctx context.Context, metastoreId string, owner string)) *mockDataAccessAccountRepository_UpdateMetastoreOwner_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateMetastoreOwner_Call) Return(_a0 error) *mockDataAccessAccountRepository_UpdateMetastoreOwner_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateMetastoreOwner_Call) RunAndReturn(run func(context.Context, string, string) error) *mockDataAccessAccountRepository_UpdateMetastoreOwner_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateServicePrincipalRoles provides a mock function with given fields: ctx, servicePrincipalId, add, remove
+func (_m *mockDataAccessAccountRepository) UpdateServicePrincipalRoles(ctx context.Context, servicePrincipalId string, add []string, remove []string) error {
+	ret := _m.Called(ctx, servicePrincipalId, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateServicePrincipalRoles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, []string) error); ok {
+		r0 = rf(ctx, servicePrincipalId, add, remove)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateServicePrincipalRoles'
+type mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call struct {
+	*mock.Call
+}
+
+// UpdateServicePrincipalRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - servicePrincipalId string
+//   - add []string
+//   - remove []string
+func (_e *mockDataAccessAccountRepository_Expecter) UpdateServicePrincipalRoles(ctx interface{}, servicePrincipalId interface{}, add interface{}, remove interface{}) *mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call {
+	return &mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call{Call: _e.mock.On("UpdateServicePrincipalRoles", ctx, servicePrincipalId, add, remove)}
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call) Run(run func(ctx context.Context, servicePrincipalId string, add []string, remove []string)) *mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call) Return(_a0 error) *mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call) RunAndReturn(run func(context.Context, string, []string, []string) error) *mockDataAccessAccountRepository_UpdateServicePrincipalRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserRoles provides a mock function with given fields: ctx, userId, add, remove
+func (_m *mockDataAccessAccountRepository) UpdateUserRoles(ctx context.Context, userId string, add []string, remove []string) error {
+	ret := _m.Called(ctx, userId, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserRoles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, []string) error); ok {
+		r0 = rf(ctx, userId, add, remove)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessAccountRepository_UpdateUserRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserRoles'
+type mockDataAccessAccountRepository_UpdateUserRoles_Call struct {
+	*mock.Call
+}
+
+// UpdateUserRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - add []string
+//   - remove []string
+func (_e *mockDataAccessAccountRepository_Expecter) UpdateUserRoles(ctx interface{}, userId interface{}, add interface{}, remove interface{}) *mockDataAccessAccountRepository_UpdateUserRoles_Call {
+	return &mockDataAccessAccountRepository_UpdateUserRoles_Call{Call: _e.mock.On("UpdateUserRoles", ctx, userId, add, remove)}
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateUserRoles_Call) Run(run func(ctx context.Context, userId string, add []string, remove []string)) *mockDataAccessAccountRepository_UpdateUserRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateUserRoles_Call) Return(_a0 error) *mockDataAccessAccountRepository_UpdateUserRoles_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessAccountRepository_UpdateUserRoles_Call) RunAndReturn(run func(context.Context, string, []string, []string) error) *mockDataAccessAccountRepository_UpdateUserRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateWorkspaceAssignment provides a mock function with given fields: ctx, workspaceId, principalId, permission
 func (_m *mockDataAccessAccountRepository) UpdateWorkspaceAssignment(ctx context.Context, workspaceId int64, principalId int64, permission []iam.WorkspacePermission) error {
 	ret := _m.Called(ctx, workspaceId, principalId, permission)
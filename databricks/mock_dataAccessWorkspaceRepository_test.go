@@ -7,9 +7,13 @@ import (
 
 	catalog "github.com/databricks/databricks-sdk-go/service/catalog"
 
+	iam "github.com/databricks/databricks-sdk-go/service/iam"
+
 	mock "github.com/stretchr/testify/mock"
 
 	repo "cli-plugin-databricks/databricks/repo"
+
+	types "cli-plugin-databricks/databricks/repo/types"
 )
 
 // mockDataAccessWorkspaceRepository is an autogenerated mock type for the dataAccessWorkspaceRepository type
@@ -84,6 +88,66 @@ func (_c *mockDataAccessWorkspaceRepository_GetCatalogWorkspaceBinding_Call) Run
 	return _c
 }
 
+// GetEffectivePermissionsOnResource provides a mock function with given fields: ctx, securableType, fullName
+func (_m *mockDataAccessWorkspaceRepository) GetEffectivePermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string) (*catalog.EffectivePermissionsList, error) {
+	ret := _m.Called(ctx, securableType, fullName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEffectivePermissionsOnResource")
+	}
+
+	var r0 *catalog.EffectivePermissionsList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, catalog.SecurableType, string) (*catalog.EffectivePermissionsList, error)); ok {
+		return rf(ctx, securableType, fullName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, catalog.SecurableType, string) *catalog.EffectivePermissionsList); ok {
+		r0 = rf(ctx, securableType, fullName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*catalog.EffectivePermissionsList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, catalog.SecurableType, string) error); ok {
+		r1 = rf(ctx, securableType, fullName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEffectivePermissionsOnResource'
+type mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call struct {
+	*mock.Call
+}
+
+// GetEffectivePermissionsOnResource is a helper method to define mock.On call
+//   - ctx context.Context
+//   - securableType catalog.SecurableType
+//   - fullName string
+func (_e *mockDataAccessWorkspaceRepository_Expecter) GetEffectivePermissionsOnResource(ctx interface{}, securableType interface{}, fullName interface{}) *mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call {
+	return &mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call{Call: _e.mock.On("GetEffectivePermissionsOnResource", ctx, securableType, fullName)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call) Run(run func(ctx context.Context, securableType catalog.SecurableType, fullName string)) *mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(catalog.SecurableType), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call) Return(_a0 *catalog.EffectivePermissionsList, _a1 error) *mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call) RunAndReturn(run func(context.Context, catalog.SecurableType, string) (*catalog.EffectivePermissionsList, error)) *mockDataAccessWorkspaceRepository_GetEffectivePermissionsOnResource_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetOwner provides a mock function with given fields: ctx, securableType, fullName
 func (_m *mockDataAccessWorkspaceRepository) GetOwner(ctx context.Context, securableType catalog.SecurableType, fullName string) (string, error) {
 	ret := _m.Called(ctx, securableType, fullName)
@@ -310,6 +374,66 @@ func (_c *mockDataAccessWorkspaceRepository_ListCatalogs_Call) RunAndReturn(run
 	return _c
 }
 
+// ListEntityTagAssignments provides a mock function with given fields: ctx, entityType, entityName
+func (_m *mockDataAccessWorkspaceRepository) ListEntityTagAssignments(ctx context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error) {
+	ret := _m.Called(ctx, entityType, entityName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEntityTagAssignments")
+	}
+
+	var r0 []types.EntityTagAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]types.EntityTagAssignment, error)); ok {
+		return rf(ctx, entityType, entityName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []types.EntityTagAssignment); ok {
+		r0 = rf(ctx, entityType, entityName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.EntityTagAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, entityType, entityName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEntityTagAssignments'
+type mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call struct {
+	*mock.Call
+}
+
+// ListEntityTagAssignments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entityType string
+//   - entityName string
+func (_e *mockDataAccessWorkspaceRepository_Expecter) ListEntityTagAssignments(ctx interface{}, entityType interface{}, entityName interface{}) *mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call {
+	return &mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call{Call: _e.mock.On("ListEntityTagAssignments", ctx, entityType, entityName)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call) Run(run func(ctx context.Context, entityType string, entityName string)) *mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call) Return(_a0 []types.EntityTagAssignment, _a1 error) *mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call) RunAndReturn(run func(context.Context, string, string) ([]types.EntityTagAssignment, error)) *mockDataAccessWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListFunctions provides a mock function with given fields: ctx, catalogName, schemaName
 func (_m *mockDataAccessWorkspaceRepository) ListFunctions(ctx context.Context, catalogName string, schemaName string) <-chan repo.ChannelItem[catalog.FunctionInfo] {
 	ret := _m.Called(ctx, catalogName, schemaName)
@@ -360,6 +484,69 @@ func (_c *mockDataAccessWorkspaceRepository_ListFunctions_Call) RunAndReturn(run
 	return _c
 }
 
+// ListGroups provides a mock function with given fields: ctx, optFn
+func (_m *mockDataAccessWorkspaceRepository) ListGroups(ctx context.Context, optFn ...func(*types.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
+	_va := make([]interface{}, len(optFn))
+	for _i := range optFn {
+		_va[_i] = optFn[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListGroups")
+	}
+
+	var r0 <-chan repo.ChannelItem[iam.Group]
+	if rf, ok := ret.Get(0).(func(context.Context, ...func(*types.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group]); ok {
+		r0 = rf(ctx, optFn...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan repo.ChannelItem[iam.Group])
+		}
+	}
+
+	return r0
+}
+
+// mockDataAccessWorkspaceRepository_ListGroups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListGroups'
+type mockDataAccessWorkspaceRepository_ListGroups_Call struct {
+	*mock.Call
+}
+
+// ListGroups is a helper method to define mock.On call
+//   - ctx context.Context
+//   - optFn ...func(*types.DatabricksGroupsFilter)
+func (_e *mockDataAccessWorkspaceRepository_Expecter) ListGroups(ctx interface{}, optFn ...interface{}) *mockDataAccessWorkspaceRepository_ListGroups_Call {
+	return &mockDataAccessWorkspaceRepository_ListGroups_Call{Call: _e.mock.On("ListGroups",
+		append([]interface{}{ctx}, optFn...)...)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListGroups_Call) Run(run func(ctx context.Context, optFn ...func(*types.DatabricksGroupsFilter))) *mockDataAccessWorkspaceRepository_ListGroups_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*types.DatabricksGroupsFilter), len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*types.DatabricksGroupsFilter))
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListGroups_Call) Return(_a0 <-chan repo.ChannelItem[iam.Group]) *mockDataAccessWorkspaceRepository_ListGroups_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListGroups_Call) RunAndReturn(run func(context.Context, ...func(*types.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group]) *mockDataAccessWorkspaceRepository_ListGroups_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListSchemas provides a mock function with given fields: ctx, catalogName
 func (_m *mockDataAccessWorkspaceRepository) ListSchemas(ctx context.Context, catalogName string) <-chan repo.ChannelItem[catalog.SchemaInfo] {
 	ret := _m.Called(ctx, catalogName)
@@ -409,6 +596,250 @@ func (_c *mockDataAccessWorkspaceRepository_ListSchemas_Call) RunAndReturn(run f
 	return _c
 }
 
+// ListServicePrincipals provides a mock function with given fields: ctx, optFn
+func (_m *mockDataAccessWorkspaceRepository) ListServicePrincipals(ctx context.Context, optFn ...func(*types.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal] {
+	_va := make([]interface{}, len(optFn))
+	for _i := range optFn {
+		_va[_i] = optFn[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListServicePrincipals")
+	}
+
+	var r0 <-chan repo.ChannelItem[iam.ServicePrincipal]
+	if rf, ok := ret.Get(0).(func(context.Context, ...func(*types.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal]); ok {
+		r0 = rf(ctx, optFn...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan repo.ChannelItem[iam.ServicePrincipal])
+		}
+	}
+
+	return r0
+}
+
+// mockDataAccessWorkspaceRepository_ListServicePrincipals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListServicePrincipals'
+type mockDataAccessWorkspaceRepository_ListServicePrincipals_Call struct {
+	*mock.Call
+}
+
+// ListServicePrincipals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - optFn ...func(*types.DatabricksServicePrincipalFilter)
+func (_e *mockDataAccessWorkspaceRepository_Expecter) ListServicePrincipals(ctx interface{}, optFn ...interface{}) *mockDataAccessWorkspaceRepository_ListServicePrincipals_Call {
+	return &mockDataAccessWorkspaceRepository_ListServicePrincipals_Call{Call: _e.mock.On("ListServicePrincipals",
+		append([]interface{}{ctx}, optFn...)...)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListServicePrincipals_Call) Run(run func(ctx context.Context, optFn ...func(*types.DatabricksServicePrincipalFilter))) *mockDataAccessWorkspaceRepository_ListServicePrincipals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*types.DatabricksServicePrincipalFilter), len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*types.DatabricksServicePrincipalFilter))
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListServicePrincipals_Call) Return(_a0 <-chan repo.ChannelItem[iam.ServicePrincipal]) *mockDataAccessWorkspaceRepository_ListServicePrincipals_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListServicePrincipals_Call) RunAndReturn(run func(context.Context, ...func(*types.DatabricksServicePrincipalFilter)) <-chan repo.ChannelItem[iam.ServicePrincipal]) *mockDataAccessWorkspaceRepository_ListServicePrincipals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTableSummaries provides a mock function with given fields: ctx, catalogName, schemaName
+func (_m *mockDataAccessWorkspaceRepository) ListTableSummaries(ctx context.Context, catalogName string, schemaName string) ([]catalog.TableInfo, error) {
+	ret := _m.Called(ctx, catalogName, schemaName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTableSummaries")
+	}
+
+	var r0 []catalog.TableInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]catalog.TableInfo, error)); ok {
+		return rf(ctx, catalogName, schemaName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []catalog.TableInfo); ok {
+		r0 = rf(ctx, catalogName, schemaName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]catalog.TableInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, catalogName, schemaName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataAccessWorkspaceRepository_ListTableSummaries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTableSummaries'
+type mockDataAccessWorkspaceRepository_ListTableSummaries_Call struct {
+	*mock.Call
+}
+
+// ListTableSummaries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - catalogName string
+//   - schemaName string
+func (_e *mockDataAccessWorkspaceRepository_Expecter) ListTableSummaries(ctx interface{}, catalogName interface{}, schemaName interface{}) *mockDataAccessWorkspaceRepository_ListTableSummaries_Call {
+	return &mockDataAccessWorkspaceRepository_ListTableSummaries_Call{Call: _e.mock.On("ListTableSummaries", ctx, catalogName, schemaName)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListTableSummaries_Call) Run(run func(ctx context.Context, catalogName string, schemaName string)) *mockDataAccessWorkspaceRepository_ListTableSummaries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListTableSummaries_Call) Return(_a0 []catalog.TableInfo, _a1 error) *mockDataAccessWorkspaceRepository_ListTableSummaries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListTableSummaries_Call) RunAndReturn(run func(context.Context, string, string) ([]catalog.TableInfo, error)) *mockDataAccessWorkspaceRepository_ListTableSummaries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUsers provides a mock function with given fields: ctx, optFn
+func (_m *mockDataAccessWorkspaceRepository) ListUsers(ctx context.Context, optFn ...func(*types.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User] {
+	_va := make([]interface{}, len(optFn))
+	for _i := range optFn {
+		_va[_i] = optFn[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 <-chan repo.ChannelItem[iam.User]
+	if rf, ok := ret.Get(0).(func(context.Context, ...func(*types.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User]); ok {
+		r0 = rf(ctx, optFn...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan repo.ChannelItem[iam.User])
+		}
+	}
+
+	return r0
+}
+
+// mockDataAccessWorkspaceRepository_ListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsers'
+type mockDataAccessWorkspaceRepository_ListUsers_Call struct {
+	*mock.Call
+}
+
+// ListUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - optFn ...func(*types.DatabricksUsersFilter)
+func (_e *mockDataAccessWorkspaceRepository_Expecter) ListUsers(ctx interface{}, optFn ...interface{}) *mockDataAccessWorkspaceRepository_ListUsers_Call {
+	return &mockDataAccessWorkspaceRepository_ListUsers_Call{Call: _e.mock.On("ListUsers",
+		append([]interface{}{ctx}, optFn...)...)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListUsers_Call) Run(run func(ctx context.Context, optFn ...func(*types.DatabricksUsersFilter))) *mockDataAccessWorkspaceRepository_ListUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*types.DatabricksUsersFilter), len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*types.DatabricksUsersFilter))
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListUsers_Call) Return(_a0 <-chan repo.ChannelItem[iam.User]) *mockDataAccessWorkspaceRepository_ListUsers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_ListUsers_Call) RunAndReturn(run func(context.Context, ...func(*types.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User]) *mockDataAccessWorkspaceRepository_ListUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Me provides a mock function with given fields: ctx
+func (_m *mockDataAccessWorkspaceRepository) Me(ctx context.Context) (*iam.User, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Me")
+	}
+
+	var r0 *iam.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*iam.User, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *iam.User); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*iam.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataAccessWorkspaceRepository_Me_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Me'
+type mockDataAccessWorkspaceRepository_Me_Call struct {
+	*mock.Call
+}
+
+// Me is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockDataAccessWorkspaceRepository_Expecter) Me(ctx interface{}) *mockDataAccessWorkspaceRepository_Me_Call {
+	return &mockDataAccessWorkspaceRepository_Me_Call{Call: _e.mock.On("Me", ctx)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_Me_Call) Run(run func(ctx context.Context)) *mockDataAccessWorkspaceRepository_Me_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_Me_Call) Return(_a0 *iam.User, _a1 error) *mockDataAccessWorkspaceRepository_Me_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_Me_Call) RunAndReturn(run func(context.Context) (*iam.User, error)) *mockDataAccessWorkspaceRepository_Me_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Ping provides a mock function with given fields: ctx
 func (_m *mockDataAccessWorkspaceRepository) Ping(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -566,6 +997,153 @@ func (_c *mockDataAccessWorkspaceRepository_SqlWarehouseRepository_Call) RunAndR
 	return _c
 }
 
+// UpdateGroupEntitlements provides a mock function with given fields: ctx, groupId, add, remove
+func (_m *mockDataAccessWorkspaceRepository) UpdateGroupEntitlements(ctx context.Context, groupId string, add []string, remove []string) error {
+	ret := _m.Called(ctx, groupId, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateGroupEntitlements")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, []string) error); ok {
+		r0 = rf(ctx, groupId, add, remove)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateGroupEntitlements'
+type mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call struct {
+	*mock.Call
+}
+
+// UpdateGroupEntitlements is a helper method to define mock.On call
+//   - ctx context.Context
+//   - groupId string
+//   - add []string
+//   - remove []string
+func (_e *mockDataAccessWorkspaceRepository_Expecter) UpdateGroupEntitlements(ctx interface{}, groupId interface{}, add interface{}, remove interface{}) *mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call {
+	return &mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call{Call: _e.mock.On("UpdateGroupEntitlements", ctx, groupId, add, remove)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call) Run(run func(ctx context.Context, groupId string, add []string, remove []string)) *mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call) Return(_a0 error) *mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call) RunAndReturn(run func(context.Context, string, []string, []string) error) *mockDataAccessWorkspaceRepository_UpdateGroupEntitlements_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateServicePrincipalEntitlements provides a mock function with given fields: ctx, servicePrincipalId, add, remove
+func (_m *mockDataAccessWorkspaceRepository) UpdateServicePrincipalEntitlements(ctx context.Context, servicePrincipalId string, add []string, remove []string) error {
+	ret := _m.Called(ctx, servicePrincipalId, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateServicePrincipalEntitlements")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, []string) error); ok {
+		r0 = rf(ctx, servicePrincipalId, add, remove)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateServicePrincipalEntitlements'
+type mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call struct {
+	*mock.Call
+}
+
+// UpdateServicePrincipalEntitlements is a helper method to define mock.On call
+//   - ctx context.Context
+//   - servicePrincipalId string
+//   - add []string
+//   - remove []string
+func (_e *mockDataAccessWorkspaceRepository_Expecter) UpdateServicePrincipalEntitlements(ctx interface{}, servicePrincipalId interface{}, add interface{}, remove interface{}) *mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call {
+	return &mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call{Call: _e.mock.On("UpdateServicePrincipalEntitlements", ctx, servicePrincipalId, add, remove)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call) Run(run func(ctx context.Context, servicePrincipalId string, add []string, remove []string)) *mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call) Return(_a0 error) *mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call) RunAndReturn(run func(context.Context, string, []string, []string) error) *mockDataAccessWorkspaceRepository_UpdateServicePrincipalEntitlements_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserEntitlements provides a mock function with given fields: ctx, userId, add, remove
+func (_m *mockDataAccessWorkspaceRepository) UpdateUserEntitlements(ctx context.Context, userId string, add []string, remove []string) error {
+	ret := _m.Called(ctx, userId, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserEntitlements")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, []string) error); ok {
+		r0 = rf(ctx, userId, add, remove)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserEntitlements'
+type mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call struct {
+	*mock.Call
+}
+
+// UpdateUserEntitlements is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - add []string
+//   - remove []string
+func (_e *mockDataAccessWorkspaceRepository_Expecter) UpdateUserEntitlements(ctx interface{}, userId interface{}, add interface{}, remove interface{}) *mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call {
+	return &mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call{Call: _e.mock.On("UpdateUserEntitlements", ctx, userId, add, remove)}
+}
+
+func (_c *mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call) Run(run func(ctx context.Context, userId string, add []string, remove []string)) *mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call) Return(_a0 error) *mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call) RunAndReturn(run func(context.Context, string, []string, []string) error) *mockDataAccessWorkspaceRepository_UpdateUserEntitlements_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockDataAccessWorkspaceRepository creates a new instance of mockDataAccessWorkspaceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockDataAccessWorkspaceRepository(t interface {
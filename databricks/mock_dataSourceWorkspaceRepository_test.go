@@ -12,6 +12,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	repo "cli-plugin-databricks/databricks/repo"
+
+	types "cli-plugin-databricks/databricks/repo/types"
 )
 
 // mockDataSourceWorkspaceRepository is an autogenerated mock type for the dataSourceWorkspaceRepository type
@@ -27,6 +29,66 @@ func (_m *mockDataSourceWorkspaceRepository) EXPECT() *mockDataSourceWorkspaceRe
 	return &mockDataSourceWorkspaceRepository_Expecter{mock: &_m.Mock}
 }
 
+// GetPermissionsOnResource provides a mock function with given fields: ctx, securableType, fullName
+func (_m *mockDataSourceWorkspaceRepository) GetPermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string) (*catalog.PermissionsList, error) {
+	ret := _m.Called(ctx, securableType, fullName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPermissionsOnResource")
+	}
+
+	var r0 *catalog.PermissionsList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, catalog.SecurableType, string) (*catalog.PermissionsList, error)); ok {
+		return rf(ctx, securableType, fullName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, catalog.SecurableType, string) *catalog.PermissionsList); ok {
+		r0 = rf(ctx, securableType, fullName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*catalog.PermissionsList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, catalog.SecurableType, string) error); ok {
+		r1 = rf(ctx, securableType, fullName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPermissionsOnResource'
+type mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call struct {
+	*mock.Call
+}
+
+// GetPermissionsOnResource is a helper method to define mock.On call
+//   - ctx context.Context
+//   - securableType catalog.SecurableType
+//   - fullName string
+func (_e *mockDataSourceWorkspaceRepository_Expecter) GetPermissionsOnResource(ctx interface{}, securableType interface{}, fullName interface{}) *mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call {
+	return &mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call{Call: _e.mock.On("GetPermissionsOnResource", ctx, securableType, fullName)}
+}
+
+func (_c *mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call) Run(run func(ctx context.Context, securableType catalog.SecurableType, fullName string)) *mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(catalog.SecurableType), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call) Return(_a0 *catalog.PermissionsList, _a1 error) *mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call) RunAndReturn(run func(context.Context, catalog.SecurableType, string) (*catalog.PermissionsList, error)) *mockDataSourceWorkspaceRepository_GetPermissionsOnResource_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListAllTables provides a mock function with given fields: ctx, catalogName, schemaName
 func (_m *mockDataSourceWorkspaceRepository) ListAllTables(ctx context.Context, catalogName string, schemaName string) ([]catalog.TableInfo, error) {
 	ret := _m.Called(ctx, catalogName, schemaName)
@@ -135,6 +197,66 @@ func (_c *mockDataSourceWorkspaceRepository_ListCatalogs_Call) RunAndReturn(run
 	return _c
 }
 
+// ListEntityTagAssignments provides a mock function with given fields: ctx, entityType, entityName
+func (_m *mockDataSourceWorkspaceRepository) ListEntityTagAssignments(ctx context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error) {
+	ret := _m.Called(ctx, entityType, entityName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEntityTagAssignments")
+	}
+
+	var r0 []types.EntityTagAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]types.EntityTagAssignment, error)); ok {
+		return rf(ctx, entityType, entityName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []types.EntityTagAssignment); ok {
+		r0 = rf(ctx, entityType, entityName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.EntityTagAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, entityType, entityName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEntityTagAssignments'
+type mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call struct {
+	*mock.Call
+}
+
+// ListEntityTagAssignments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entityType string
+//   - entityName string
+func (_e *mockDataSourceWorkspaceRepository_Expecter) ListEntityTagAssignments(ctx interface{}, entityType interface{}, entityName interface{}) *mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call {
+	return &mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call{Call: _e.mock.On("ListEntityTagAssignments", ctx, entityType, entityName)}
+}
+
+func (_c *mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call) Run(run func(ctx context.Context, entityType string, entityName string)) *mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call) Return(_a0 []types.EntityTagAssignment, _a1 error) *mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call) RunAndReturn(run func(context.Context, string, string) ([]types.EntityTagAssignment, error)) *mockDataSourceWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListFunctions provides a mock function with given fields: ctx, catalogName, schemaName
 func (_m *mockDataSourceWorkspaceRepository) ListFunctions(ctx context.Context, catalogName string, schemaName string) <-chan repo.ChannelItem[catalog.FunctionInfo] {
 	ret := _m.Called(ctx, catalogName, schemaName)
@@ -234,6 +356,66 @@ func (_c *mockDataSourceWorkspaceRepository_ListSchemas_Call) RunAndReturn(run f
 	return _c
 }
 
+// ListTableSummaries provides a mock function with given fields: ctx, catalogName, schemaName
+func (_m *mockDataSourceWorkspaceRepository) ListTableSummaries(ctx context.Context, catalogName string, schemaName string) ([]catalog.TableInfo, error) {
+	ret := _m.Called(ctx, catalogName, schemaName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTableSummaries")
+	}
+
+	var r0 []catalog.TableInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]catalog.TableInfo, error)); ok {
+		return rf(ctx, catalogName, schemaName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []catalog.TableInfo); ok {
+		r0 = rf(ctx, catalogName, schemaName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]catalog.TableInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, catalogName, schemaName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataSourceWorkspaceRepository_ListTableSummaries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTableSummaries'
+type mockDataSourceWorkspaceRepository_ListTableSummaries_Call struct {
+	*mock.Call
+}
+
+// ListTableSummaries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - catalogName string
+//   - schemaName string
+func (_e *mockDataSourceWorkspaceRepository_Expecter) ListTableSummaries(ctx interface{}, catalogName interface{}, schemaName interface{}) *mockDataSourceWorkspaceRepository_ListTableSummaries_Call {
+	return &mockDataSourceWorkspaceRepository_ListTableSummaries_Call{Call: _e.mock.On("ListTableSummaries", ctx, catalogName, schemaName)}
+}
+
+func (_c *mockDataSourceWorkspaceRepository_ListTableSummaries_Call) Run(run func(ctx context.Context, catalogName string, schemaName string)) *mockDataSourceWorkspaceRepository_ListTableSummaries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataSourceWorkspaceRepository_ListTableSummaries_Call) Return(_a0 []catalog.TableInfo, _a1 error) *mockDataSourceWorkspaceRepository_ListTableSummaries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataSourceWorkspaceRepository_ListTableSummaries_Call) RunAndReturn(run func(context.Context, string, string) ([]catalog.TableInfo, error)) *mockDataSourceWorkspaceRepository_ListTableSummaries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Me provides a mock function with given fields: ctx
 func (_m *mockDataSourceWorkspaceRepository) Me(ctx context.Context) (*iam.User, error) {
 	ret := _m.Called(ctx)
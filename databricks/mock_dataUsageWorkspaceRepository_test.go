@@ -14,6 +14,8 @@ import (
 	sql "github.com/databricks/databricks-sdk-go/service/sql"
 
 	time "time"
+
+	types "cli-plugin-databricks/databricks/repo/types"
 )
 
 // mockDataUsageWorkspaceRepository is an autogenerated mock type for the dataUsageWorkspaceRepository type
@@ -77,6 +79,66 @@ func (_c *mockDataUsageWorkspaceRepository_ListCatalogs_Call) RunAndReturn(run f
 	return _c
 }
 
+// ListEntityTagAssignments provides a mock function with given fields: ctx, entityType, entityName
+func (_m *mockDataUsageWorkspaceRepository) ListEntityTagAssignments(ctx context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error) {
+	ret := _m.Called(ctx, entityType, entityName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEntityTagAssignments")
+	}
+
+	var r0 []types.EntityTagAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]types.EntityTagAssignment, error)); ok {
+		return rf(ctx, entityType, entityName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []types.EntityTagAssignment); ok {
+		r0 = rf(ctx, entityType, entityName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.EntityTagAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, entityType, entityName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEntityTagAssignments'
+type mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call struct {
+	*mock.Call
+}
+
+// ListEntityTagAssignments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entityType string
+//   - entityName string
+func (_e *mockDataUsageWorkspaceRepository_Expecter) ListEntityTagAssignments(ctx interface{}, entityType interface{}, entityName interface{}) *mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call {
+	return &mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call{Call: _e.mock.On("ListEntityTagAssignments", ctx, entityType, entityName)}
+}
+
+func (_c *mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call) Run(run func(ctx context.Context, entityType string, entityName string)) *mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call) Return(_a0 []types.EntityTagAssignment, _a1 error) *mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call) RunAndReturn(run func(context.Context, string, string) ([]types.EntityTagAssignment, error)) *mockDataUsageWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListSchemas provides a mock function with given fields: ctx, catalogName
 func (_m *mockDataUsageWorkspaceRepository) ListSchemas(ctx context.Context, catalogName string) <-chan repo.ChannelItem[catalog.SchemaInfo] {
 	ret := _m.Called(ctx, catalogName)
@@ -6,8 +6,11 @@ import (
 	context "context"
 
 	iam "github.com/databricks/databricks-sdk-go/service/iam"
+
 	mock "github.com/stretchr/testify/mock"
 
+	provisioning "github.com/databricks/databricks-sdk-go/service/provisioning"
+
 	repo "cli-plugin-databricks/databricks/repo"
 
 	types "cli-plugin-databricks/databricks/repo/types"
@@ -26,6 +29,64 @@ func (_m *mockIdentityStoreAccountRepository) EXPECT() *mockIdentityStoreAccount
 	return &mockIdentityStoreAccountRepository_Expecter{mock: &_m.Mock}
 }
 
+// GetWorkspaces provides a mock function with given fields: ctx
+func (_m *mockIdentityStoreAccountRepository) GetWorkspaces(ctx context.Context) ([]provisioning.Workspace, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkspaces")
+	}
+
+	var r0 []provisioning.Workspace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]provisioning.Workspace, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []provisioning.Workspace); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]provisioning.Workspace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockIdentityStoreAccountRepository_GetWorkspaces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWorkspaces'
+type mockIdentityStoreAccountRepository_GetWorkspaces_Call struct {
+	*mock.Call
+}
+
+// GetWorkspaces is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockIdentityStoreAccountRepository_Expecter) GetWorkspaces(ctx interface{}) *mockIdentityStoreAccountRepository_GetWorkspaces_Call {
+	return &mockIdentityStoreAccountRepository_GetWorkspaces_Call{Call: _e.mock.On("GetWorkspaces", ctx)}
+}
+
+func (_c *mockIdentityStoreAccountRepository_GetWorkspaces_Call) Run(run func(ctx context.Context)) *mockIdentityStoreAccountRepository_GetWorkspaces_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *mockIdentityStoreAccountRepository_GetWorkspaces_Call) Return(_a0 []provisioning.Workspace, _a1 error) *mockIdentityStoreAccountRepository_GetWorkspaces_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockIdentityStoreAccountRepository_GetWorkspaces_Call) RunAndReturn(run func(context.Context) ([]provisioning.Workspace, error)) *mockIdentityStoreAccountRepository_GetWorkspaces_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListGroups provides a mock function with given fields: ctx, optFn
 func (_m *mockIdentityStoreAccountRepository) ListGroups(ctx context.Context, optFn ...func(*types.DatabricksGroupsFilter)) <-chan repo.ChannelItem[iam.Group] {
 	_va := make([]interface{}, len(optFn))
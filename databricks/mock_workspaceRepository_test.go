@@ -10,6 +10,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	repo "cli-plugin-databricks/databricks/repo"
+
+	types "cli-plugin-databricks/databricks/repo/types"
 )
 
 // mockWorkspaceRepository is an autogenerated mock type for the workspaceRepository type
@@ -133,6 +135,66 @@ func (_c *mockWorkspaceRepository_ListCatalogs_Call) RunAndReturn(run func(conte
 	return _c
 }
 
+// ListEntityTagAssignments provides a mock function with given fields: ctx, entityType, entityName
+func (_m *mockWorkspaceRepository) ListEntityTagAssignments(ctx context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error) {
+	ret := _m.Called(ctx, entityType, entityName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEntityTagAssignments")
+	}
+
+	var r0 []types.EntityTagAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]types.EntityTagAssignment, error)); ok {
+		return rf(ctx, entityType, entityName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []types.EntityTagAssignment); ok {
+		r0 = rf(ctx, entityType, entityName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.EntityTagAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, entityType, entityName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockWorkspaceRepository_ListEntityTagAssignments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEntityTagAssignments'
+type mockWorkspaceRepository_ListEntityTagAssignments_Call struct {
+	*mock.Call
+}
+
+// ListEntityTagAssignments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entityType string
+//   - entityName string
+func (_e *mockWorkspaceRepository_Expecter) ListEntityTagAssignments(ctx interface{}, entityType interface{}, entityName interface{}) *mockWorkspaceRepository_ListEntityTagAssignments_Call {
+	return &mockWorkspaceRepository_ListEntityTagAssignments_Call{Call: _e.mock.On("ListEntityTagAssignments", ctx, entityType, entityName)}
+}
+
+func (_c *mockWorkspaceRepository_ListEntityTagAssignments_Call) Run(run func(ctx context.Context, entityType string, entityName string)) *mockWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockWorkspaceRepository_ListEntityTagAssignments_Call) Return(_a0 []types.EntityTagAssignment, _a1 error) *mockWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockWorkspaceRepository_ListEntityTagAssignments_Call) RunAndReturn(run func(context.Context, string, string) ([]types.EntityTagAssignment, error)) *mockWorkspaceRepository_ListEntityTagAssignments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListFunctions provides a mock function with given fields: ctx, catalogName, schemaName
 func (_m *mockWorkspaceRepository) ListFunctions(ctx context.Context, catalogName string, schemaName string) <-chan repo.ChannelItem[catalog.FunctionInfo] {
 	ret := _m.Called(ctx, catalogName, schemaName)
@@ -232,6 +294,66 @@ func (_c *mockWorkspaceRepository_ListSchemas_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// ListTableSummaries provides a mock function with given fields: ctx, catalogName, schemaName
+func (_m *mockWorkspaceRepository) ListTableSummaries(ctx context.Context, catalogName string, schemaName string) ([]catalog.TableInfo, error) {
+	ret := _m.Called(ctx, catalogName, schemaName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTableSummaries")
+	}
+
+	var r0 []catalog.TableInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]catalog.TableInfo, error)); ok {
+		return rf(ctx, catalogName, schemaName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []catalog.TableInfo); ok {
+		r0 = rf(ctx, catalogName, schemaName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]catalog.TableInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, catalogName, schemaName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockWorkspaceRepository_ListTableSummaries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTableSummaries'
+type mockWorkspaceRepository_ListTableSummaries_Call struct {
+	*mock.Call
+}
+
+// ListTableSummaries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - catalogName string
+//   - schemaName string
+func (_e *mockWorkspaceRepository_Expecter) ListTableSummaries(ctx interface{}, catalogName interface{}, schemaName interface{}) *mockWorkspaceRepository_ListTableSummaries_Call {
+	return &mockWorkspaceRepository_ListTableSummaries_Call{Call: _e.mock.On("ListTableSummaries", ctx, catalogName, schemaName)}
+}
+
+func (_c *mockWorkspaceRepository_ListTableSummaries_Call) Run(run func(ctx context.Context, catalogName string, schemaName string)) *mockWorkspaceRepository_ListTableSummaries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockWorkspaceRepository_ListTableSummaries_Call) Return(_a0 []catalog.TableInfo, _a1 error) *mockWorkspaceRepository_ListTableSummaries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockWorkspaceRepository_ListTableSummaries_Call) RunAndReturn(run func(context.Context, string, string) ([]catalog.TableInfo, error)) *mockWorkspaceRepository_ListTableSummaries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockWorkspaceRepository creates a new instance of mockWorkspaceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockWorkspaceRepository(t interface {
@@ -0,0 +1,86 @@
+package platform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+)
+
+const (
+	DeploymentPlaceholder    = "{deployment}"
+	WorkspaceIdPlaceholder   = "{workspace_id}"
+	WorkspaceNamePlaceholder = "{workspace_name}"
+)
+
+// Endpoints overrides the default hosts of a Databricks platform.
+// This is required for government and sovereign clouds, private link and custom workspace URLs.
+type Endpoints struct {
+	AccountHost           string            // Host of the account console, the default host of the platform if empty
+	WorkspaceHostTemplate string            // Template of the workspace hosts, e.g. https://{deployment}.cloud.databricks.us
+	WorkspaceHosts        map[string]string // Explicit workspace hosts by workspace id or deployment name
+}
+
+// ResolveAccountHost returns the host of the account console of the given platform
+func (e *Endpoints) ResolveAccountHost(p DatabricksPlatform) (string, error) {
+	if e.AccountHost != "" {
+		return normalizeHost(e.AccountHost), nil
+	}
+
+	return p.Host()
+}
+
+// ResolveWorkspaceHost returns the host of the given workspace.
+// The boolean indicates if the host is overridden, rather than derived from the default domain of the platform.
+func (e *Endpoints) ResolveWorkspaceHost(p DatabricksPlatform, workspace *provisioning.Workspace) (string, bool, error) {
+	if host, found := e.WorkspaceHosts[strconv.FormatInt(workspace.WorkspaceId, 10)]; found {
+		return normalizeHost(host), true, nil
+	}
+
+	if host, found := e.WorkspaceHosts[workspace.DeploymentName]; found {
+		return normalizeHost(host), true, nil
+	}
+
+	if e.WorkspaceHostTemplate != "" {
+		host := strings.NewReplacer(
+			DeploymentPlaceholder, workspace.DeploymentName,
+			WorkspaceIdPlaceholder, strconv.FormatInt(workspace.WorkspaceId, 10),
+			WorkspaceNamePlaceholder, workspace.WorkspaceName,
+		).Replace(e.WorkspaceHostTemplate)
+
+		return normalizeHost(host), true, nil
+	}
+
+	host, err := p.WorkspaceAddress(workspace.DeploymentName)
+	if err != nil {
+		return "", false, err
+	}
+
+	return host, false, nil
+}
+
+// Validate returns an error if the workspace host template would resolve all workspaces to the same host
+func (e *Endpoints) Validate() error {
+	if e.WorkspaceHostTemplate == "" {
+		return nil
+	}
+
+	for _, placeholder := range []string{DeploymentPlaceholder, WorkspaceIdPlaceholder, WorkspaceNamePlaceholder} {
+		if strings.Contains(e.WorkspaceHostTemplate, placeholder) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("workspace host template %q should contain %s, %s or %s", e.WorkspaceHostTemplate, DeploymentPlaceholder, WorkspaceIdPlaceholder, WorkspaceNamePlaceholder)
+}
+
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.TrimSpace(host), "/")
+
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	return host
+}
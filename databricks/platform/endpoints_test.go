@@ -0,0 +1,153 @@
+package platform
+
+import (
+	"testing"
+
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+)
+
+func TestEndpoints_ResolveAccountHost(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints Endpoints
+		p         DatabricksPlatform
+		want      string
+	}{
+		{
+			name:      "Default",
+			endpoints: Endpoints{},
+			p:         DatabricksPlatformAWS,
+			want:      "https://accounts.cloud.databricks.com",
+		},
+		{
+			name:      "Override",
+			endpoints: Endpoints{AccountHost: "https://accounts.cloud.databricks.us/"},
+			p:         DatabricksPlatformAWS,
+			want:      "https://accounts.cloud.databricks.us",
+		},
+		{
+			name:      "Override without scheme",
+			endpoints: Endpoints{AccountHost: "accounts.azuredatabricks.cn"},
+			p:         DatabricksPlatformAzure,
+			want:      "https://accounts.azuredatabricks.cn",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.endpoints.ResolveAccountHost(tt.p)
+			if err != nil {
+				t.Errorf("ResolveAccountHost() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ResolveAccountHost() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpoints_ResolveWorkspaceHost(t *testing.T) {
+	workspace := &provisioning.Workspace{
+		WorkspaceId:    1234,
+		WorkspaceName:  "workspace1",
+		DeploymentName: "deployment1",
+	}
+
+	tests := []struct {
+		name           string
+		endpoints      Endpoints
+		want           string
+		wantOverridden bool
+	}{
+		{
+			name:           "Default",
+			endpoints:      Endpoints{},
+			want:           "https://deployment1.cloud.databricks.com",
+			wantOverridden: false,
+		},
+		{
+			name:           "Template",
+			endpoints:      Endpoints{WorkspaceHostTemplate: "https://{deployment}.cloud.databricks.us"},
+			want:           "https://deployment1.cloud.databricks.us",
+			wantOverridden: true,
+		},
+		{
+			name:           "Template with workspace id and name",
+			endpoints:      Endpoints{WorkspaceHostTemplate: "{workspace_name}-{workspace_id}.example.com"},
+			want:           "https://workspace1-1234.example.com",
+			wantOverridden: true,
+		},
+		{
+			name: "Workspace id",
+			endpoints: Endpoints{
+				WorkspaceHostTemplate: "https://{deployment}.cloud.databricks.us",
+				WorkspaceHosts:        map[string]string{"1234": "https://workspace1.privatelink.example.com"},
+			},
+			want:           "https://workspace1.privatelink.example.com",
+			wantOverridden: true,
+		},
+		{
+			name: "Deployment name",
+			endpoints: Endpoints{
+				WorkspaceHosts: map[string]string{"deployment1": "https://workspace1.privatelink.example.com"},
+			},
+			want:           "https://workspace1.privatelink.example.com",
+			wantOverridden: true,
+		},
+		{
+			name: "Other workspace",
+			endpoints: Endpoints{
+				WorkspaceHosts: map[string]string{"5678": "https://workspace2.privatelink.example.com"},
+			},
+			want:           "https://deployment1.cloud.databricks.com",
+			wantOverridden: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, overridden, err := tt.endpoints.ResolveWorkspaceHost(DatabricksPlatformAWS, workspace)
+			if err != nil {
+				t.Errorf("ResolveWorkspaceHost() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ResolveWorkspaceHost() got = %v, want %v", got, tt.want)
+			}
+			if overridden != tt.wantOverridden {
+				t.Errorf("ResolveWorkspaceHost() overridden = %v, want %v", overridden, tt.wantOverridden)
+			}
+		})
+	}
+}
+
+func TestEndpoints_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints Endpoints
+		wantErr   bool
+	}{
+		{
+			name:      "No template",
+			endpoints: Endpoints{},
+			wantErr:   false,
+		},
+		{
+			name:      "Template with placeholder",
+			endpoints: Endpoints{WorkspaceHostTemplate: "https://{workspace_id}.example.com"},
+			wantErr:   false,
+		},
+		{
+			name:      "Template without placeholder",
+			endpoints: Endpoints{WorkspaceHostTemplate: "https://workspace.example.com"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.endpoints.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package databricks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/databricks/databricks-sdk-go/service/iam"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+	"github.com/raito-io/cli/base/util/config"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/platform"
+	"cli-plugin-databricks/databricks/repo"
+	types2 "cli-plugin-databricks/databricks/repo/types"
+	"cli-plugin-databricks/databricks/types"
+	"cli-plugin-databricks/databricks/utils"
+)
+
+const workspaceAdminGroup = "admins"
+
+var errPreflightStop = errors.New("stop")
+
+//go:generate go run github.com/vektra/mockery/v2 --name=preflightAccountRepository
+type preflightAccountRepository interface {
+	ListMetastores(ctx context.Context) ([]catalog.MetastoreInfo, error)
+	GetWorkspaces(ctx context.Context) ([]provisioning.Workspace, error)
+	GetWorkspaceMap(ctx context.Context, metastores []catalog.MetastoreInfo, workspaces []provisioning.Workspace) (map[string][]*provisioning.Workspace, map[string]string, error)
+	ListUsers(ctx context.Context, optFn ...func(options *types2.DatabricksUsersFilter)) <-chan repo.ChannelItem[iam.User]
+}
+
+//go:generate go run github.com/vektra/mockery/v2 --name=preflightWorkspaceRepository
+type preflightWorkspaceRepository interface {
+	Me(ctx context.Context) (*iam.User, error)
+	ListCatalogs(ctx context.Context) <-chan repo.ChannelItem[catalog.CatalogInfo]
+	GetPermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string) (*catalog.PermissionsList, error)
+	QueryHistory(ctx context.Context, startTime *time.Time, f func(context.Context, *sql.QueryInfo) error) error
+	GetWarehousePermissions(ctx context.Context, warehouseId string) (*sql.WarehousePermissions, error)
+}
+
+type PreflightStatus int
+
+const (
+	PreflightSkipped PreflightStatus = iota
+	PreflightOk
+	PreflightWarning
+	PreflightFailed
+)
+
+func (s PreflightStatus) String() string {
+	switch s {
+	case PreflightOk:
+		return "OK"
+	case PreflightWarning:
+		return "WARNING"
+	case PreflightFailed:
+		return "FAILED"
+	default:
+		return "-"
+	}
+}
+
+// PreflightSync is a sync type of the compatibility matrix
+type PreflightSync string
+
+const (
+	PreflightDataSourceSync    PreflightSync = "DATA SOURCE"
+	PreflightIdentityStoreSync PreflightSync = "IDENTITY STORE"
+	PreflightAccessImport      PreflightSync = "ACCESS IMPORT"
+	PreflightAccessExport      PreflightSync = "ACCESS EXPORT"
+	PreflightDataUsageSync     PreflightSync = "DATA USAGE"
+	PreflightSqlWarehouse      PreflightSync = "SQL WAREHOUSE" // Tags, masks, filters and lineage
+)
+
+var preflightSyncs = []PreflightSync{PreflightDataSourceSync, PreflightIdentityStoreSync, PreflightAccessImport, PreflightAccessExport, PreflightDataUsageSync, PreflightSqlWarehouse}
+
+type PreflightCheck struct {
+	Scope   string // The account or workspace that is checked
+	Name    string
+	Status  PreflightStatus
+	Message string
+}
+
+// PreflightWorkspace is a row of the compatibility matrix
+type PreflightWorkspace struct {
+	Workspace string
+	Metastore string
+	Syncs     map[PreflightSync]PreflightStatus
+}
+
+type PreflightReport struct {
+	Checks     []PreflightCheck
+	Workspaces []PreflightWorkspace
+}
+
+// Failed returns true if any of the checks failed
+func (r *PreflightReport) Failed() bool {
+	return slices.ContainsFunc(r.Checks, func(check PreflightCheck) bool {
+		return check.Status == PreflightFailed
+	})
+}
+
+// Print writes the checks and the compatibility matrix of the workspaces
+func (r *PreflightReport) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "SCOPE\tCHECK\tSTATUS\tMESSAGE")
+
+	for _, check := range r.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", check.Scope, check.Name, check.Status, check.Message)
+	}
+
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+
+	header := []string{"WORKSPACE", "METASTORE"}
+	for _, sync := range preflightSyncs {
+		header = append(header, string(sync))
+	}
+
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, workspace := range r.Workspaces {
+		row := []string{workspace.Workspace, workspace.Metastore}
+		for _, sync := range preflightSyncs {
+			row = append(row, workspace.Syncs[sync].String())
+		}
+
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+func (r *PreflightReport) add(scope string, name string, status PreflightStatus, message string) PreflightStatus {
+	r.Checks = append(r.Checks, PreflightCheck{Scope: scope, Name: name, Status: status, Message: message})
+
+	return status
+}
+
+func (r *PreflightReport) addResult(scope string, name string, err error) PreflightStatus {
+	if err != nil {
+		return r.add(scope, name, PreflightFailed, err.Error())
+	}
+
+	return r.add(scope, name, PreflightOk, "")
+}
+
+// PreflightChecker checks the effective rights of the plugin principal, to detect missing permissions before running a sync
+type PreflightChecker struct {
+	accountRepoFactory   func(pltfrm platform.DatabricksPlatform, accountId string, repoCredentials *types2.RepositoryCredentials) (preflightAccountRepository, error)
+	workspaceRepoFactory func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (preflightWorkspaceRepository, error)
+}
+
+func NewPreflightChecker() *PreflightChecker {
+	return &PreflightChecker{
+		accountRepoFactory: func(pltfrm platform.DatabricksPlatform, accountId string, repoCredentials *types2.RepositoryCredentials) (preflightAccountRepository, error) {
+			return repo.NewAccountRepository(pltfrm, repoCredentials, accountId)
+		},
+		workspaceRepoFactory: func(repoCredentials *types2.RepositoryCredentials, workspaceId int64) (preflightWorkspaceRepository, error) {
+			return repo.NewWorkspaceRepository(repoCredentials, workspaceId)
+		},
+	}
+}
+
+func (p *PreflightChecker) Run(ctx context.Context, configMap *config.ConfigMap) (*PreflightReport, error) {
+	pltfrm, accountId, repoCredentials, err := utils.GetAndValidateParameters(configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceFilter, err := NewObjectFilter(configMap.GetString(constants.DatabricksExcludeWorkspaces), configMap.GetString(constants.DatabricksIncludeWorkspaces))
+	if err != nil {
+		return nil, fmt.Errorf("workspace filter: %w", err)
+	}
+
+	metastoreFilter, err := NewObjectFilter(configMap.GetString(constants.DatabricksExcludeMetastores), configMap.GetString(constants.DatabricksIncludeMetastores))
+	if err != nil {
+		return nil, fmt.Errorf("metastore filter: %w", err)
+	}
+
+	var warehouses []types.WarehouseDetails
+
+	if _, err = configMap.Unmarshal(constants.DatabricksSqlWarehouses, &warehouses); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", constants.DatabricksSqlWarehouses, err)
+	}
+
+	accountRepo, err := p.accountRepoFactory(pltfrm, accountId, &repoCredentials)
+	if err != nil {
+		return nil, fmt.Errorf("account repository factory: %w", err)
+	}
+
+	report := &PreflightReport{}
+	accountScope := fmt.Sprintf("account %s", accountId)
+
+	metastores, err := accountRepo.ListMetastores(ctx)
+	accountStatus := report.addResult(accountScope, "list metastores", err)
+
+	workspaces, err := accountRepo.GetWorkspaces(ctx)
+	accountStatus = max(accountStatus, report.addResult(accountScope, "list workspaces", err))
+
+	if accountStatus == PreflightFailed {
+		return report, nil
+	}
+
+	_, workspaceToMetastoreMap, err := accountRepo.GetWorkspaceMap(ctx, metastores, workspaces)
+	if report.addResult(accountScope, "list metastore assignments", err) == PreflightFailed {
+		return report, nil
+	}
+
+	usersStatus := report.addResult(accountScope, "list users", firstChannelItemError(ctx, func(ctx context.Context) <-chan repo.ChannelItem[iam.User] {
+		return accountRepo.ListUsers(ctx, func(options *types2.DatabricksUsersFilter) { options.IdsOnly = true })
+	}))
+
+	metastoreMap := make(map[string]*catalog.MetastoreInfo)
+	for i := range metastores {
+		metastoreMap[metastores[i].MetastoreId] = &metastores[i]
+	}
+
+	for i := range workspaces {
+		workspace := &workspaces[i]
+
+		if !workspaceFilter.IncludeObject(workspace.WorkspaceName) {
+			continue
+		}
+
+		metastore, found := metastoreMap[workspaceToMetastoreMap[workspace.DeploymentName]]
+		if !found {
+			report.add(workspace.WorkspaceName, "metastore assignment", PreflightWarning, "workspace is not running or no metastore is assigned, so the workspace is ignored")
+
+			continue
+		}
+
+		if !metastoreFilter.IncludeObject(metastore.Name) {
+			continue
+		}
+
+		warehouseId := ""
+
+		for _, warehouse := range warehouses {
+			if warehouse.Workspace == workspace.DeploymentName {
+				warehouseId = warehouse.Warehouse
+
+				break
+			}
+		}
+
+		syncs := p.checkWorkspace(ctx, report, pltfrm, repoCredentials, workspace, metastore, warehouseId)
+		syncs[PreflightIdentityStoreSync] = max(syncs[PreflightIdentityStoreSync], usersStatus)
+
+		report.Workspaces = append(report.Workspaces, PreflightWorkspace{
+			Workspace: workspace.WorkspaceName,
+			Metastore: metastore.Name,
+			Syncs:     syncs,
+		})
+	}
+
+	return report, nil
+}
+
+func (p *PreflightChecker) checkWorkspace(ctx context.Context, report *PreflightReport, pltfrm platform.DatabricksPlatform, repoCredentials types2.RepositoryCredentials, workspace *provisioning.Workspace, metastore *catalog.MetastoreInfo, warehouseId string) map[PreflightSync]PreflightStatus {
+	scope := workspace.WorkspaceName
+	syncs := make(map[PreflightSync]PreflightStatus)
+
+	failAll := func() map[PreflightSync]PreflightStatus {
+		for _, sync := range preflightSyncs {
+			syncs[sync] = PreflightFailed
+		}
+
+		if warehouseId == "" {
+			syncs[PreflightSqlWarehouse] = PreflightSkipped
+		}
+
+		return syncs
+	}
+
+	credentials, err := utils.InitializeWorkspaceRepoCredentials(repoCredentials, pltfrm, workspace)
+	if report.addResult(scope, "credentials", err) == PreflightFailed {
+		return failAll()
+	}
+
+	workspaceRepo, err := p.workspaceRepoFactory(credentials, workspace.WorkspaceId)
+	if report.addResult(scope, "workspace repository", err) == PreflightFailed {
+		return failAll()
+	}
+
+	me, err := workspaceRepo.Me(ctx)
+	if err != nil {
+		report.add(scope, "authenticate", PreflightFailed, err.Error())
+
+		return failAll()
+	}
+
+	report.add(scope, "authenticate", PreflightOk, fmt.Sprintf("authenticated as %s", me.UserName))
+
+	admin := isPrincipal(me, workspaceAdminGroup)
+	if admin {
+		syncs[PreflightIdentityStoreSync] = report.add(scope, "workspace admin", PreflightOk, "")
+	} else {
+		syncs[PreflightIdentityStoreSync] = report.add(scope, "workspace admin", PreflightWarning, "not a workspace admin, so workspace entitlements and workspace-local groups can not be managed")
+	}
+
+	syncs[PreflightDataSourceSync] = report.addResult(scope, "list catalogs", firstChannelItemError(ctx, workspaceRepo.ListCatalogs))
+
+	_, err = workspaceRepo.GetPermissionsOnResource(ctx, catalog.SecurableTypeMetastore, metastore.MetastoreId)
+	syncs[PreflightAccessImport] = report.addResult(scope, fmt.Sprintf("read grants of metastore %s", metastore.Name), err)
+
+	syncs[PreflightAccessExport] = syncs[PreflightAccessImport]
+	if isPrincipal(me, metastore.Owner) {
+		report.add(scope, fmt.Sprintf("owner of metastore %s", metastore.Name), PreflightOk, "")
+	} else {
+		syncs[PreflightAccessExport] = max(syncs[PreflightAccessExport], report.add(scope, fmt.Sprintf("owner of metastore %s", metastore.Name), PreflightWarning, fmt.Sprintf("the metastore is owned by %s, so only grants on securables owned by or with MANAGE for %s can be changed", metastore.Owner, me.UserName)))
+	}
+
+	syncs[PreflightDataUsageSync] = p.checkQueryHistory(ctx, report, scope, workspaceRepo, admin)
+
+	if warehouseId == "" {
+		report.add(scope, "sql warehouse", PreflightSkipped, fmt.Sprintf("no warehouse configured in %s", constants.DatabricksSqlWarehouses))
+	} else {
+		syncs[PreflightSqlWarehouse] = p.checkWarehouse(ctx, report, scope, workspaceRepo, me, warehouseId)
+	}
+
+	return syncs
+}
+
+func (p *PreflightChecker) checkQueryHistory(ctx context.Context, report *PreflightReport, scope string, workspaceRepo preflightWorkspaceRepository, admin bool) PreflightStatus {
+	startTime := time.Now().Add(-24 * time.Hour)
+
+	err := workspaceRepo.QueryHistory(ctx, &startTime, func(_ context.Context, _ *sql.QueryInfo) error {
+		return errPreflightStop
+	})
+	if err != nil && !errors.Is(err, errPreflightStop) {
+		return report.add(scope, "read query history", PreflightFailed, err.Error())
+	}
+
+	if !admin {
+		return report.add(scope, "read query history", PreflightWarning, "not a workspace admin, so only the own queries are in the query history")
+	}
+
+	return report.add(scope, "read query history", PreflightOk, "")
+}
+
+func (p *PreflightChecker) checkWarehouse(ctx context.Context, report *PreflightReport, scope string, workspaceRepo preflightWorkspaceRepository, me *iam.User, warehouseId string) PreflightStatus {
+	name := fmt.Sprintf("CAN_USE on sql warehouse %s", warehouseId)
+
+	permissions, err := workspaceRepo.GetWarehousePermissions(ctx, warehouseId)
+	if err != nil {
+		return report.add(scope, name, PreflightFailed, err.Error())
+	}
+
+	for _, acl := range permissions.AccessControlList {
+		principal := acl.UserName + acl.ServicePrincipalName + acl.GroupName
+		if !isPrincipal(me, principal) {
+			continue
+		}
+
+		for _, permission := range acl.AllPermissions {
+			switch permission.PermissionLevel { //nolint:exhaustive
+			case sql.WarehousePermissionLevelCanUse, sql.WarehousePermissionLevelCanManage, sql.WarehousePermissionLevelIsOwner:
+				return report.add(scope, name, PreflightOk, fmt.Sprintf("%s through %s", permission.PermissionLevel, principal))
+			}
+		}
+	}
+
+	return report.add(scope, name, PreflightFailed, fmt.Sprintf("%s has no CAN_USE, CAN_MANAGE or IS_OWNER on the warehouse", me.UserName))
+}
+
+// isPrincipal returns true if the principal is the current user or service principal, or one of its groups
+func isPrincipal(me *iam.User, principal string) bool {
+	if principal == "" {
+		return false
+	}
+
+	if me.UserName == principal {
+		return true
+	}
+
+	return slices.ContainsFunc(me.Groups, func(group iam.ComplexValue) bool {
+		return group.Display == principal
+	})
+}
+
+// firstChannelItemError returns the error of the first item of the channel, and stops the listing afterward
+func firstChannelItemError[T any](ctx context.Context, list func(ctx context.Context) <-chan repo.ChannelItem[T]) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for item := range list(ctx) {
+		return item.Err
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/service/catalog"
+	"github.com/databricks/databricks-sdk-go/service/iam"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	"github.com/databricks/databricks-sdk-go/service/sql"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/platform"
+	"cli-plugin-databricks/databricks/repo"
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+func TestPreflightChecker_Run(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:     "AccountId",
+			constants.DatabricksPlatform:      "AWS",
+			constants.DatabricksToken:         "token",
+			constants.DatabricksSqlWarehouses: `[{"workspace": "deployment1", "warehouse": "warehouse1"}]`,
+		},
+	}
+
+	metastores := []catalog.MetastoreInfo{{MetastoreId: "metastore-id", Name: "metastore1", Owner: "metastore-owners"}}
+	workspaces := []provisioning.Workspace{
+		{WorkspaceId: 1, WorkspaceName: "workspace1", DeploymentName: "deployment1", WorkspaceStatus: "RUNNING"},
+		{WorkspaceId: 2, WorkspaceName: "workspace2", DeploymentName: "deployment2", WorkspaceStatus: "RUNNING"},
+		{WorkspaceId: 3, WorkspaceName: "workspace3", DeploymentName: "deployment3", WorkspaceStatus: "FAILED"},
+	}
+
+	accountRepo := newMockPreflightAccountRepository(t)
+	accountRepo.EXPECT().ListMetastores(mock.Anything).Return(metastores, nil)
+	accountRepo.EXPECT().GetWorkspaces(mock.Anything).Return(workspaces, nil)
+	accountRepo.EXPECT().GetWorkspaceMap(mock.Anything, metastores, workspaces).Return(nil, map[string]string{"deployment1": "metastore-id", "deployment2": "metastore-id"}, nil)
+	accountRepo.EXPECT().ListUsers(mock.Anything, mock.Anything).Return(repo.ArrayToChannel([]iam.User{{Id: "user1"}}))
+
+	workspace1Repo := newMockPreflightWorkspaceRepository(t)
+	workspace1Repo.EXPECT().Me(mock.Anything).Return(&iam.User{UserName: "plugin-sp", Groups: []iam.ComplexValue{{Display: "admins"}, {Display: "metastore-owners"}}}, nil)
+	workspace1Repo.EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel([]catalog.CatalogInfo{{Name: "catalog1"}}))
+	workspace1Repo.EXPECT().GetPermissionsOnResource(mock.Anything, catalog.SecurableTypeMetastore, "metastore-id").Return(&catalog.PermissionsList{}, nil)
+	workspace1Repo.EXPECT().QueryHistory(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, _ *time.Time, f func(context.Context, *sql.QueryInfo) error) error {
+		return f(ctx, &sql.QueryInfo{QueryId: "query1"})
+	})
+	workspace1Repo.EXPECT().GetWarehousePermissions(mock.Anything, "warehouse1").Return(&sql.WarehousePermissions{
+		AccessControlList: []sql.WarehouseAccessControlResponse{
+			{UserName: "other-user", AllPermissions: []sql.WarehousePermission{{PermissionLevel: sql.WarehousePermissionLevelCanUse}}},
+			{GroupName: "admins", AllPermissions: []sql.WarehousePermission{{PermissionLevel: sql.WarehousePermissionLevelCanManage, Inherited: true}}},
+		},
+	}, nil)
+
+	workspace2Repo := newMockPreflightWorkspaceRepository(t)
+	workspace2Repo.EXPECT().Me(mock.Anything).Return(&iam.User{UserName: "plugin-sp"}, nil)
+	workspace2Repo.EXPECT().ListCatalogs(mock.Anything).Return(repo.ArrayToChannel([]catalog.CatalogInfo{{Name: "catalog1"}}))
+	workspace2Repo.EXPECT().GetPermissionsOnResource(mock.Anything, catalog.SecurableTypeMetastore, "metastore-id").Return(nil, errors.New("PERMISSION_DENIED"))
+	workspace2Repo.EXPECT().QueryHistory(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	workspaceRepos := map[int64]*mockPreflightWorkspaceRepository{1: workspace1Repo, 2: workspace2Repo}
+
+	checker := PreflightChecker{
+		accountRepoFactory: func(pltfrm platform.DatabricksPlatform, accountId string, repoCredentials *types.RepositoryCredentials) (preflightAccountRepository, error) {
+			return accountRepo, nil
+		},
+		workspaceRepoFactory: func(repoCredentials *types.RepositoryCredentials, workspaceId int64) (preflightWorkspaceRepository, error) {
+			return workspaceRepos[workspaceId], nil
+		},
+	}
+
+	// When
+	report, err := checker.Run(ctx, configMap)
+
+	// Then
+	require.NoError(t, err)
+
+	assert.Equal(t, []PreflightWorkspace{
+		{
+			Workspace: "workspace1",
+			Metastore: "metastore1",
+			Syncs: map[PreflightSync]PreflightStatus{
+				PreflightDataSourceSync:    PreflightOk,
+				PreflightIdentityStoreSync: PreflightOk,
+				PreflightAccessImport:      PreflightOk,
+				PreflightAccessExport:      PreflightOk,
+				PreflightDataUsageSync:     PreflightOk,
+				PreflightSqlWarehouse:      PreflightOk,
+			},
+		},
+		{
+			Workspace: "workspace2",
+			Metastore: "metastore1",
+			Syncs: map[PreflightSync]PreflightStatus{
+				PreflightDataSourceSync:    PreflightOk,
+				PreflightIdentityStoreSync: PreflightWarning,
+				PreflightAccessImport:      PreflightFailed,
+				PreflightAccessExport:      PreflightFailed,
+				PreflightDataUsageSync:     PreflightWarning,
+			},
+		},
+	}, report.Workspaces)
+
+	assert.True(t, report.Failed())
+	assert.Contains(t, report.Checks, PreflightCheck{Scope: "workspace2", Name: "read grants of metastore metastore1", Status: PreflightFailed, Message: "PERMISSION_DENIED"})
+	assert.Contains(t, report.Checks, PreflightCheck{Scope: "workspace3", Name: "metastore assignment", Status: PreflightWarning, Message: "workspace is not running or no metastore is assigned, so the workspace is ignored"})
+	assert.Contains(t, report.Checks, PreflightCheck{Scope: "workspace1", Name: "CAN_USE on sql warehouse warehouse1", Status: PreflightOk, Message: "CAN_MANAGE through admins"})
+
+	var output bytes.Buffer
+	require.NoError(t, report.Print(&output))
+	assert.Contains(t, output.String(), "WORKSPACE   METASTORE   DATA SOURCE  IDENTITY STORE  ACCESS IMPORT  ACCESS EXPORT  DATA USAGE  SQL WAREHOUSE")
+	assert.Contains(t, output.String(), "workspace2  metastore1  OK           WARNING         FAILED         FAILED         WARNING     -")
+}
+
+func TestPreflightChecker_Run_AccountFailure(t *testing.T) {
+	// Given
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId: "AccountId",
+			constants.DatabricksPlatform:  "AWS",
+		},
+	}
+
+	accountRepo := newMockPreflightAccountRepository(t)
+	accountRepo.EXPECT().ListMetastores(mock.Anything).Return(nil, errors.New("invalid credentials"))
+	accountRepo.EXPECT().GetWorkspaces(mock.Anything).Return(nil, errors.New("invalid credentials"))
+
+	checker := PreflightChecker{
+		accountRepoFactory: func(pltfrm platform.DatabricksPlatform, accountId string, repoCredentials *types.RepositoryCredentials) (preflightAccountRepository, error) {
+			return accountRepo, nil
+		},
+	}
+
+	// When
+	report, err := checker.Run(context.Background(), configMap)
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, report.Failed())
+	assert.Empty(t, report.Workspaces)
+	assert.Equal(t, []PreflightCheck{
+		{Scope: "account AccountId", Name: "list metastores", Status: PreflightFailed, Message: "invalid credentials"},
+		{Scope: "account AccountId", Name: "list workspaces", Status: PreflightFailed, Message: "invalid credentials"},
+	}, report.Checks)
+}
@@ -8,6 +8,8 @@ import (
 	sql "github.com/databricks/databricks-sdk-go/service/sql"
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	types "cli-plugin-databricks/databricks/repo/types"
 )
 
@@ -24,6 +26,54 @@ func (_m *MockWarehouseRepository) EXPECT() *MockWarehouseRepository_Expecter {
 	return &MockWarehouseRepository_Expecter{mock: &_m.Mock}
 }
 
+// CreateOrReplacePolicy provides a mock function with given fields: ctx, catalog, policy
+func (_m *MockWarehouseRepository) CreateOrReplacePolicy(ctx context.Context, catalog string, policy *types.AbacPolicy) error {
+	ret := _m.Called(ctx, catalog, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrReplacePolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *types.AbacPolicy) error); ok {
+		r0 = rf(ctx, catalog, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWarehouseRepository_CreateOrReplacePolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrReplacePolicy'
+type MockWarehouseRepository_CreateOrReplacePolicy_Call struct {
+	*mock.Call
+}
+
+// CreateOrReplacePolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - catalog string
+//   - policy *types.AbacPolicy
+func (_e *MockWarehouseRepository_Expecter) CreateOrReplacePolicy(ctx interface{}, catalog interface{}, policy interface{}) *MockWarehouseRepository_CreateOrReplacePolicy_Call {
+	return &MockWarehouseRepository_CreateOrReplacePolicy_Call{Call: _e.mock.On("CreateOrReplacePolicy", ctx, catalog, policy)}
+}
+
+func (_c *MockWarehouseRepository_CreateOrReplacePolicy_Call) Run(run func(ctx context.Context, catalog string, policy *types.AbacPolicy)) *MockWarehouseRepository_CreateOrReplacePolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*types.AbacPolicy))
+	})
+	return _c
+}
+
+func (_c *MockWarehouseRepository_CreateOrReplacePolicy_Call) Return(_a0 error) *MockWarehouseRepository_CreateOrReplacePolicy_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWarehouseRepository_CreateOrReplacePolicy_Call) RunAndReturn(run func(context.Context, string, *types.AbacPolicy) error) *MockWarehouseRepository_CreateOrReplacePolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DropFunction provides a mock function with given fields: ctx, catalog, schema, functionName
 func (_m *MockWarehouseRepository) DropFunction(ctx context.Context, catalog string, schema string, functionName string) error {
 	ret := _m.Called(ctx, catalog, schema, functionName)
@@ -123,6 +173,56 @@ func (_c *MockWarehouseRepository_DropMask_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// DropPolicy provides a mock function with given fields: ctx, catalog, name, securableType, securableName
+func (_m *MockWarehouseRepository) DropPolicy(ctx context.Context, catalog string, name string, securableType string, securableName string) error {
+	ret := _m.Called(ctx, catalog, name, securableType, securableName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DropPolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, catalog, name, securableType, securableName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWarehouseRepository_DropPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DropPolicy'
+type MockWarehouseRepository_DropPolicy_Call struct {
+	*mock.Call
+}
+
+// DropPolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - catalog string
+//   - name string
+//   - securableType string
+//   - securableName string
+func (_e *MockWarehouseRepository_Expecter) DropPolicy(ctx interface{}, catalog interface{}, name interface{}, securableType interface{}, securableName interface{}) *MockWarehouseRepository_DropPolicy_Call {
+	return &MockWarehouseRepository_DropPolicy_Call{Call: _e.mock.On("DropPolicy", ctx, catalog, name, securableType, securableName)}
+}
+
+func (_c *MockWarehouseRepository_DropPolicy_Call) Run(run func(ctx context.Context, catalog string, name string, securableType string, securableName string)) *MockWarehouseRepository_DropPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockWarehouseRepository_DropPolicy_Call) Return(_a0 error) *MockWarehouseRepository_DropPolicy_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWarehouseRepository_DropPolicy_Call) RunAndReturn(run func(context.Context, string, string, string, string) error) *MockWarehouseRepository_DropPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DropRowFilter provides a mock function with given fields: ctx, catalog, schema, table
 func (_m *MockWarehouseRepository) DropRowFilter(ctx context.Context, catalog string, schema string, table string) error {
 	ret := _m.Called(ctx, catalog, schema, table)
@@ -248,6 +348,115 @@ func (_c *MockWarehouseRepository_ExecuteStatement_Call) RunAndReturn(run func(c
 	return _c
 }
 
+// GetColumnLineage provides a mock function with given fields: ctx, since, fn
+func (_m *MockWarehouseRepository) GetColumnLineage(ctx context.Context, since time.Time, fn func(context.Context, string, string, string, string) error) error {
+	ret := _m.Called(ctx, since, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetColumnLineage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, func(context.Context, string, string, string, string) error) error); ok {
+		r0 = rf(ctx, since, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWarehouseRepository_GetColumnLineage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetColumnLineage'
+type MockWarehouseRepository_GetColumnLineage_Call struct {
+	*mock.Call
+}
+
+// GetColumnLineage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - since time.Time
+//   - fn func(context.Context, string, string, string, string) error
+func (_e *MockWarehouseRepository_Expecter) GetColumnLineage(ctx interface{}, since interface{}, fn interface{}) *MockWarehouseRepository_GetColumnLineage_Call {
+	return &MockWarehouseRepository_GetColumnLineage_Call{Call: _e.mock.On("GetColumnLineage", ctx, since, fn)}
+}
+
+func (_c *MockWarehouseRepository_GetColumnLineage_Call) Run(run func(ctx context.Context, since time.Time, fn func(context.Context, string, string, string, string) error)) *MockWarehouseRepository_GetColumnLineage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(func(context.Context, string, string, string, string) error))
+	})
+	return _c
+}
+
+func (_c *MockWarehouseRepository_GetColumnLineage_Call) Return(_a0 error) *MockWarehouseRepository_GetColumnLineage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWarehouseRepository_GetColumnLineage_Call) RunAndReturn(run func(context.Context, time.Time, func(context.Context, string, string, string, string) error) error) *MockWarehouseRepository_GetColumnLineage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTableDetail provides a mock function with given fields: ctx, catalog, schema, tableName
+func (_m *MockWarehouseRepository) GetTableDetail(ctx context.Context, catalog string, schema string, tableName string) (*types.TableDetail, error) {
+	ret := _m.Called(ctx, catalog, schema, tableName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTableDetail")
+	}
+
+	var r0 *types.TableDetail
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*types.TableDetail, error)); ok {
+		return rf(ctx, catalog, schema, tableName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *types.TableDetail); ok {
+		r0 = rf(ctx, catalog, schema, tableName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.TableDetail)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, catalog, schema, tableName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWarehouseRepository_GetTableDetail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTableDetail'
+type MockWarehouseRepository_GetTableDetail_Call struct {
+	*mock.Call
+}
+
+// GetTableDetail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - catalog string
+//   - schema string
+//   - tableName string
+func (_e *MockWarehouseRepository_Expecter) GetTableDetail(ctx interface{}, catalog interface{}, schema interface{}, tableName interface{}) *MockWarehouseRepository_GetTableDetail_Call {
+	return &MockWarehouseRepository_GetTableDetail_Call{Call: _e.mock.On("GetTableDetail", ctx, catalog, schema, tableName)}
+}
+
+func (_c *MockWarehouseRepository_GetTableDetail_Call) Run(run func(ctx context.Context, catalog string, schema string, tableName string)) *MockWarehouseRepository_GetTableDetail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockWarehouseRepository_GetTableDetail_Call) Return(_a0 *types.TableDetail, _a1 error) *MockWarehouseRepository_GetTableDetail_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWarehouseRepository_GetTableDetail_Call) RunAndReturn(run func(context.Context, string, string, string) (*types.TableDetail, error)) *MockWarehouseRepository_GetTableDetail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTableInformation provides a mock function with given fields: ctx, catalog, schema, tableName
 func (_m *MockWarehouseRepository) GetTableInformation(ctx context.Context, catalog string, schema string, tableName string) (map[string]*types.ColumnInformation, error) {
 	ret := _m.Called(ctx, catalog, schema, tableName)
@@ -309,6 +518,114 @@ func (_c *MockWarehouseRepository_GetTableInformation_Call) RunAndReturn(run fun
 	return _c
 }
 
+// GetTableLineage provides a mock function with given fields: ctx, since, fn
+func (_m *MockWarehouseRepository) GetTableLineage(ctx context.Context, since time.Time, fn func(context.Context, string, string) error) error {
+	ret := _m.Called(ctx, since, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTableLineage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, func(context.Context, string, string) error) error); ok {
+		r0 = rf(ctx, since, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWarehouseRepository_GetTableLineage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTableLineage'
+type MockWarehouseRepository_GetTableLineage_Call struct {
+	*mock.Call
+}
+
+// GetTableLineage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - since time.Time
+//   - fn func(context.Context, string, string) error
+func (_e *MockWarehouseRepository_Expecter) GetTableLineage(ctx interface{}, since interface{}, fn interface{}) *MockWarehouseRepository_GetTableLineage_Call {
+	return &MockWarehouseRepository_GetTableLineage_Call{Call: _e.mock.On("GetTableLineage", ctx, since, fn)}
+}
+
+func (_c *MockWarehouseRepository_GetTableLineage_Call) Run(run func(ctx context.Context, since time.Time, fn func(context.Context, string, string) error)) *MockWarehouseRepository_GetTableLineage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(func(context.Context, string, string) error))
+	})
+	return _c
+}
+
+func (_c *MockWarehouseRepository_GetTableLineage_Call) Return(_a0 error) *MockWarehouseRepository_GetTableLineage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWarehouseRepository_GetTableLineage_Call) RunAndReturn(run func(context.Context, time.Time, func(context.Context, string, string) error) error) *MockWarehouseRepository_GetTableLineage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTaggedColumns provides a mock function with given fields: ctx, catalog, schema
+func (_m *MockWarehouseRepository) GetTaggedColumns(ctx context.Context, catalog string, schema string) ([]types.TaggedColumn, error) {
+	ret := _m.Called(ctx, catalog, schema)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaggedColumns")
+	}
+
+	var r0 []types.TaggedColumn
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]types.TaggedColumn, error)); ok {
+		return rf(ctx, catalog, schema)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []types.TaggedColumn); ok {
+		r0 = rf(ctx, catalog, schema)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.TaggedColumn)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, catalog, schema)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWarehouseRepository_GetTaggedColumns_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTaggedColumns'
+type MockWarehouseRepository_GetTaggedColumns_Call struct {
+	*mock.Call
+}
+
+// GetTaggedColumns is a helper method to define mock.On call
+//   - ctx context.Context
+//   - catalog string
+//   - schema string
+func (_e *MockWarehouseRepository_Expecter) GetTaggedColumns(ctx interface{}, catalog interface{}, schema interface{}) *MockWarehouseRepository_GetTaggedColumns_Call {
+	return &MockWarehouseRepository_GetTaggedColumns_Call{Call: _e.mock.On("GetTaggedColumns", ctx, catalog, schema)}
+}
+
+func (_c *MockWarehouseRepository_GetTaggedColumns_Call) Run(run func(ctx context.Context, catalog string, schema string)) *MockWarehouseRepository_GetTaggedColumns_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockWarehouseRepository_GetTaggedColumns_Call) Return(_a0 []types.TaggedColumn, _a1 error) *MockWarehouseRepository_GetTaggedColumns_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWarehouseRepository_GetTaggedColumns_Call) RunAndReturn(run func(context.Context, string, string) ([]types.TaggedColumn, error)) *MockWarehouseRepository_GetTaggedColumns_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTags provides a mock function with given fields: ctx, catalog, fn
 func (_m *MockWarehouseRepository) GetTags(ctx context.Context, catalog string, fn func(context.Context, string, string, string) error) error {
 	ret := _m.Called(ctx, catalog, fn)
@@ -459,6 +776,102 @@ func (_c *MockWarehouseRepository_SetRowFilter_Call) RunAndReturn(run func(conte
 	return _c
 }
 
+// SetTags provides a mock function with given fields: ctx, fullName, tags
+func (_m *MockWarehouseRepository) SetTags(ctx context.Context, fullName string, tags map[string]string) error {
+	ret := _m.Called(ctx, fullName, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string) error); ok {
+		r0 = rf(ctx, fullName, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWarehouseRepository_SetTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTags'
+type MockWarehouseRepository_SetTags_Call struct {
+	*mock.Call
+}
+
+// SetTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fullName string
+//   - tags map[string]string
+func (_e *MockWarehouseRepository_Expecter) SetTags(ctx interface{}, fullName interface{}, tags interface{}) *MockWarehouseRepository_SetTags_Call {
+	return &MockWarehouseRepository_SetTags_Call{Call: _e.mock.On("SetTags", ctx, fullName, tags)}
+}
+
+func (_c *MockWarehouseRepository_SetTags_Call) Run(run func(ctx context.Context, fullName string, tags map[string]string)) *MockWarehouseRepository_SetTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(map[string]string))
+	})
+	return _c
+}
+
+func (_c *MockWarehouseRepository_SetTags_Call) Return(_a0 error) *MockWarehouseRepository_SetTags_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWarehouseRepository_SetTags_Call) RunAndReturn(run func(context.Context, string, map[string]string) error) *MockWarehouseRepository_SetTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnsetTags provides a mock function with given fields: ctx, fullName, keys
+func (_m *MockWarehouseRepository) UnsetTags(ctx context.Context, fullName string, keys []string) error {
+	ret := _m.Called(ctx, fullName, keys)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnsetTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, fullName, keys)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWarehouseRepository_UnsetTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnsetTags'
+type MockWarehouseRepository_UnsetTags_Call struct {
+	*mock.Call
+}
+
+// UnsetTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fullName string
+//   - keys []string
+func (_e *MockWarehouseRepository_Expecter) UnsetTags(ctx interface{}, fullName interface{}, keys interface{}) *MockWarehouseRepository_UnsetTags_Call {
+	return &MockWarehouseRepository_UnsetTags_Call{Call: _e.mock.On("UnsetTags", ctx, fullName, keys)}
+}
+
+func (_c *MockWarehouseRepository_UnsetTags_Call) Run(run func(ctx context.Context, fullName string, keys []string)) *MockWarehouseRepository_UnsetTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockWarehouseRepository_UnsetTags_Call) Return(_a0 error) *MockWarehouseRepository_UnsetTags_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWarehouseRepository_UnsetTags_Call) RunAndReturn(run func(context.Context, string, []string) error) *MockWarehouseRepository_UnsetTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockWarehouseRepository creates a new instance of MockWarehouseRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockWarehouseRepository(t interface {
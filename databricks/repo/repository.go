@@ -3,9 +3,12 @@ package repo
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/databricks/databricks-sdk-go"
+	"github.com/databricks/databricks-sdk-go/client"
 	"github.com/databricks/databricks-sdk-go/listing"
 	"github.com/databricks/databricks-sdk-go/service/catalog"
 	"github.com/databricks/databricks-sdk-go/service/iam"
@@ -28,7 +31,7 @@ type AccountRepository struct {
 }
 
 func NewAccountRepository(pltfrm platform.DatabricksPlatform, credentials *types.RepositoryCredentials, accountId string) (*AccountRepository, error) {
-	accountHost, err := pltfrm.Host()
+	accountHost, err := credentials.Endpoints.ResolveAccountHost(pltfrm)
 	if err != nil {
 		return nil, fmt.Errorf("get host for platform %s: %w", pltfrm, err)
 	}
@@ -36,6 +39,7 @@ func NewAccountRepository(pltfrm platform.DatabricksPlatform, credentials *types
 	config := credentials.DatabricksConfig()
 	config.Host = accountHost
 	config.AccountID = accountId
+	applyThrottling(config, credentials)
 
 	dbClient, err := databricks.NewAccountClient(config)
 	if err != nil {
@@ -126,7 +130,10 @@ func (r *AccountRepository) ListUsers(ctx context.Context, optFn ...func(options
 			filter = fmt.Sprintf("userName eq %s", *options.Username)
 		}
 
-		return r.dbClient.Users.List(ctx, iam.ListAccountUsersRequest{Filter: filter})
+		return r.dbClient.Users.List(ctx, iam.ListAccountUsersRequest{
+			Filter:     filter,
+			Attributes: idsOnlyAttributes(options.IdsOnly),
+		})
 	})
 }
 
@@ -141,10 +148,13 @@ func (r *AccountRepository) ListServicePrincipals(ctx context.Context, optFn ...
 
 		if options.ServicePrincipalName != nil {
 			filter = fmt.Sprintf("displayName eq %s", *options.ServicePrincipalName)
+		} else if options.ApplicationId != nil {
+			filter = fmt.Sprintf("applicationId eq %s", *options.ApplicationId)
 		}
 
 		return r.dbClient.ServicePrincipals.List(ctx, iam.ListAccountServicePrincipalsRequest{
-			Filter: filter,
+			Filter:     filter,
+			Attributes: idsOnlyAttributes(options.IdsOnly),
 		})
 	})
 }
@@ -162,12 +172,55 @@ func (r *AccountRepository) ListGroups(ctx context.Context, optFn ...func(option
 			filter = fmt.Sprintf("displayName eq %s", *options.Groupname)
 		}
 
+		if options.ExternalId != nil {
+			filter = scimFilter(filter, fmt.Sprintf("externalId eq \"%s\"", *options.ExternalId))
+		}
+
 		return r.dbClient.Groups.List(ctx, iam.ListAccountGroupsRequest{
-			Filter: filter,
+			Filter:     filter,
+			Attributes: idsOnlyAttributes(options.IdsOnly),
 		})
 	})
 }
 
+func (r *AccountRepository) CreateGroup(ctx context.Context, displayName string) (*iam.Group, error) {
+	return r.dbClient.Groups.Create(ctx, iam.Group{
+		DisplayName: displayName,
+		Schemas:     []iam.GroupSchema{iam.GroupSchemaUrnIetfParamsScimSchemasCore20Group},
+	})
+}
+
+func (r *AccountRepository) UpdateGroupExternalId(ctx context.Context, groupId string, externalId string) error {
+	return r.dbClient.Groups.Patch(ctx, iam.PartialUpdate{
+		Id: groupId,
+		Operations: []iam.Patch{
+			{
+				Op:    iam.PatchOpReplace,
+				Path:  "externalId",
+				Value: externalId,
+			},
+		},
+		Schemas: []iam.PatchSchema{iam.PatchSchemaUrnIetfParamsScimApiMessages20PatchOp},
+	})
+}
+
+func (r *AccountRepository) DeleteGroup(ctx context.Context, groupId string) error {
+	return r.dbClient.Groups.DeleteById(ctx, groupId)
+}
+
+func (r *AccountRepository) UpdateGroupMembers(ctx context.Context, groupId string, add []string, remove []string) error {
+	operations := complexValuePatchOperations("members", add, remove)
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return r.dbClient.Groups.Patch(ctx, iam.PartialUpdate{
+		Id:         groupId,
+		Operations: operations,
+		Schemas:    []iam.PatchSchema{iam.PatchSchemaUrnIetfParamsScimApiMessages20PatchOp},
+	})
+}
+
 func (r *AccountRepository) ListWorkspaceAssignments(ctx context.Context, workspaceId int64) ([]iam.PermissionAssignment, error) {
 	return r.dbClient.WorkspaceAssignment.ListAll(ctx, iam.ListWorkspaceAssignmentRequest{WorkspaceId: workspaceId})
 }
@@ -182,21 +235,105 @@ func (r *AccountRepository) UpdateWorkspaceAssignment(ctx context.Context, works
 	return err
 }
 
+func (r *AccountRepository) UpdateUserRoles(ctx context.Context, userId string, add []string, remove []string) error {
+	operations := complexValuePatchOperations("roles", add, remove)
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return r.dbClient.Users.Patch(ctx, iam.PartialUpdate{
+		Id:         userId,
+		Operations: operations,
+		Schemas:    []iam.PatchSchema{iam.PatchSchemaUrnIetfParamsScimApiMessages20PatchOp},
+	})
+}
+
+func (r *AccountRepository) UpdateServicePrincipalRoles(ctx context.Context, servicePrincipalId string, add []string, remove []string) error {
+	operations := complexValuePatchOperations("roles", add, remove)
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return r.dbClient.ServicePrincipals.Patch(ctx, iam.PartialUpdate{
+		Id:         servicePrincipalId,
+		Operations: operations,
+		Schemas:    []iam.PatchSchema{iam.PatchSchemaUrnIetfParamsScimApiMessages20PatchOp},
+	})
+}
+
+func (r *AccountRepository) UpdateGroupRoles(ctx context.Context, groupId string, add []string, remove []string) error {
+	operations := complexValuePatchOperations("roles", add, remove)
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return r.dbClient.Groups.Patch(ctx, iam.PartialUpdate{
+		Id:         groupId,
+		Operations: operations,
+		Schemas:    []iam.PatchSchema{iam.PatchSchemaUrnIetfParamsScimApiMessages20PatchOp},
+	})
+}
+
+// GetAccountRuleSet returns the account-level access control rule set, containing the account roles like the marketplace admin
+func (r *AccountRepository) GetAccountRuleSet(ctx context.Context) (*iam.RuleSetResponse, error) {
+	return r.dbClient.AccessControl.GetRuleSet(ctx, iam.GetRuleSetRequest{
+		Name: r.accountRuleSetName(),
+	})
+}
+
+// UpdateAccountRuleSet replaces the grant rules of the account-level rule set. The etag must be the etag of the rule set the grant rules are based on.
+func (r *AccountRepository) UpdateAccountRuleSet(ctx context.Context, etag string, grantRules []iam.GrantRule) error {
+	_, err := r.dbClient.AccessControl.UpdateRuleSet(ctx, iam.UpdateRuleSetRequest{
+		Name: r.accountRuleSetName(),
+		RuleSet: iam.RuleSetUpdateRequest{
+			Etag:       etag,
+			GrantRules: grantRules,
+			Name:       r.accountRuleSetName(),
+		},
+	})
+
+	return err
+}
+
+func (r *AccountRepository) accountRuleSetName() string {
+	return fmt.Sprintf("accounts/%s/ruleSets/default", r.accountId)
+}
+
+func (r *AccountRepository) UpdateMetastoreOwner(ctx context.Context, metastoreId string, owner string) error {
+	_, err := r.dbClient.Metastores.Update(ctx, catalog.AccountsUpdateMetastore{
+		MetastoreId: metastoreId,
+		MetastoreInfo: &catalog.UpdateMetastore{
+			Id:    metastoreId,
+			Owner: owner,
+		},
+	})
+
+	return err
+}
+
 type WorkspaceRepository struct {
 	client      *databricks.WorkspaceClient
+	apiClient   *client.DatabricksClient // Used for APIs that are not yet supported by the SDK
 	workspaceId int64
 }
 
 func NewWorkspaceRepository(credentials *types.RepositoryCredentials, workspaceId int64) (*WorkspaceRepository, error) {
 	config := credentials.DatabricksConfig()
+	applyThrottling(config, credentials)
+
+	workspaceClient, err := databricks.NewWorkspaceClient(config)
+	if err != nil {
+		return nil, err
+	}
 
-	client, err := databricks.NewWorkspaceClient(config)
+	apiClient, err := client.New(workspaceClient.Config)
 	if err != nil {
 		return nil, err
 	}
 
 	return &WorkspaceRepository{
-		client:      client,
+		client:      workspaceClient,
+		apiClient:   apiClient,
 		workspaceId: workspaceId,
 	}, nil
 }
@@ -236,6 +373,18 @@ func (r *WorkspaceRepository) ListAllTables(ctx context.Context, catalogName str
 	})
 }
 
+// ListTableSummaries lists the tables of the schema without their columns, properties and owner
+func (r *WorkspaceRepository) ListTableSummaries(ctx context.Context, catalogName string, schemaName string) ([]catalog.TableInfo, error) {
+	return r.client.Tables.ListAll(ctx, catalog.ListTablesRequest{
+		CatalogName:    catalogName,
+		SchemaName:     schemaName,
+		IncludeBrowse:  true,
+		OmitColumns:    true,
+		OmitProperties: true,
+		OmitUsername:   true,
+	})
+}
+
 func (r *WorkspaceRepository) GetTable(ctx context.Context, catalogName string, schemaName string, tableName string) (*catalog.TableInfo, error) {
 	response, err := r.client.Tables.Get(ctx, catalog.GetTableRequest{
 		FullName: fmt.Sprintf("%s.%s.%s", catalogName, schemaName, tableName),
@@ -294,6 +443,41 @@ func (r *WorkspaceRepository) GetPermissionsOnResource(ctx context.Context, secu
 	return response, nil
 }
 
+func (r *WorkspaceRepository) GetEffectivePermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string) (*catalog.EffectivePermissionsList, error) {
+	return r.client.Grants.GetEffective(ctx, catalog.GetEffectiveRequest{
+		SecurableType: securableType,
+		FullName:      fullName,
+	})
+}
+
+// ListEntityTagAssignments returns the tags assigned to the entity (catalogs, schemas, tables or columns) with the given full name.
+// The entity tag assignments API requires no SQL warehouse and no privileges on the information_schema.
+func (r *WorkspaceRepository) ListEntityTagAssignments(ctx context.Context, entityType string, entityName string) ([]types.EntityTagAssignment, error) {
+	var result []types.EntityTagAssignment
+
+	query := map[string]any{}
+
+	for {
+		var response struct {
+			TagAssignments []types.EntityTagAssignment `json:"tag_assignments"`
+			NextPageToken  string                      `json:"next_page_token"`
+		}
+
+		err := r.apiClient.Do(ctx, http.MethodGet, fmt.Sprintf("/api/2.1/unity-catalog/entity-tag-assignments/%s/%s/tags", entityType, url.PathEscape(entityName)), nil, query, nil, &response)
+		if err != nil {
+			return nil, fmt.Errorf("list tag assignments of %s %q: %w", entityType, entityName, err)
+		}
+
+		result = append(result, response.TagAssignments...)
+
+		if response.NextPageToken == "" {
+			return result, nil
+		}
+
+		query["page_token"] = response.NextPageToken
+	}
+}
+
 func (r *WorkspaceRepository) SetPermissionsOnResource(ctx context.Context, securableType catalog.SecurableType, fullName string, changes ...catalog.PermissionsChange) error {
 	_, err := r.client.Grants.Update(ctx, catalog.UpdatePermissions{
 		SecurableType: securableType,
@@ -393,10 +577,106 @@ func (r *WorkspaceRepository) QueryHistory(ctx context.Context, startTime *time.
 	return nil
 }
 
+func (r *WorkspaceRepository) ListUsers(ctx context.Context, optFn ...func(options *types.DatabricksUsersFilter)) <-chan ChannelItem[iam.User] {
+	options := types.DatabricksUsersFilter{}
+	for _, fn := range optFn {
+		fn(&options)
+	}
+
+	return iteratorToChannel(ctx, func() listing.Iterator[iam.User] {
+		var filter string
+
+		if options.Username != nil {
+			filter = fmt.Sprintf("userName eq %s", *options.Username)
+		}
+
+		return r.client.Users.List(ctx, iam.ListUsersRequest{Filter: filter})
+	})
+}
+
+func (r *WorkspaceRepository) ListServicePrincipals(ctx context.Context, optFn ...func(options *types.DatabricksServicePrincipalFilter)) <-chan ChannelItem[iam.ServicePrincipal] {
+	options := types.DatabricksServicePrincipalFilter{}
+	for _, fn := range optFn {
+		fn(&options)
+	}
+
+	return iteratorToChannel(ctx, func() listing.Iterator[iam.ServicePrincipal] {
+		var filter string
+
+		if options.ServicePrincipalName != nil {
+			filter = fmt.Sprintf("displayName eq %s", *options.ServicePrincipalName)
+		}
+
+		return r.client.ServicePrincipals.List(ctx, iam.ListServicePrincipalsRequest{Filter: filter})
+	})
+}
+
+func (r *WorkspaceRepository) ListGroups(ctx context.Context, optFn ...func(options *types.DatabricksGroupsFilter)) <-chan ChannelItem[iam.Group] {
+	options := types.DatabricksGroupsFilter{}
+	for _, fn := range optFn {
+		fn(&options)
+	}
+
+	return iteratorToChannel(ctx, func() listing.Iterator[iam.Group] {
+		var filter string
+
+		if options.Groupname != nil {
+			filter = fmt.Sprintf("displayName eq %s", *options.Groupname)
+		}
+
+		return r.client.Groups.List(ctx, iam.ListGroupsRequest{Filter: filter})
+	})
+}
+
+func (r *WorkspaceRepository) UpdateUserEntitlements(ctx context.Context, userId string, add []string, remove []string) error {
+	operations := complexValuePatchOperations("entitlements", add, remove)
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return r.client.Users.Patch(ctx, iam.PartialUpdate{
+		Id:         userId,
+		Operations: operations,
+		Schemas:    []iam.PatchSchema{iam.PatchSchemaUrnIetfParamsScimApiMessages20PatchOp},
+	})
+}
+
+func (r *WorkspaceRepository) UpdateServicePrincipalEntitlements(ctx context.Context, servicePrincipalId string, add []string, remove []string) error {
+	operations := complexValuePatchOperations("entitlements", add, remove)
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return r.client.ServicePrincipals.Patch(ctx, iam.PartialUpdate{
+		Id:         servicePrincipalId,
+		Operations: operations,
+		Schemas:    []iam.PatchSchema{iam.PatchSchemaUrnIetfParamsScimApiMessages20PatchOp},
+	})
+}
+
+func (r *WorkspaceRepository) UpdateGroupEntitlements(ctx context.Context, groupId string, add []string, remove []string) error {
+	operations := complexValuePatchOperations("entitlements", add, remove)
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return r.client.Groups.Patch(ctx, iam.PartialUpdate{
+		Id:         groupId,
+		Operations: operations,
+		Schemas:    []iam.PatchSchema{iam.PatchSchemaUrnIetfParamsScimApiMessages20PatchOp},
+	})
+}
+
 func (r *WorkspaceRepository) SqlWarehouseRepository(warehouseId string) WarehouseRepository {
 	return NewSqlWarehouseRepository(r.client, warehouseId)
 }
 
+func (r *WorkspaceRepository) GetWarehousePermissions(ctx context.Context, warehouseId string) (*sql.WarehousePermissions, error) {
+	return r.client.Warehouses.GetPermissions(ctx, sql.GetWarehousePermissionsRequest{
+		WarehouseId: warehouseId,
+	})
+}
+
 func (r *WorkspaceRepository) Ping(ctx context.Context) error {
 	_, err := r.Me(ctx)
 	if err != nil {
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/stretchr/testify/assert"
+
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+func Test_policyStatement(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy types.AbacPolicy
+		want   string
+	}{
+		{
+			name: "column mask",
+			policy: types.AbacPolicy{
+				Name:          "raito_mask",
+				Kind:          types.AbacColumnMask,
+				SecurableType: "SCHEMA",
+				SecurableName: "catalog.schema",
+				Comment:       "Raito's mask",
+				Function:      "catalog.schema.raito_mask_string",
+				To:            []string{"account users"},
+				Except:        []string{"user@raito.io", "group"},
+				MatchColumns:  []types.AbacMatchColumn{{Condition: types.AbacCondition{{{Key: "pii", Value: ptr.String("e'mail")}}}, Alias: "masked_column"}},
+			},
+			want: "CREATE OR REPLACE POLICY `raito_mask`\nON SCHEMA `catalog`.`schema`\nCOMMENT 'Raito\\'s mask'\nCOLUMN MASK `catalog`.`schema`.`raito_mask_string`\nTO `account users`\nEXCEPT `user@raito.io`, `group`\nFOR TABLES\nMATCH COLUMNS hasTagValue('pii', 'e\\'mail') AS masked_column\nON COLUMN masked_column",
+		},
+		{
+			name: "row filter",
+			policy: types.AbacPolicy{
+				Name:          "raito_filter",
+				Kind:          types.AbacRowFilter,
+				SecurableType: "CATALOG",
+				SecurableName: "catalog",
+				Function:      "catalog.default.raito_filter_filter",
+				To:            []string{"account users"},
+				MatchColumns: []types.AbacMatchColumn{
+					{Condition: types.AbacCondition{{{Key: "geo", Value: ptr.String("region")}}}, Alias: "region"},
+					{Condition: types.AbacCondition{{{Key: "geo"}, {Key: "country"}}, {{Key: "nation"}}}, Alias: "country"},
+				},
+			},
+			want: "CREATE OR REPLACE POLICY `raito_filter`\nON CATALOG `catalog`\nROW FILTER `catalog`.`default`.`raito_filter_filter`\nTO `account users`\nFOR TABLES\nMATCH COLUMNS hasTagValue('geo', 'region') AS region, hasTag('geo') AND hasTag('country') OR hasTag('nation') AS country\nUSING COLUMNS (region, country)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policyStatement(&tt.policy))
+		})
+	}
+}
+
+func Test_tagStatementTarget(t *testing.T) {
+	tests := []struct {
+		fullName    string
+		wantTarget  string
+		wantCatalog string
+		wantErr     bool
+	}{
+		{fullName: "catalog", wantTarget: "CATALOG `catalog`", wantCatalog: "catalog"},
+		{fullName: "catalog.schema", wantTarget: "SCHEMA `catalog`.`schema`", wantCatalog: "catalog"},
+		{fullName: "catalog.schema.table", wantTarget: "TABLE `catalog`.`schema`.`table`", wantCatalog: "catalog"},
+		{fullName: "catalog.schema.table.column", wantTarget: "TABLE `catalog`.`schema`.`table` ALTER COLUMN `column`", wantCatalog: "catalog"},
+		{fullName: "catalog.schema.table.column.field", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fullName, func(t *testing.T) {
+			target, catalog, err := tagStatementTarget(tt.fullName)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTarget, target)
+			assert.Equal(t, tt.wantCatalog, catalog)
+		})
+	}
+}
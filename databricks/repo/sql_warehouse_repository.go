@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,12 +27,20 @@ var _ WarehouseRepository = (*SqlWarehouseRepository)(nil)
 type WarehouseRepository interface {
 	ExecuteStatement(ctx context.Context, catalog, schema, statement string, parameters ...sql.StatementParameterListItem) (*sql.StatementResponse, error)
 	GetTableInformation(ctx context.Context, catalog, schema, tableName string) (map[string]*types.ColumnInformation, error)
+	GetTableDetail(ctx context.Context, catalog, schema, tableName string) (*types.TableDetail, error)
 	DropMask(ctx context.Context, catalog, schema, table, column string) error
 	DropRowFilter(ctx context.Context, catalog, schema, table string) error
 	DropFunction(ctx context.Context, catalog, schema, functionName string) error
 	SetMask(ctx context.Context, catalog, schema, table, column, function string) error
 	SetRowFilter(ctx context.Context, catalog, schema, table, functionName string, arguments []string) error
 	GetTags(ctx context.Context, catalog string, fn func(ctx context.Context, fullName string, key string, value string) error) error
+	SetTags(ctx context.Context, fullName string, tags map[string]string) error
+	UnsetTags(ctx context.Context, fullName string, keys []string) error
+	CreateOrReplacePolicy(ctx context.Context, catalog string, policy *types.AbacPolicy) error
+	DropPolicy(ctx context.Context, catalog string, name string, securableType string, securableName string) error
+	GetTaggedColumns(ctx context.Context, catalog string, schema string) ([]types.TaggedColumn, error)
+	GetTableLineage(ctx context.Context, since time.Time, fn func(ctx context.Context, sourceTable string, targetTable string) error) error
+	GetColumnLineage(ctx context.Context, since time.Time, fn func(ctx context.Context, sourceTable string, sourceColumn string, targetTable string, targetColumn string) error) error
 }
 
 type SqlWarehouseRepository struct {
@@ -114,6 +124,44 @@ func (r *SqlWarehouseRepository) GetTableInformation(ctx context.Context, catalo
 	return result, nil
 }
 
+func (r *SqlWarehouseRepository) GetTableDetail(ctx context.Context, catalog, schema, tableName string) (*types.TableDetail, error) {
+	response, err := r.ExecuteStatement(ctx, catalog, schema, fmt.Sprintf("DESCRIBE DETAIL %s", escapeName(tableName)))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Result == nil || len(response.Result.DataArray) == 0 || response.Manifest == nil || response.Manifest.Schema == nil {
+		return nil, fmt.Errorf("no result on describe detail %q", tableName)
+	}
+
+	row := response.Result.DataArray[0]
+	result := &types.TableDetail{}
+
+	for i, column := range response.Manifest.Schema.Columns {
+		if i >= len(row) || row[i] == "" {
+			continue
+		}
+
+		switch column.Name {
+		case "sizeInBytes":
+			result.SizeInBytes = parseOptionalInt(row[i])
+		case "numFiles":
+			result.NumFiles = parseOptionalInt(row[i])
+		}
+	}
+
+	return result, nil
+}
+
+func parseOptionalInt(value string) *int64 {
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	return &i
+}
+
 func (r *SqlWarehouseRepository) DropMask(ctx context.Context, catalog, schema, table, column string) error {
 	_, err := r.ExecuteStatement(ctx, catalog, schema, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP MASK", escapeName(table), escapeName(column)))
 
@@ -212,6 +260,181 @@ func (r *SqlWarehouseRepository) GetTags(ctx context.Context, catalog string, fn
 	return nil
 }
 
+// SetTags sets the tags on the catalog, schema, table or column with the given full name (catalog[.schema[.table[.column]]])
+func (r *SqlWarehouseRepository) SetTags(ctx context.Context, fullName string, tags map[string]string) error {
+	target, catalog, err := tagStatementTarget(fullName)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	assignments := make([]string, 0, len(keys))
+	for _, key := range keys {
+		assignments = append(assignments, fmt.Sprintf("%s = %s", quoteString(key), quoteString(tags[key])))
+	}
+
+	_, err = r.ExecuteStatement(ctx, catalog, "", fmt.Sprintf("ALTER %s SET TAGS (%s)", target, strings.Join(assignments, ", ")))
+
+	return err
+}
+
+// UnsetTags removes the tags with the given keys from the catalog, schema, table or column with the given full name
+func (r *SqlWarehouseRepository) UnsetTags(ctx context.Context, fullName string, keys []string) error {
+	target, catalog, err := tagStatementTarget(fullName)
+	if err != nil {
+		return err
+	}
+
+	quotedKeys := array.Map(keys, func(key *string) string {
+		return quoteString(*key)
+	})
+
+	_, err = r.ExecuteStatement(ctx, catalog, "", fmt.Sprintf("ALTER %s UNSET TAGS (%s)", target, strings.Join(quotedKeys, ", ")))
+
+	return err
+}
+
+func (r *SqlWarehouseRepository) CreateOrReplacePolicy(ctx context.Context, catalog string, policy *types.AbacPolicy) error {
+	_, err := r.ExecuteStatement(ctx, catalog, "", policyStatement(policy))
+
+	return err
+}
+
+func (r *SqlWarehouseRepository) DropPolicy(ctx context.Context, catalog string, name string, securableType string, securableName string) error {
+	_, err := r.ExecuteStatement(ctx, catalog, "", fmt.Sprintf("DROP POLICY IF EXISTS %s ON %s %s", escapeName(name), securableType, escapeFullName(securableName)))
+
+	return err
+}
+
+// GetTaggedColumns returns all tagged columns within the catalog, or within the schema if not empty
+func (r *SqlWarehouseRepository) GetTaggedColumns(ctx context.Context, catalog string, schema string) ([]types.TaggedColumn, error) {
+	statement := "SELECT t.schema_name, t.table_name, t.column_name, c.data_type, t.tag_name, t.tag_value FROM information_schema.column_tags t " +
+		"JOIN information_schema.columns c ON c.table_schema = t.schema_name AND c.table_name = t.table_name AND c.column_name = t.column_name"
+
+	var parameters []sql.StatementParameterListItem
+
+	if schema != "" {
+		statement += " WHERE t.schema_name = :schema"
+		parameters = append(parameters, sql.StatementParameterListItem{Name: "schema", Value: schema})
+	}
+
+	response, err := r.ExecuteStatement(ctx, catalog, "", statement, parameters...)
+	if err != nil {
+		return nil, fmt.Errorf("get tagged columns: %w", err)
+	}
+
+	var result []types.TaggedColumn
+
+	columnIdx := make(map[string]int)
+
+	for _, row := range response.Result.DataArray {
+		fullName := row[0] + "." + row[1] + "." + row[2]
+
+		idx, found := columnIdx[fullName]
+		if !found {
+			idx = len(result)
+			columnIdx[fullName] = idx
+
+			result = append(result, types.TaggedColumn{FullName: fullName, DataType: row[3], Tags: make(map[string]string)})
+		}
+
+		result[idx].Tags[row[4]] = row[5]
+	}
+
+	return result, nil
+}
+
+func policyStatement(policy *types.AbacPolicy) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("CREATE OR REPLACE POLICY %s\nON %s %s\n", escapeName(policy.Name), policy.SecurableType, escapeFullName(policy.SecurableName)))
+
+	if policy.Comment != "" {
+		builder.WriteString(fmt.Sprintf("COMMENT %s\n", quoteString(policy.Comment)))
+	}
+
+	builder.WriteString(fmt.Sprintf("%s %s\n", policy.Kind, escapeFullName(policy.Function)))
+	builder.WriteString(fmt.Sprintf("TO %s\n", strings.Join(escapeColumnNames(policy.To...), ", ")))
+
+	if len(policy.Except) > 0 {
+		builder.WriteString(fmt.Sprintf("EXCEPT %s\n", strings.Join(escapeColumnNames(policy.Except...), ", ")))
+	}
+
+	builder.WriteString("FOR TABLES\n")
+
+	matchColumns := make([]string, 0, len(policy.MatchColumns))
+	aliases := make([]string, 0, len(policy.MatchColumns))
+
+	for _, column := range policy.MatchColumns {
+		matchColumns = append(matchColumns, fmt.Sprintf("%s AS %s", abacConditionStatement(column.Condition), column.Alias))
+		aliases = append(aliases, column.Alias)
+	}
+
+	builder.WriteString(fmt.Sprintf("MATCH COLUMNS %s\n", strings.Join(matchColumns, ", ")))
+
+	if policy.Kind == types.AbacColumnMask {
+		builder.WriteString(fmt.Sprintf("ON COLUMN %s", aliases[0]))
+	} else {
+		builder.WriteString(fmt.Sprintf("USING COLUMNS (%s)", strings.Join(aliases, ", ")))
+	}
+
+	return builder.String()
+}
+
+// abacConditionStatement renders the tag condition. The tag keys and values are quoted, so the condition can not contain anything else.
+func abacConditionStatement(condition types.AbacCondition) string {
+	conjunctions := make([]string, 0, len(condition))
+
+	for _, conjunction := range condition {
+		terms := make([]string, 0, len(conjunction))
+
+		for _, tagCondition := range conjunction {
+			if tagCondition.Value == nil {
+				terms = append(terms, fmt.Sprintf("hasTag(%s)", quoteString(tagCondition.Key)))
+			} else {
+				terms = append(terms, fmt.Sprintf("hasTagValue(%s, %s)", quoteString(tagCondition.Key), quoteString(*tagCondition.Value)))
+			}
+		}
+
+		conjunctions = append(conjunctions, strings.Join(terms, " AND "))
+	}
+
+	return strings.Join(conjunctions, " OR ")
+}
+
+func escapeFullName(fullName string) string {
+	return strings.Join(escapeColumnNames(strings.Split(fullName, ".")...), ".")
+}
+
+// tagStatementTarget returns the securable part of an ALTER ... SET/UNSET TAGS statement and the catalog of the securable
+func tagStatementTarget(fullName string) (string, string, error) {
+	parts := strings.Split(fullName, ".")
+	escapedParts := escapeColumnNames(parts...)
+
+	switch len(parts) {
+	case 1:
+		return "CATALOG " + escapedParts[0], parts[0], nil
+	case 2:
+		return "SCHEMA " + strings.Join(escapedParts, "."), parts[0], nil
+	case 3:
+		return "TABLE " + strings.Join(escapedParts, "."), parts[0], nil
+	case 4:
+		return fmt.Sprintf("TABLE %s ALTER COLUMN %s", strings.Join(escapedParts[:3], "."), escapedParts[3]), parts[0], nil
+	default:
+		return "", "", fmt.Errorf("unable to tag %q: expected a catalog, schema, table or column", fullName)
+	}
+}
+
+func quoteString(s string) string {
+	return fmt.Sprintf("'%s'", strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s))
+}
+
 func (r *SqlWarehouseRepository) waitForWarehouse(ctx context.Context) error {
 	requestToStart := false
 
@@ -241,3 +464,69 @@ func (r *SqlWarehouseRepository) waitForWarehouse(ctx context.Context) error {
 
 	return errors.New("warehouse start timeout")
 }
+
+// GetTableLineage returns the distinct table lineage recorded in the lineage system table of the metastore since the given time
+func (r *SqlWarehouseRepository) GetTableLineage(ctx context.Context, since time.Time, fn func(ctx context.Context, sourceTable string, targetTable string) error) error {
+	response, err := r.ExecuteStatement(ctx, "", "", "SELECT DISTINCT source_table_full_name, target_table_full_name FROM system.access.table_lineage WHERE source_table_full_name IS NOT NULL AND target_table_full_name IS NOT NULL AND event_time >= :since", lineageSinceParameter(since))
+	if err != nil {
+		return fmt.Errorf("get table lineage: %w", err)
+	}
+
+	return r.forEachRow(ctx, response, func(row []string) error {
+		return fn(ctx, row[0], row[1])
+	})
+}
+
+// GetColumnLineage returns the distinct column lineage recorded in the lineage system table of the metastore since the given time
+func (r *SqlWarehouseRepository) GetColumnLineage(ctx context.Context, since time.Time, fn func(ctx context.Context, sourceTable string, sourceColumn string, targetTable string, targetColumn string) error) error {
+	response, err := r.ExecuteStatement(ctx, "", "", "SELECT DISTINCT source_table_full_name, source_column_name, target_table_full_name, target_column_name FROM system.access.column_lineage WHERE source_table_full_name IS NOT NULL AND source_column_name IS NOT NULL AND target_table_full_name IS NOT NULL AND target_column_name IS NOT NULL AND event_time >= :since", lineageSinceParameter(since))
+	if err != nil {
+		return fmt.Errorf("get column lineage: %w", err)
+	}
+
+	return r.forEachRow(ctx, response, func(row []string) error {
+		return fn(ctx, row[0], row[1], row[2], row[3])
+	})
+}
+
+func lineageSinceParameter(since time.Time) sql.StatementParameterListItem {
+	return sql.StatementParameterListItem{
+		Name:  "since",
+		Type:  "TIMESTAMP",
+		Value: since.UTC().Format(time.RFC3339),
+	}
+}
+
+// forEachRow calls fn for each row of the statement result, fetching the remaining chunks if the result is split
+func (r *SqlWarehouseRepository) forEachRow(ctx context.Context, response *sql.StatementResponse, fn func(row []string) error) error {
+	if response.Result == nil {
+		return nil
+	}
+
+	result := response.Result
+
+	for {
+		for _, row := range result.DataArray {
+			err := fn(row)
+			if err != nil {
+				return err
+			}
+		}
+
+		if result.NextChunkInternalLink == "" {
+			return nil
+		}
+
+		chunkIndex := result.NextChunkIndex
+
+		var err error
+
+		result, err = r.executionClient.GetStatementResultChunkN(ctx, sql.GetStatementResultChunkNRequest{
+			StatementId: response.StatementId,
+			ChunkIndex:  chunkIndex,
+		})
+		if err != nil {
+			return fmt.Errorf("get result chunk %d of statement %s: %w", chunkIndex, response.StatementId, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go"
+	"golang.org/x/time/rate"
+
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+type throttleStats struct {
+	requests  int64
+	throttled int64         // 429 and 503 responses
+	waited    time.Duration // time spent waiting on the rate limiter
+}
+
+type hostThrottle struct {
+	limiter *rate.Limiter
+	stats   throttleStats
+}
+
+// throttleRegistry keeps a token bucket per host, so all clients of the same account or workspace share the same rate limit
+type throttleRegistry struct {
+	mutex sync.Mutex
+	hosts map[string]*hostThrottle
+}
+
+var throttles = &throttleRegistry{hosts: make(map[string]*hostThrottle)}
+
+func (r *throttleRegistry) get(host string, requestsPerSecond float64) *hostThrottle {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	throttle, found := r.hosts[host]
+	if !found {
+		limit := rate.Inf
+		if requestsPerSecond > 0 {
+			limit = rate.Limit(requestsPerSecond)
+		}
+
+		throttle = &hostThrottle{limiter: rate.NewLimiter(limit, 1)}
+		r.hosts[host] = throttle
+	}
+
+	return throttle
+}
+
+func (r *throttleRegistry) record(throttle *hostThrottle, fn func(stats *throttleStats)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	fn(&throttle.stats)
+}
+
+// LogThrottlingStats logs the number of requests and throttled responses per host since the previous call
+func LogThrottlingStats() {
+	throttles.mutex.Lock()
+	defer throttles.mutex.Unlock()
+
+	hosts := make([]string, 0, len(throttles.hosts))
+	for host := range throttles.hosts {
+		hosts = append(hosts, host)
+	}
+
+	slices.Sort(hosts)
+
+	for _, host := range hosts {
+		stats := &throttles.hosts[host].stats
+
+		if stats.requests == 0 {
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Requests to %s: %d requests, %d throttled responses, %s waited", host, stats.requests, stats.throttled, stats.waited.Round(time.Millisecond)))
+
+		*stats = throttleStats{}
+	}
+}
+
+// throttlingTransport limits the number of requests per second to each host and keeps track of the throttled responses (429 and 503).
+// Throttled requests are retried by the SDK, so the transport never retries requests itself.
+type throttlingTransport struct {
+	base              http.RoundTripper
+	requestsPerSecond float64
+}
+
+// newThrottlingTransport creates the transport for the credentials. A non-positive rate disables client-side rate limiting.
+func newThrottlingTransport(credentials *types.RepositoryCredentials) *throttlingTransport {
+	return &throttlingTransport{
+		base:              http.DefaultTransport,
+		requestsPerSecond: credentials.RequestsPerSecond,
+	}
+}
+
+// applyThrottling routes all requests of the client configuration through the shared throttling transport
+func applyThrottling(config *databricks.Config, credentials *types.RepositoryCredentials) {
+	config.HTTPTransport = newThrottlingTransport(credentials)
+
+	// The SDK rate limits each client separately, with a default of 15 requests per second.
+	// Align it with the shared limit, so it never throttles before the shared limit does, or disable it if the shared limit is disabled.
+	if credentials.RequestsPerSecond > 0 {
+		config.RateLimitPerSecond = int(math.Ceil(credentials.RequestsPerSecond))
+	} else {
+		config.RateLimitPerSecond = math.MaxInt32
+	}
+
+	if credentials.RetryTimeoutSeconds > 0 {
+		config.RetryTimeoutSeconds = credentials.RetryTimeoutSeconds
+	}
+}
+
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	throttle := throttles.get(req.URL.Host, t.requestsPerSecond)
+
+	start := time.Now()
+
+	err := throttle.limiter.Wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	waited := time.Since(start)
+
+	resp, err := t.base.RoundTrip(req)
+
+	throttles.record(throttle, func(stats *throttleStats) {
+		stats.requests++
+		stats.waited += waited
+
+		if err == nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+			stats.throttled++
+		}
+	})
+
+	return resp, err
+}
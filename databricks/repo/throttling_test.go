@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/databricks/databricks-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cli-plugin-databricks/databricks/repo/types"
+)
+
+func TestThrottlingTransport_DoesNotRetryThrottledRequests(t *testing.T) {
+	// Given
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newThrottlingTransport(&types.RepositoryCredentials{RequestsPerSecond: 100})}
+
+	// When
+	resp, err := client.Get(server.URL)
+
+	// Then
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 1, requests)
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	stats := throttles.get(serverUrl.Host, 100).stats
+	assert.Equal(t, int64(1), stats.requests)
+	assert.Equal(t, int64(1), stats.throttled)
+}
+
+func Test_applyThrottling(t *testing.T) {
+	tests := []struct {
+		name                string
+		credentials         types.RepositoryCredentials
+		rateLimitPerSecond  int
+		retryTimeoutSeconds int
+	}{
+		{
+			name:                "rate limit and retry timeout",
+			credentials:         types.RepositoryCredentials{RequestsPerSecond: 2.5, RetryTimeoutSeconds: 60},
+			rateLimitPerSecond:  3,
+			retryTimeoutSeconds: 60,
+		},
+		{
+			name:               "rate limit disabled",
+			credentials:        types.RepositoryCredentials{},
+			rateLimitPerSecond: math.MaxInt32,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &databricks.Config{}
+
+			applyThrottling(config, &tt.credentials)
+
+			assert.IsType(t, &throttlingTransport{}, config.HTTPTransport)
+			assert.Equal(t, tt.rateLimitPerSecond, config.RateLimitPerSecond)
+			assert.Equal(t, tt.retryTimeoutSeconds, config.RetryTimeoutSeconds)
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package types
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+
 	"github.com/databricks/databricks-sdk-go"
 	"github.com/databricks/databricks-sdk-go/config"
 	config2 "github.com/raito-io/cli/base/util/config"
 
 	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/platform"
 )
 
 type RepositoryCredentials struct {
@@ -25,7 +30,19 @@ type RepositoryCredentials struct {
 	GoogleCredentials    string
 	GoogleServiceAccount string
 
-	Host string
+	Profile           string // Profile of the Databricks config file
+	ConfigFile        string // Path of the Databricks config file, ~/.databrickscfg if empty
+	AuthType          string // Unified authentication type, all types are tried in order if empty
+	OIDCTokenEnv      string
+	OIDCTokenFilepath string
+	TokenAudience     string
+
+	Host                 string
+	Endpoints            platform.Endpoints     // Overrides of the account and workspace hosts of the platform
+	WorkspaceCredentials []WorkspaceCredentials // Overrides of the credentials for specific workspaces
+
+	RequestsPerSecond   float64 // Shared rate limit per account or workspace host, no client-side rate limit if not positive
+	RetryTimeoutSeconds int     // Maximum time the SDK retries throttled and failed requests
 }
 
 func (r *RepositoryCredentials) DatabricksConfig() *databricks.Config {
@@ -44,8 +61,108 @@ func (r *RepositoryCredentials) DatabricksConfig() *databricks.Config {
 		ClientSecret:         r.ClientSecret,
 		GoogleCredentials:    r.GoogleCredentials,
 		GoogleServiceAccount: r.GoogleServiceAccount,
+		Profile:              r.Profile,
+		ConfigFile:           r.ConfigFile,
+		AuthType:             r.AuthType,
+		OIDCTokenEnv:         r.OIDCTokenEnv,
+		OIDCTokenFilepath:    r.OIDCTokenFilepath,
+		TokenAudience:        r.TokenAudience,
 		Host:                 r.Host,
+		Loaders:              []config.Loader{pluginHostLoader{}},
+	}
+}
+
+// ForWorkspace returns the credentials to authenticate against the given workspace.
+// If credentials are configured for the workspace, these replace the account credentials.
+func (r RepositoryCredentials) ForWorkspace(workspaceId int64, deploymentName string) RepositoryCredentials {
+	workspaceCredentials := r.workspaceCredentials(workspaceId, deploymentName)
+	if workspaceCredentials == nil {
+		return r
+	}
+
+	// Only the connection settings are kept, so no account credential is combined with the workspace credentials
+	return RepositoryCredentials{
+		Username:     workspaceCredentials.Username,
+		Password:     workspaceCredentials.Password,
+		ClientId:     workspaceCredentials.ClientId,
+		ClientSecret: workspaceCredentials.ClientSecret,
+		Token:        workspaceCredentials.Token,
+
+		AzureUseMSI:       workspaceCredentials.AzureUseMSI,
+		AzureClientId:     workspaceCredentials.AzureClientId,
+		AzureClientSecret: workspaceCredentials.AzureClientSecret,
+		AzureTenantId:     workspaceCredentials.AzureTenantId,
+		AzureEnvironment:  workspaceCredentials.AzureEnvironment,
+
+		GoogleCredentials:    workspaceCredentials.GoogleCredentials,
+		GoogleServiceAccount: workspaceCredentials.GoogleServiceAccount,
+
+		Profile:           workspaceCredentials.Profile,
+		ConfigFile:        workspaceCredentials.ConfigFile,
+		AuthType:          workspaceCredentials.AuthType,
+		OIDCTokenEnv:      workspaceCredentials.OIDCTokenEnv,
+		OIDCTokenFilepath: workspaceCredentials.OIDCTokenFilepath,
+		TokenAudience:     workspaceCredentials.TokenAudience,
+
+		Host:                 r.Host,
+		Endpoints:            r.Endpoints,
+		WorkspaceCredentials: r.WorkspaceCredentials,
+
+		RequestsPerSecond:   r.RequestsPerSecond,
+		RetryTimeoutSeconds: r.RetryTimeoutSeconds,
+	}
+}
+
+// HasWorkspaceCredentials returns true if credentials are configured for the given workspace, so it is not accessed with the account credentials
+func (r RepositoryCredentials) HasWorkspaceCredentials(workspaceId int64, deploymentName string) bool {
+	return r.workspaceCredentials(workspaceId, deploymentName) != nil
+}
+
+func (r RepositoryCredentials) workspaceCredentials(workspaceId int64, deploymentName string) *WorkspaceCredentials {
+	workspaceIdStr := strconv.FormatInt(workspaceId, 10)
+
+	for i := range r.WorkspaceCredentials {
+		if r.WorkspaceCredentials[i].Workspace == deploymentName || r.WorkspaceCredentials[i].Workspace == workspaceIdStr {
+			return &r.WorkspaceCredentials[i]
+		}
+	}
+
+	return nil
+}
+
+// ResolveAccountId returns the account id of the DATABRICKS_ACCOUNT_ID environment variable or the config profile
+func (r *RepositoryCredentials) ResolveAccountId() (string, error) {
+	databricksConfig := (*config.Config)(r.DatabricksConfig())
+
+	err := databricksConfig.EnsureResolved()
+	if err != nil {
+		return "", fmt.Errorf("resolve databricks config: %w", err)
+	}
+
+	return databricksConfig.AccountID, nil
+}
+
+// pluginHostLoader loads the DATABRICKS_* environment variables and the config profile, like the default loaders of the SDK.
+// The host of the account or workspace is always determined by the plugin, so a host in the environment or in the profile is ignored.
+type pluginHostLoader struct{}
+
+func (l pluginHostLoader) Name() string {
+	return "raito-plugin"
+}
+
+func (l pluginHostLoader) Configure(databricksConfig *config.Config) error {
+	host := databricksConfig.Host
+
+	for _, loader := range []config.Loader{config.ConfigAttributes, config.ConfigFile} {
+		err := loader.Configure(databricksConfig)
+		if err != nil {
+			return err
+		}
 	}
+
+	databricksConfig.Host = host
+
+	return nil
 }
 
 func GenerateConfig(configParams *config2.ConfigMap) RepositoryCredentials {
@@ -64,6 +181,19 @@ func GenerateConfig(configParams *config2.ConfigMap) RepositoryCredentials {
 	googleCredentials := configParams.GetString(constants.DatabricksGoogleCredentials)
 	googleServiceAccount := configParams.GetString(constants.DatabricksGoogleServiceAccount)
 
+	profile := configParams.GetString(constants.DatabricksConfigProfile)
+	configFile := configParams.GetString(constants.DatabricksConfigFile)
+	authType := configParams.GetString(constants.DatabricksAuthType)
+	oidcTokenEnv := configParams.GetString(constants.DatabricksOidcTokenEnv)
+	oidcTokenFile := configParams.GetString(constants.DatabricksOidcTokenFile)
+	tokenAudience := configParams.GetString(constants.DatabricksTokenAudience)
+
+	accountHost := configParams.GetString(constants.DatabricksAccountHost)
+	workspaceHostTemplate := configParams.GetString(constants.DatabricksWorkspaceHostTemplate)
+
+	requestsPerSecond := configParams.GetIntWithDefault(constants.DatabricksRequestsPerSecond, constants.DefaultRequestsPerSecond)
+	retryTimeout := configParams.GetIntWithDefault(constants.DatabricksRetryTimeout, constants.DefaultRetryTimeout)
+
 	return RepositoryCredentials{
 		Username:             username,
 		Password:             password,
@@ -77,19 +207,63 @@ func GenerateConfig(configParams *config2.ConfigMap) RepositoryCredentials {
 		AzureEnvironment:     azureEnvironment,
 		GoogleCredentials:    googleCredentials,
 		GoogleServiceAccount: googleServiceAccount,
+		Profile:              profile,
+		ConfigFile:           configFile,
+		AuthType:             authType,
+		OIDCTokenEnv:         oidcTokenEnv,
+		OIDCTokenFilepath:    oidcTokenFile,
+		TokenAudience:        tokenAudience,
+		Endpoints: platform.Endpoints{
+			AccountHost:           accountHost,
+			WorkspaceHostTemplate: workspaceHostTemplate,
+		},
+		RequestsPerSecond:   float64(requestsPerSecond),
+		RetryTimeoutSeconds: retryTimeout,
 	}
 }
 
+// WorkspaceCredentials are the credentials to authenticate against a single workspace, instead of the account credentials
+type WorkspaceCredentials struct {
+	Workspace string `json:"workspace"` // Deployment name or ID of the workspace
+
+	Username     string `json:"user,omitempty"`
+	Password     string `json:"password,omitempty"`
+	ClientId     string `json:"client-id,omitempty"`
+	ClientSecret string `json:"client-secret,omitempty"`
+	Token        string `json:"token,omitempty"`
+
+	AzureUseMSI       bool   `json:"azure-use-msi,omitempty"`
+	AzureClientId     string `json:"azure-client-id,omitempty"`
+	AzureClientSecret string `json:"azure-client-secret,omitempty"`
+	AzureTenantId     string `json:"azure-tenant-id,omitempty"`
+	AzureEnvironment  string `json:"azure-environment,omitempty"`
+
+	GoogleCredentials    string `json:"google-credentials,omitempty"`
+	GoogleServiceAccount string `json:"google-service-account,omitempty"`
+
+	Profile           string `json:"config-profile,omitempty"`
+	ConfigFile        string `json:"config-file,omitempty"`
+	AuthType          string `json:"auth-type,omitempty"`
+	OIDCTokenEnv      string `json:"oidc-token-env,omitempty"`
+	OIDCTokenFilepath string `json:"oidc-token-file,omitempty"`
+	TokenAudience     string `json:"token-audience,omitempty"`
+}
+
 type DatabricksUsersFilter struct {
 	Username *string
+	IdsOnly  bool // Only return the ids of the users
 }
 
 type DatabricksServicePrincipalFilter struct {
 	ServicePrincipalName *string
+	ApplicationId        *string
+	IdsOnly              bool // Only return the ids of the service principals
 }
 
 type DatabricksGroupsFilter struct {
-	Groupname *string
+	Groupname  *string
+	ExternalId *string
+	IdsOnly    bool // Only return the ids of the groups
 }
 
 type ColumnInformation struct {
@@ -97,3 +271,72 @@ type ColumnInformation struct {
 	Type string
 	Mask *string
 }
+
+// TableDetail contains the storage details of a Delta table, as returned by DESCRIBE DETAIL
+type TableDetail struct {
+	SizeInBytes *int64
+	NumFiles    *int64
+}
+
+type EntityTagAssignment struct {
+	EntityType string `json:"entity_type"`
+	EntityName string `json:"entity_name"`
+	TagKey     string `json:"tag_key"`
+	TagValue   string `json:"tag_value,omitempty"`
+}
+
+type AbacPolicyKind string
+
+const (
+	AbacColumnMask AbacPolicyKind = "COLUMN MASK"
+	AbacRowFilter  AbacPolicyKind = "ROW FILTER"
+)
+
+// AbacTagCondition matches columns with the tag key, and with the tag value if set
+type AbacTagCondition struct {
+	Key   string
+	Value *string
+}
+
+// AbacCondition is a disjunction of conjunctions of tag conditions
+type AbacCondition [][]AbacTagCondition
+
+// Matches returns true if the tags of a column satisfy the condition
+func (c AbacCondition) Matches(tags map[string]string) bool {
+	for _, conjunction := range c {
+		if len(conjunction) > 0 && !slices.ContainsFunc(conjunction, func(condition AbacTagCondition) bool {
+			value, found := tags[condition.Key]
+
+			return !found || (condition.Value != nil && *condition.Value != value)
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type AbacMatchColumn struct {
+	Condition AbacCondition
+	Alias     string
+}
+
+// TaggedColumn is a column with at least one tag
+type TaggedColumn struct {
+	FullName string // schema.table.column
+	DataType string
+	Tags     map[string]string
+}
+
+// AbacPolicy is an attribute based access control policy, applying a column mask or row filter on all tables within a catalog or schema that match the tag conditions
+type AbacPolicy struct {
+	Name          string
+	Kind          AbacPolicyKind
+	SecurableType string // CATALOG or SCHEMA
+	SecurableName string
+	Comment       string
+	Function      string
+	To            []string
+	Except        []string
+	MatchColumns  []AbacMatchColumn
+}
@@ -2,8 +2,11 @@ package repo
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/databricks/databricks-sdk-go/listing"
+	"github.com/databricks/databricks-sdk-go/service/iam"
 	"github.com/hashicorp/go-hclog"
 	"github.com/raito-io/cli/base"
 )
@@ -14,6 +17,54 @@ func init() {
 	logger = base.Logger()
 }
 
+// complexValuePatchOperations converts changes of a multi-valued SCIM attribute (e.g. members, roles or entitlements) to SCIM patch operations
+func complexValuePatchOperations(path string, add []string, remove []string) []iam.Patch {
+	operations := make([]iam.Patch, 0, 1+len(remove))
+
+	if len(add) > 0 {
+		values := make([]iam.ComplexValue, 0, len(add))
+		for _, value := range add {
+			values = append(values, iam.ComplexValue{Value: value})
+		}
+
+		operations = append(operations, iam.Patch{
+			Op:    iam.PatchOpAdd,
+			Path:  path,
+			Value: values,
+		})
+	}
+
+	for _, value := range remove {
+		operations = append(operations, iam.Patch{
+			Op:   iam.PatchOpRemove,
+			Path: fmt.Sprintf("%s[value eq \"%s\"]", path, value),
+		})
+	}
+
+	return operations
+}
+
+// scimFilter combines the non-empty SCIM filter conditions
+func scimFilter(conditions ...string) string {
+	nonEmpty := make([]string, 0, len(conditions))
+
+	for _, condition := range conditions {
+		if condition != "" {
+			nonEmpty = append(nonEmpty, condition)
+		}
+	}
+
+	return strings.Join(nonEmpty, " and ")
+}
+
+func idsOnlyAttributes(idsOnly bool) string {
+	if idsOnly {
+		return "id"
+	}
+
+	return ""
+}
+
 func iteratorToChannel[T any](ctx context.Context, f func() listing.Iterator[T]) <-chan ChannelItem[T] {
 	outputChannel := make(chan ChannelItem[T])
 
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+type TagKey struct {
+	FullName string
+	Key      string
+}
+
+type ownedTagEntry struct {
+	FullName string `json:"fullName"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Released bool   `json:"released,omitempty"`
+}
+
+// OwnedTagState keeps track of the tags that are set by Raito, including the value that was set.
+// Only these tags are updated or removed by the tag export, tags set by other parties are never touched.
+// Tags that are changed or removed outside Raito are released, so they are not applied again as long as Raito exports the same value.
+type OwnedTagState struct {
+	path     string
+	tags     map[TagKey]string
+	released map[TagKey]string
+}
+
+func LoadOwnedTagState(path string) (*OwnedTagState, error) {
+	state := &OwnedTagState{
+		path:     path,
+		tags:     make(map[TagKey]string),
+		released: make(map[TagKey]string),
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read owned tag state %q: %w", path, err)
+	}
+
+	var entries []ownedTagEntry
+
+	err = json.Unmarshal(content, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("parse owned tag state %q: %w", path, err)
+	}
+
+	for _, entry := range entries {
+		if entry.Released {
+			state.released[TagKey{FullName: entry.FullName, Key: entry.Key}] = entry.Value
+		} else {
+			state.tags[TagKey{FullName: entry.FullName, Key: entry.Key}] = entry.Value
+		}
+	}
+
+	return state, nil
+}
+
+func (s *OwnedTagState) Save() error {
+	entries := make([]ownedTagEntry, 0, len(s.tags)+len(s.released))
+
+	for key, value := range s.tags {
+		entries = append(entries, ownedTagEntry{
+			FullName: key.FullName,
+			Key:      key.Key,
+			Value:    value,
+		})
+	}
+
+	for key, value := range s.released {
+		entries = append(entries, ownedTagEntry{
+			FullName: key.FullName,
+			Key:      key.Key,
+			Value:    value,
+			Released: true,
+		})
+	}
+
+	slices.SortFunc(entries, func(a, b ownedTagEntry) int {
+		return cmp.Or(cmp.Compare(a.FullName, b.FullName), cmp.Compare(a.Key, b.Key))
+	})
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal owned tag state: %w", err)
+	}
+
+	err = os.WriteFile(s.path, content, 0600)
+	if err != nil {
+		return fmt.Errorf("write owned tag state %q: %w", s.path, err)
+	}
+
+	return nil
+}
+
+// Get returns the value Raito set for the tag, if the tag is owned by Raito
+func (s *OwnedTagState) Get(key TagKey) (string, bool) {
+	value, found := s.tags[key]
+
+	return value, found
+}
+
+func (s *OwnedTagState) Set(key TagKey, value string) {
+	delete(s.released, key)
+	s.tags[key] = value
+}
+
+func (s *OwnedTagState) Delete(key TagKey) {
+	delete(s.tags, key)
+	delete(s.released, key)
+}
+
+// Release gives up the ownership of a tag that is changed or removed outside Raito.
+// The exported value is kept, so the tag is not applied again until Raito exports another value.
+func (s *OwnedTagState) Release(key TagKey, exportedValue string) {
+	delete(s.tags, key)
+	s.released[key] = exportedValue
+}
+
+// IsReleased returns true if the ownership of the tag was given up while Raito exported the given value
+func (s *OwnedTagState) IsReleased(key TagKey, exportedValue string) bool {
+	value, found := s.released[key]
+
+	return found && value == exportedValue
+}
+
+// Keys returns all owned tags of which the full name matches the filter
+func (s *OwnedTagState) Keys(filter func(fullName string) bool) []TagKey {
+	return filterTagKeys(s.tags, filter)
+}
+
+// ReleasedKeys returns all released tags of which the full name matches the filter
+func (s *OwnedTagState) ReleasedKeys(filter func(fullName string) bool) []TagKey {
+	return filterTagKeys(s.released, filter)
+}
+
+func filterTagKeys(tags map[TagKey]string, filter func(fullName string) bool) []TagKey {
+	var keys []TagKey
+
+	for key := range tags {
+		if filter(key.FullName) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b TagKey) int {
+		return cmp.Or(cmp.Compare(a.FullName, b.FullName), cmp.Compare(a.Key, b.Key))
+	})
+
+	return keys
+}
@@ -0,0 +1,205 @@
+package types
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/raito-io/golang-set/set"
+)
+
+type UsageGrantKey struct {
+	Item      SecurableItemKey
+	Principal string
+	Privilege string
+}
+
+type usageGrant struct {
+	Owned      bool            // Raito created the grant, so it can be revoked once no access provider references it
+	Pending    bool            // Ownership is not yet known as the grant was not stored before
+	References set.Set[string] // Access providers that require the grant
+}
+
+type usageGrantEntry struct {
+	Type       string   `json:"type"`
+	FullName   string   `json:"fullName"`
+	Principal  string   `json:"principal"`
+	Privilege  string   `json:"privilege"`
+	Owned      bool     `json:"owned"`
+	References []string `json:"references"`
+}
+
+// UsageGrantState keeps track of the access providers that require a usage grant (USE CATALOG, USE SCHEMA) on a parent data object.
+// The state is persisted between syncs, as only out of sync access providers may be exported.
+type UsageGrantState struct {
+	path       string
+	grants     map[UsageGrantKey]*usageGrant
+	references map[string]set.Set[UsageGrantKey] // Access provider id -> usage grants referenced by the access provider
+}
+
+func LoadUsageGrantState(path string) (*UsageGrantState, error) {
+	state := &UsageGrantState{
+		path:       path,
+		grants:     make(map[UsageGrantKey]*usageGrant),
+		references: make(map[string]set.Set[UsageGrantKey]),
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read usage grant state %q: %w", path, err)
+	}
+
+	var entries []usageGrantEntry
+
+	err = json.Unmarshal(content, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("parse usage grant state %q: %w", path, err)
+	}
+
+	for _, entry := range entries {
+		key := UsageGrantKey{Item: SecurableItemKey{Type: entry.Type, FullName: entry.FullName}, Principal: entry.Principal, Privilege: entry.Privilege}
+
+		state.grants[key] = &usageGrant{
+			Owned:      entry.Owned,
+			References: set.NewSet(entry.References...),
+		}
+
+		for _, apId := range entry.References {
+			state.addReference(apId, key)
+		}
+	}
+
+	return state, nil
+}
+
+// Save persists all grants of which the ownership is known
+func (s *UsageGrantState) Save() error {
+	entries := make([]usageGrantEntry, 0, len(s.grants))
+
+	for key, grant := range s.grants {
+		if grant.Pending {
+			continue
+		}
+
+		references := grant.References.Slice()
+		slices.Sort(references)
+
+		entries = append(entries, usageGrantEntry{
+			Type:       key.Item.Type,
+			FullName:   key.Item.FullName,
+			Principal:  key.Principal,
+			Privilege:  key.Privilege,
+			Owned:      grant.Owned,
+			References: references,
+		})
+	}
+
+	slices.SortFunc(entries, func(a, b usageGrantEntry) int {
+		return cmp.Or(cmp.Compare(a.FullName, b.FullName), cmp.Compare(a.Principal, b.Principal), cmp.Compare(a.Privilege, b.Privilege))
+	})
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal usage grant state: %w", err)
+	}
+
+	err = os.WriteFile(s.path, content, 0600)
+	if err != nil {
+		return fmt.Errorf("write usage grant state %q: %w", s.path, err)
+	}
+
+	return nil
+}
+
+// Release removes all references of the access provider
+func (s *UsageGrantState) Release(apId string) {
+	for key := range s.references[apId] {
+		if grant, found := s.grants[key]; found {
+			grant.References.Remove(apId)
+		}
+	}
+
+	delete(s.references, apId)
+}
+
+// Reference registers that the access provider requires the usage grant
+func (s *UsageGrantState) Reference(key UsageGrantKey, apId string) {
+	s.addReference(apId, key)
+
+	if grant, found := s.grants[key]; found {
+		grant.References.Add(apId)
+
+		return
+	}
+
+	s.grants[key] = &usageGrant{Pending: true, References: set.NewSet(apId)}
+}
+
+func (s *UsageGrantState) addReference(apId string, key UsageGrantKey) {
+	if _, found := s.references[apId]; !found {
+		s.references[apId] = set.NewSet[UsageGrantKey]()
+	}
+
+	s.references[apId].Add(key)
+}
+
+func (s *UsageGrantState) IsReferenced(key UsageGrantKey) bool {
+	grant, found := s.grants[key]
+
+	return found && len(grant.References) > 0
+}
+
+func (s *UsageGrantState) IsPending(key UsageGrantKey) bool {
+	grant, found := s.grants[key]
+
+	return found && grant.Pending
+}
+
+func (s *UsageGrantState) HasPending(item SecurableItemKey) bool {
+	for key, grant := range s.grants {
+		if key.Item == item && grant.Pending {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *UsageGrantState) SetOwned(key UsageGrantKey, owned bool) {
+	if grant, found := s.grants[key]; found {
+		grant.Owned = owned
+		grant.Pending = false
+	}
+}
+
+// Unreferenced returns all grants that are no longer required by any access provider, split on whether Raito created the grant
+func (s *UsageGrantState) Unreferenced() (owned []UsageGrantKey, notOwned []UsageGrantKey) {
+	for key, grant := range s.grants {
+		if grant.Pending || len(grant.References) > 0 {
+			continue
+		}
+
+		if grant.Owned {
+			owned = append(owned, key)
+		} else {
+			notOwned = append(notOwned, key)
+		}
+	}
+
+	return owned, notOwned
+}
+
+func (s *UsageGrantState) Delete(key UsageGrantKey) {
+	if grant, found := s.grants[key]; found {
+		for apId := range grant.References {
+			s.references[apId].Remove(key)
+		}
+	}
+
+	delete(s.grants, key)
+}
@@ -16,8 +16,17 @@ import (
 )
 
 func GetAndValidateParameters(configParams *config.ConfigMap) (pltfrm platform.DatabricksPlatform, accountId string, repoCredentials repo.RepositoryCredentials, err error) {
+	repoCredentials = repo.GenerateConfig(configParams)
 	accountId = configParams.GetString(constants.DatabricksAccountId)
 
+	// The account id can also be set in the environment or in the config profile
+	if accountId == "" {
+		accountId, err = repoCredentials.ResolveAccountId()
+		if err != nil {
+			return 0, "", repo.RepositoryCredentials{}, fmt.Errorf("resolve %s: %w", constants.DatabricksAccountId, err)
+		}
+	}
+
 	if accountId == "" {
 		return 0, "", repo.RepositoryCredentials{}, fmt.Errorf("%s is not set", constants.DatabricksAccountId)
 	}
@@ -27,7 +36,46 @@ func GetAndValidateParameters(configParams *config.ConfigMap) (pltfrm platform.D
 		return 0, "", repo.RepositoryCredentials{}, fmt.Errorf("invalid platform: %w", err)
 	}
 
-	return pltfrm, accountId, repo.GenerateConfig(configParams), nil
+	if _, err = configParams.Unmarshal(constants.DatabricksWorkspaceHosts, &repoCredentials.Endpoints.WorkspaceHosts); err != nil {
+		return 0, "", repo.RepositoryCredentials{}, fmt.Errorf("unmarshal %s: %w", constants.DatabricksWorkspaceHosts, err)
+	}
+
+	if _, err = configParams.Unmarshal(constants.DatabricksWorkspaceCredentials, &repoCredentials.WorkspaceCredentials); err != nil {
+		return 0, "", repo.RepositoryCredentials{}, fmt.Errorf("unmarshal %s: %w", constants.DatabricksWorkspaceCredentials, err)
+	}
+
+	err = validateWorkspaceCredentials(repoCredentials.WorkspaceCredentials)
+	if err != nil {
+		return 0, "", repo.RepositoryCredentials{}, fmt.Errorf("invalid %s: %w", constants.DatabricksWorkspaceCredentials, err)
+	}
+
+	err = repoCredentials.Endpoints.Validate()
+	if err != nil {
+		return 0, "", repo.RepositoryCredentials{}, fmt.Errorf("invalid %s: %w", constants.DatabricksWorkspaceHostTemplate, err)
+	}
+
+	return pltfrm, accountId, repoCredentials, nil
+}
+
+// validateWorkspaceCredentials ensures each entry refers to a workspace and no workspace is configured twice
+func validateWorkspaceCredentials(workspaceCredentials []repo.WorkspaceCredentials) error {
+	workspaces := set.NewSet[string]()
+
+	for i := range workspaceCredentials {
+		workspace := workspaceCredentials[i].Workspace
+
+		if workspace == "" {
+			return fmt.Errorf("entry %d has no workspace", i+1)
+		}
+
+		if workspaces.Contains(workspace) {
+			return fmt.Errorf("workspace %q is configured more than once", workspace)
+		}
+
+		workspaces.Add(workspace)
+	}
+
+	return nil
 }
 
 func AddToSetInMap[K comparable, V comparable](m map[K]set.Set[V], k K, v ...V) {
@@ -47,14 +95,21 @@ func InitializeWorkspaceRepoCredentials(repoCredentials repo.RepositoryCredentia
 		return nil, errors.New("unable to find workspace")
 	}
 
+	repoCredentials = repoCredentials.ForWorkspace(workspace.WorkspaceId, workspace.DeploymentName)
+
+	host, overridden, err := repoCredentials.Endpoints.ResolveWorkspaceHost(pltfrm, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("workspace address for workspace %q: %w", workspace.WorkspaceName, err)
+	}
+
 	if pltfrm == platform.DatabricksPlatformAzure && workspace.AzureWorkspaceInfo != nil && repoCredentials.AzureClientId != "" {
 		repoCredentials.AzureResourceId = fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Databricks/workspaces/%s", workspace.AzureWorkspaceInfo.SubscriptionId, workspace.AzureWorkspaceInfo.ResourceGroup, workspace.WorkspaceName)
-	} else {
-		host, err := pltfrm.WorkspaceAddress(workspace.DeploymentName)
-		if err != nil {
-			return nil, fmt.Errorf("workspace address for workspace %q: %w", workspace.WorkspaceName, err)
-		}
 
+		// The host is resolved from the Azure resource id, unless it is explicitly overridden (e.g. a private link host)
+		if overridden {
+			repoCredentials.Host = host
+		}
+	} else {
 		repoCredentials.Host = host
 	}
 
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdkconfig "github.com/databricks/databricks-sdk-go/config"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cli-plugin-databricks/databricks/constants"
+	"cli-plugin-databricks/databricks/platform"
+)
+
+func TestGetAndValidateParameters_ConfigProfile(t *testing.T) {
+	// Given
+	t.Setenv("DATABRICKS_HOST", "https://env-host.cloud.databricks.com")
+
+	configFile := filepath.Join(t.TempDir(), ".databrickscfg")
+	err := os.WriteFile(configFile, []byte("[ci]\nhost = https://accounts.cloud.databricks.com\naccount_id = profile-account\ntoken = profile-token\n"), 0600)
+	require.NoError(t, err)
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksPlatform:      "AWS",
+			constants.DatabricksConfigProfile: "ci",
+			constants.DatabricksConfigFile:    configFile,
+		},
+	}
+
+	// When
+	pltfrm, accountId, repoCredentials, err := GetAndValidateParameters(configMap)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, platform.DatabricksPlatformAWS, pltfrm)
+	assert.Equal(t, "profile-account", accountId)
+
+	repoCredentials.Host = "https://workspace.cloud.databricks.com"
+	databricksConfig := (*sdkconfig.Config)(repoCredentials.DatabricksConfig())
+
+	require.NoError(t, databricksConfig.EnsureResolved())
+	assert.Equal(t, "https://workspace.cloud.databricks.com", databricksConfig.Host)
+	assert.Equal(t, "profile-token", databricksConfig.Token)
+}
+
+func TestGetAndValidateParameters_MissingAccountId(t *testing.T) {
+	// Given
+	t.Setenv("DATABRICKS_ACCOUNT_ID", "")
+
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksPlatform:   "AWS",
+			constants.DatabricksConfigFile: filepath.Join(t.TempDir(), ".databrickscfg"),
+		},
+	}
+
+	// When
+	_, _, _, err := GetAndValidateParameters(configMap)
+
+	// Then
+	require.EqualError(t, err, "databricks-account-id is not set")
+}
+
+func TestInitializeWorkspaceRepoCredentials_CustomEndpoints(t *testing.T) {
+	// Given
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:             "account",
+			constants.DatabricksPlatform:              "Azure",
+			constants.DatabricksAzureClientId:         "azureClientId",
+			constants.DatabricksAccountHost:           "https://accounts.azuredatabricks.cn",
+			constants.DatabricksWorkspaceHostTemplate: "https://{deployment}.azuredatabricks.cn",
+			constants.DatabricksWorkspaceHosts:        `{"2": "https://workspace2.privatelink.azuredatabricks.cn"}`,
+		},
+	}
+
+	pltfrm, _, repoCredentials, err := GetAndValidateParameters(configMap)
+	require.NoError(t, err)
+
+	accountHost, err := repoCredentials.Endpoints.ResolveAccountHost(pltfrm)
+	require.NoError(t, err)
+	assert.Equal(t, "https://accounts.azuredatabricks.cn", accountHost)
+
+	azureWorkspaceInfo := &provisioning.AzureWorkspaceInfo{SubscriptionId: "subscription", ResourceGroup: "resourceGroup"}
+
+	// When
+	credentials1, err := InitializeWorkspaceRepoCredentials(repoCredentials, pltfrm, &provisioning.Workspace{WorkspaceId: 1, WorkspaceName: "workspace1", DeploymentName: "deployment1", AzureWorkspaceInfo: azureWorkspaceInfo})
+	require.NoError(t, err)
+
+	credentials2, err := InitializeWorkspaceRepoCredentials(repoCredentials, pltfrm, &provisioning.Workspace{WorkspaceId: 2, WorkspaceName: "workspace2", DeploymentName: "deployment2", AzureWorkspaceInfo: azureWorkspaceInfo})
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, "https://deployment1.azuredatabricks.cn", credentials1.Host)
+	assert.Equal(t, "/subscriptions/subscription/resourceGroups/resourceGroup/providers/Microsoft.Databricks/workspaces/workspace1", credentials1.AzureResourceId)
+	assert.Equal(t, "https://workspace2.privatelink.azuredatabricks.cn", credentials2.Host)
+}
+
+func TestGetAndValidateParameters_InvalidWorkspaceHostTemplate(t *testing.T) {
+	// Given
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:             "account",
+			constants.DatabricksPlatform:              "AWS",
+			constants.DatabricksWorkspaceHostTemplate: "https://workspace.cloud.databricks.us",
+		},
+	}
+
+	// When
+	_, _, _, err := GetAndValidateParameters(configMap)
+
+	// Then
+	require.ErrorContains(t, err, "invalid databricks-workspace-host-template")
+}
+
+func TestInitializeWorkspaceRepoCredentials_WorkspaceCredentials(t *testing.T) {
+	// Given
+	configMap := &config.ConfigMap{
+		Parameters: map[string]string{
+			constants.DatabricksAccountId:            "account",
+			constants.DatabricksPlatform:             "AWS",
+			constants.DatabricksClientId:             "accountClientId",
+			constants.DatabricksClientSecret:         "accountClientSecret",
+			constants.DatabricksAuthType:             "env-oidc",
+			constants.DatabricksOidcTokenEnv:         "ACCOUNT_OIDC_TOKEN",
+			constants.DatabricksTokenAudience:        "account",
+			constants.DatabricksAzureEnvironment:     "USGOVERNMENT",
+			constants.DatabricksWorkspaceCredentials: `[{"workspace": "deployment2", "token": "workspaceToken"}, {"workspace": "3", "client-id": "workspaceClientId", "client-secret": "workspaceClientSecret", "auth-type": "oauth-m2m"}]`,
+		},
+	}
+
+	_, _, repoCredentials, err := GetAndValidateParameters(configMap)
+	require.NoError(t, err)
+
+	// When
+	credentials1, err := InitializeWorkspaceRepoCredentials(repoCredentials, platform.DatabricksPlatformAWS, &provisioning.Workspace{WorkspaceId: 1, DeploymentName: "deployment1"})
+	require.NoError(t, err)
+
+	credentials2, err := InitializeWorkspaceRepoCredentials(repoCredentials, platform.DatabricksPlatformAWS, &provisioning.Workspace{WorkspaceId: 2, DeploymentName: "deployment2"})
+	require.NoError(t, err)
+
+	credentials3, err := InitializeWorkspaceRepoCredentials(repoCredentials, platform.DatabricksPlatformAWS, &provisioning.Workspace{WorkspaceId: 3, DeploymentName: "deployment3"})
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, "accountClientId", credentials1.ClientId)
+	assert.Equal(t, "accountClientSecret", credentials1.ClientSecret)
+	assert.Empty(t, credentials1.Token)
+
+	assert.Equal(t, "https://deployment2.cloud.databricks.com", credentials2.Host)
+	assert.Equal(t, "workspaceToken", credentials2.Token)
+	assert.Empty(t, credentials2.ClientId)
+	assert.Empty(t, credentials2.ClientSecret)
+	assert.Empty(t, credentials2.AuthType)
+	assert.Empty(t, credentials2.OIDCTokenEnv)
+	assert.Empty(t, credentials2.TokenAudience)
+	assert.Empty(t, credentials2.AzureEnvironment)
+
+	assert.Equal(t, "workspaceClientId", credentials3.ClientId)
+	assert.Equal(t, "workspaceClientSecret", credentials3.ClientSecret)
+	assert.Equal(t, "oauth-m2m", credentials3.AuthType)
+	assert.Empty(t, credentials3.OIDCTokenEnv)
+
+	// The account credentials are not modified
+	assert.Equal(t, "accountClientId", repoCredentials.ClientId)
+}
+
+func TestGetAndValidateParameters_InvalidWorkspaceCredentials(t *testing.T) {
+	tests := []struct {
+		name                 string
+		workspaceCredentials string
+		expectedError        string
+	}{
+		{
+			name:                 "Missing workspace",
+			workspaceCredentials: `[{"workspace": "1", "token": "token1"}, {"token": "token2"}]`,
+			expectedError:        "invalid databricks-workspace-credentials: entry 2 has no workspace",
+		},
+		{
+			name:                 "Duplicate workspace",
+			workspaceCredentials: `[{"workspace": "deployment1", "token": "token1"}, {"workspace": "deployment1", "token": "token2"}]`,
+			expectedError:        `invalid databricks-workspace-credentials: workspace "deployment1" is configured more than once`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			configMap := &config.ConfigMap{
+				Parameters: map[string]string{
+					constants.DatabricksAccountId:            "account",
+					constants.DatabricksPlatform:             "AWS",
+					constants.DatabricksWorkspaceCredentials: tt.workspaceCredentials,
+				},
+			}
+
+			// When
+			_, _, _, err := GetAndValidateParameters(configMap)
+
+			// Then
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
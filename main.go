@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/raito-io/cli/base"
@@ -17,59 +18,111 @@ import (
 
 var logger hclog.Logger
 
+var pluginInfo = &plugin.PluginInfo{
+	Name:    "Databricks",
+	Version: plugin.ParseVersion(version.Version),
+	Parameters: []*plugin.ParameterInfo{
+		{Name: constants.DatabricksAccountId, Description: "The Databricks account to connect to. If not set, the account_id of the config profile or the DATABRICKS_ACCOUNT_ID environment variable is used.", Mandatory: false},
+		{Name: constants.DatabricksPlatform, Description: "The Databricks platform to connect to (AWS/GCP/Azure).", Mandatory: true},
+
+		// Native authentication
+		{Name: constants.DatabricksClientId, Description: "The (oauth) client ID to use when authenticating against the Databricks account.", Mandatory: false},
+		{Name: constants.DatabricksClientSecret, Description: "The (oauth) client Secret to use when authentic against the Databricks account.", Mandatory: false},
+		{Name: constants.DatabricksUser, Description: "The username to authenticate against the Databricks account.", Mandatory: false},
+		{Name: constants.DatabricksPassword, Description: "The password to authenticate against the Databricks account.", Mandatory: false},
+		{Name: constants.DatabricksToken, Description: "The Databricks personal access token (PAT) (AWS, Azure, and GCP) or Azure Active Directory (Azure AD) token (Azure).", Mandatory: false},
+
+		// Azure authentication
+		{Name: constants.DatabricksAzureUseMSI, Description: "true to use Azure Managed Service Identity passwordless authentication flow for service principals. Requires AzureResourceID to be set.", Mandatory: false},
+		{Name: constants.DatabricksAzureClientId, Description: "The Azure AD service principal's client secret.", Mandatory: false},
+		{Name: constants.DatabricksAzureClientSecret, Description: "The Azure AD service principal's application ID.", Mandatory: false},
+		{Name: constants.DatabricksAzureTenantID, Description: "The Azure AD service principal's tenant ID.", Mandatory: false},
+		{Name: constants.DatabricksAzureEnvironment, Description: "The Azure environment type (such as Public, UsGov, China, and Germany) for a specific set of API endpoints. Defaults to PUBLIC.", Mandatory: false},
+
+		// GCP authentication
+		{Name: constants.DatabricksGoogleCredentials, Description: "GCP Service Account Credentials JSON or the location of these credentials on the local filesystem.", Mandatory: false},
+		{Name: constants.DatabricksGoogleServiceAccount, Description: "The Google Cloud Platform (GCP) service account e-mail used for impersonation in the Default Application Credentials Flow that does not require a password.", Mandatory: false},
+
+		// Unified authentication
+		{Name: constants.DatabricksConfigProfile, Description: "The profile of the Databricks config file to authenticate with. The host of the profile is ignored.", Mandatory: false},
+		{Name: constants.DatabricksConfigFile, Description: "The path of the Databricks config file. Default is ~/.databrickscfg.", Mandatory: false},
+		{Name: constants.DatabricksAuthType, Description: "The Databricks unified authentication type to use (e.g. 'pat', 'oauth-m2m', 'databricks-cli', 'github-oidc', 'env-oidc', 'file-oidc', 'azure-cli'). If not set, all authentication types are tried in order.", Mandatory: false},
+		{Name: constants.DatabricksOidcTokenEnv, Description: "The environment variable containing the OIDC ID token to exchange for a Databricks token (env-oidc). Default is DATABRICKS_OIDC_TOKEN.", Mandatory: false},
+		{Name: constants.DatabricksOidcTokenFile, Description: "The file containing the OIDC ID token to exchange for a Databricks token (file-oidc).", Mandatory: false},
+		{Name: constants.DatabricksTokenAudience, Description: "The audience of the OIDC ID token. Default is the account ID for account level authentication.", Mandatory: false},
+
+		// Custom endpoints
+		{Name: constants.DatabricksAccountHost, Description: "The host of the account console (e.g. https://accounts.cloud.databricks.us). Default is the account console of the Databricks platform.", Mandatory: false},
+		{Name: constants.DatabricksWorkspaceHostTemplate, Description: "The template of the workspace hosts (e.g. https://{deployment}.cloud.databricks.us). The placeholders {deployment}, {workspace_id} and {workspace_name} are replaced by the deployment name, ID and name of the workspace. Default is the workspace domain of the Databricks platform.", Mandatory: false},
+		{Name: constants.DatabricksWorkspaceHosts, Description: "A JSON map of workspace IDs or deployment names to workspace hosts (e.g. private link hosts). These hosts take precedence over the workspace host template.", Mandatory: false},
+		{Name: constants.DatabricksWorkspaceCredentials, Description: "A JSON list of workspaces (deployment name or ID) with the credentials (user, password, client-id, client-secret, token, azure-use-msi, azure-client-id, azure-client-secret, azure-tenant-id, google-credentials, google-service-account, config-profile, auth-type) to use instead of the account credentials.", Mandatory: false},
+
+		{Name: constants.DatabricksDataUsageWindow, Description: "The maximum number of days of usage data to retrieve. Default is 90. Maximum is 90 days.", Mandatory: false},
+		{Name: constants.DatabricksMissingEmailStrategy, Description: "How to import users without email: 'username' (use the username as email), 'empty' (import without email), 'skip' (do not import the user) or 'fail' (fail the identity store sync). Default is 'username'.", Mandatory: false},
+		{Name: constants.DatabricksLinkByExternalId, Description: "If set to true, the identifier of users, service principals and groups in the identity provider (the SCIM externalId) is used as their external ID, so they can be linked to the identities of the identity store of that identity provider. Default is false.", Mandatory: false},
+		{Name: constants.DatabricksIdentityStoreMaster, Description: "If set to true, the Databricks identity store can act as master identity store. Only enable this if no identity provider is connected to Raito. Default is false.", Mandatory: false},
+		{Name: constants.DatabricksWorkspaceLocalIdentities, Description: "If set to true, the workspace-local groups of each workspace are imported as well, namespaced by workspace. The workspaces can be filtered with databricks-include-workspaces and databricks-exclude-workspaces. Default is false.", Mandatory: false},
+		{Name: constants.DatabricksRequestsPerSecond, Description: "The maximum number of API requests per second to the account and to each workspace, shared by all clients. Set to 0 to disable client-side rate limiting. Default is 15.", Mandatory: false},
+		{Name: constants.DatabricksRetryTimeout, Description: "The maximum number of seconds requests that are throttled by Databricks (HTTP 429) or timed out (HTTP 504) are retried. Default is 300.", Mandatory: false},
+
+		// Data Object selection
+		{Name: constants.DatabricksExcludeWorkspaces, Description: "Optional comma-separated list of workspaces to exclude. If specified, only these workspaces will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
+		{Name: constants.DatabricksIncludeWorkspaces, Description: "Optional comma-separated list of workspaces to include. If specified, only these workspaces will be handled. Wildcards (*) can be used.", Mandatory: false},
+		{Name: constants.DatabricksExcludeMetastores, Description: "Optional comma-separated list of metastores to exclude. If specified, only these metastores will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
+		{Name: constants.DatabricksIncludeMetastores, Description: "Optional comma-separated list of metastores to include. If specified, only these metastores will be handled. Wildcards (*) can be used.", Mandatory: false},
+		{Name: constants.DatabricksExcludeCatalogs, Description: "Optional comma-separated list of catalogs to exclude. If specified, only these catalogs will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
+		{Name: constants.DatabricksIncludeCatalogs, Description: "Optional comma-separated list of catalogs to include. If specified, only these catalogs will be handled. Wildcards (*) can be used.", Mandatory: false},
+		{Name: constants.DatabricksExcludeSchemas, Description: "Optional comma-separated list of schemas to exclude. If specified, only these schemas will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
+		{Name: constants.DatabricksIncludeSchemas, Description: "Optional comma-separated list of schemas to include. If specified, only these schemas will be handled. Wildcards (*) can be used.", Mandatory: false},
+		{Name: constants.DatabricksExcludeTables, Description: "Optional comma-separated list of tables to exclude. If specified, only these tables will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
+		{Name: constants.DatabricksIncludeTables, Description: "Optional comma-separated list of tables to include. If specified, only these tables will be handled. Wildcards (*) can be used.", Mandatory: false},
+		{Name: constants.DatabricksDataObjectFilter, Description: "Optional expression to select data objects on path, name, type, table_type, owner, comment and tags, e.g. 'not tag(\"raito:ignore\")'. Data objects that do not match are skipped together with all data objects within them.", Mandatory: false},
+		{Name: constants.DatabricksTableDetails, Description: "If set to true, the size and number of files of each Delta table are loaded with DESCRIBE DETAIL through the configured SQL warehouses. This requires one query per table.", Mandatory: false},
+		{Name: constants.DatabricksCatalogParallelism, Description: "The number of catalogs of which the schemas are listed concurrently while traversing. Default is 1.", Mandatory: false},
+		{Name: constants.DatabricksSchemaParallelism, Description: "The number of schemas of which the tables and functions are listed concurrently while traversing. Default is 1.", Mandatory: false},
+		{Name: constants.DatabricksCheckpointFile, Description: "If set, each completely traversed metastore, catalog and schema is stored in this file, together with the listed data objects and the responses of the requests per data object, so an interrupted sync resumes without listing and requesting them again. The checkpoint is removed once the sync completes.", Mandatory: false},
+		{Name: constants.DatabricksMetadataCacheFile, Description: "If set, the tables of each schema are cached in this file. If no table of a schema was created, dropped or modified since the previous sync, the tables are read from the cache instead of being listed again with all details.", Mandatory: false},
+		{Name: constants.DatabricksMetadataCacheMaxAge, Description: "The maximum age in hours of cached tables, after which they are listed again regardless of their modification time. Default is 168 (7 days).", Mandatory: false},
+
+		// Grant naming
+		{Name: constants.DatabricksIncludeMetastoreInGrantName, Description: "Prefix the grant name with the metastore name.", Mandatory: false},
+
+		// Access import
+		{Name: constants.DatabricksImportEffectivePermissions, Description: "If set to true, the effective permissions of each securable are imported, including the permissions inherited from parent securables. Default is false.", Mandatory: false},
+		{Name: constants.DatabricksGrantGrouping, Description: "The strategy to group imported grants into access providers: 'none' (one access provider per data object and privilege), 'principal-set', 'principal' or 'schema'. Default is 'none'.", Mandatory: false},
+
+		// Access export
+		{Name: constants.DatabricksUsageGrantStateFile, Description: "The file in which the plugin keeps track of the USE CATALOG and USE SCHEMA grants it added implicitly. If set, these grants are revoked once no access provider requires them anymore.", Mandatory: false},
+		{Name: constants.DatabricksAbacFunctionSchema, Description: "The schema in which the functions of catalog level tag based masks and filters are created. Default is 'default'.", Mandatory: false},
+		{Name: constants.DatabricksAbacFilterColumnTags, Description: "JSON object with, for each column referenced in tag based filters, the tag condition that matches the column, e.g. {\"region\": \"hasTagValue('geo', 'region')\"}.", Mandatory: false},
+		{Name: constants.DatabricksManageAccountRoles, Description: "If set to true, the account admin, marketplace admin and metastore admin roles can be granted and revoked from Raito. Otherwise these roles are only imported. Default is false.", Mandatory: false},
+
+		// Tags
+		{Name: constants.DatabricksTagLoading, Description: "The strategy to load tags: 'warehouse' (information_schema tag tables through the configured SQL warehouses) or 'rest' (entity tag assignments API, no SQL warehouse or additional privileges required). Default is 'warehouse'.", Mandatory: false},
+		{Name: constants.DatabricksTagExportFile, Description: "A JSON file with the Raito tags (dataObjectFullName, key, stringValue) to apply on catalogs, schemas, tables and columns during the data source sync.", Mandatory: false},
+		{Name: constants.DatabricksTagExportStateFile, Description: "The file in which the plugin keeps track of the tags it applied. Only these tags are updated or removed. Required if databricks-tag-export-file is set.", Mandatory: false},
+
+		// Lineage
+		{Name: constants.DatabricksLineageFile, Description: "If set, the table and column lineage between the synced data objects is loaded from the lineage system tables (system.access.table_lineage and system.access.column_lineage) and written to this JSON file for use by other tooling. Lineage is not synced to Raito. Requires a SQL warehouse per metastore in databricks-sql-warehouses.", Mandatory: false},
+		{Name: constants.DatabricksLineageWindow, Description: "The number of days of lineage to load. Default is 30.", Mandatory: false},
+	},
+}
+
 func main() {
 	logger = base.Logger()
 	logger.SetLevel(hclog.Debug)
 
+	if len(os.Args) > 1 && os.Args[1] == "preflight" {
+		os.Exit(runPreflight(os.Args[2:]))
+	}
+
 	err := base.RegisterPlugins(
 		wrappers.DataSourceSync(databricks.NewDataSourceSyncer()),
 		wrappers.IdentityStoreSync(databricks.NewIdentityStoreSyncer()),
 		wrappers.DataAccessSync(databricks.NewAccessSyncer(), access_provider.WithSupportPartialSync()),
 		wrappers.DataUsageSync(databricks.NewDataUsageSyncer()),
 		&info.InfoImpl{
-			Info: &plugin.PluginInfo{
-				Name:    "Databricks",
-				Version: plugin.ParseVersion(version.Version),
-				Parameters: []*plugin.ParameterInfo{
-					{Name: constants.DatabricksAccountId, Description: "The Databricks account to connect to.", Mandatory: true},
-					{Name: constants.DatabricksPlatform, Description: "The Databricks platform to connect to (AWS/GCP/Azure).", Mandatory: true},
-
-					// Native authentication
-					{Name: constants.DatabricksClientId, Description: "The (oauth) client ID to use when authenticating against the Databricks account.", Mandatory: false},
-					{Name: constants.DatabricksClientSecret, Description: "The (oauth) client Secret to use when authentic against the Databricks account.", Mandatory: false},
-					{Name: constants.DatabricksUser, Description: "The username to authenticate against the Databricks account.", Mandatory: false},
-					{Name: constants.DatabricksPassword, Description: "The password to authenticate against the Databricks account.", Mandatory: false},
-					{Name: constants.DatabricksToken, Description: "The Databricks personal access token (PAT) (AWS, Azure, and GCP) or Azure Active Directory (Azure AD) token (Azure).", Mandatory: false},
-
-					// Azure authentication
-					{Name: constants.DatabricksAzureUseMSI, Description: "true to use Azure Managed Service Identity passwordless authentication flow for service principals. Requires AzureResourceID to be set.", Mandatory: false},
-					{Name: constants.DatabricksAzureClientId, Description: "The Azure AD service principal's client secret.", Mandatory: false},
-					{Name: constants.DatabricksAzureClientSecret, Description: "The Azure AD service principal's application ID.", Mandatory: false},
-					{Name: constants.DatabricksAzureTenantID, Description: "The Azure AD service principal's tenant ID.", Mandatory: false},
-					{Name: constants.DatabricksAzureEnvironment, Description: "The Azure environment type (such as Public, UsGov, China, and Germany) for a specific set of API endpoints. Defaults to PUBLIC.", Mandatory: false},
-
-					// GCP authentication
-					{Name: constants.DatabricksGoogleCredentials, Description: "GCP Service Account Credentials JSON or the location of these credentials on the local filesystem.", Mandatory: false},
-					{Name: constants.DatabricksGoogleServiceAccount, Description: "The Google Cloud Platform (GCP) service account e-mail used for impersonation in the Default Application Credentials Flow that does not require a password.", Mandatory: false},
-
-					{Name: constants.DatabricksDataUsageWindow, Description: "The maximum number of days of usage data to retrieve. Default is 90. Maximum is 90 days.", Mandatory: false},
-
-					// Data Object selection
-					{Name: constants.DatabricksExcludeWorkspaces, Description: "Optional comma-separated list of workspaces to exclude. If specified, only these workspaces will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
-					{Name: constants.DatabricksIncludeWorkspaces, Description: "Optional comma-separated list of workspaces to include. If specified, only these workspaces will be handled. Wildcards (*) can be used.", Mandatory: false},
-					{Name: constants.DatabricksExcludeMetastores, Description: "Optional comma-separated list of metastores to exclude. If specified, only these metastores will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
-					{Name: constants.DatabricksIncludeMetastores, Description: "Optional comma-separated list of metastores to include. If specified, only these metastores will be handled. Wildcards (*) can be used.", Mandatory: false},
-					{Name: constants.DatabricksExcludeCatalogs, Description: "Optional comma-separated list of catalogs to exclude. If specified, only these catalogs will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
-					{Name: constants.DatabricksIncludeCatalogs, Description: "Optional comma-separated list of catalogs to include. If specified, only these catalogs will be handled. Wildcards (*) can be used.", Mandatory: false},
-					{Name: constants.DatabricksExcludeSchemas, Description: "Optional comma-separated list of schemas to exclude. If specified, only these schemas will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
-					{Name: constants.DatabricksIncludeSchemas, Description: "Optional comma-separated list of schemas to include. If specified, only these schemas will be handled. Wildcards (*) can be used.", Mandatory: false},
-					{Name: constants.DatabricksExcludeTables, Description: "Optional comma-separated list of tables to exclude. If specified, only these tables will not be handled. Wildcards (*) can be used. Excludes have preference over includes.", Mandatory: false},
-					{Name: constants.DatabricksIncludeTables, Description: "Optional comma-separated list of tables to include. If specified, only these tables will be handled. Wildcards (*) can be used.", Mandatory: false},
-
-					// Grant naming
-					{Name: constants.DatabricksIncludeMetastoreInGrantName, Description: "Prefix the grant name with the metastore name.", Mandatory: false},
-				},
-			},
+			Info: pluginInfo,
 		},
 	)
 
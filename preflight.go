@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/golang-set/set"
+	"gopkg.in/yaml.v3"
+
+	"cli-plugin-databricks/databricks"
+	"cli-plugin-databricks/databricks/constants"
+)
+
+const (
+	preflightDefaultConfigFile = "raito.yml"
+	preflightConnectorSuffix   = "cli-plugin-databricks"
+	preflightParameterPrefix   = "databricks-" // Prefix of the plugin parameters, the other target keys are Raito CLI settings
+)
+
+// preflightSecretParameters are never accepted as flags, as flags end up in the shell history and in the process list.
+// They are read from the Raito CLI configuration file, or from the DATABRICKS_* environment variables of the Databricks SDK.
+var preflightSecretParameters = set.NewSet(
+	constants.DatabricksPassword,
+	constants.DatabricksClientSecret,
+	constants.DatabricksToken,
+	constants.DatabricksAzureClientSecret,
+	constants.DatabricksGoogleCredentials,
+	constants.DatabricksWorkspaceCredentials,
+)
+
+// runPreflight checks the permissions of the plugin principal and prints a compatibility matrix of the syncs.
+// The parameters are read from the Databricks target in the Raito CLI configuration file. Non-secret parameters can be overridden with flags, e.g. preflight --databricks-platform=AWS.
+func runPreflight(args []string) int {
+	parameters, err := loadPreflightParameters(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "preflight parameters: %s\n", err.Error())
+
+		return 2
+	}
+
+	report, err := databricks.NewPreflightChecker().Run(context.Background(), &config.ConfigMap{Parameters: parameters})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "preflight check failed: %s\n", err.Error())
+
+		return 1
+	}
+
+	err = report.Print(os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "print preflight report: %s\n", err.Error())
+
+		return 1
+	}
+
+	if report.Failed() {
+		return 1
+	}
+
+	return 0
+}
+
+// loadPreflightParameters returns the parameters of the Databricks target in the Raito CLI configuration file, overridden by the flags.
+// Only the parameters that are set are returned, so the defaults of the plugin apply to the others.
+func loadPreflightParameters(args []string) (map[string]string, error) {
+	flags := flag.NewFlagSet("preflight", flag.ContinueOnError)
+
+	configFile := flags.String("config-file", "", fmt.Sprintf("The Raito CLI configuration file to read the parameters of the Databricks target from. Default is %s, if it exists.", preflightDefaultConfigFile))
+	targetName := flags.String("target", "", "The name of the target in the configuration file. Required if the file contains multiple Databricks targets.")
+
+	for _, parameter := range pluginInfo.Parameters {
+		if !preflightSecretParameters.Contains(parameter.Name) {
+			flags.String(parameter.Name, "", parameter.Description)
+		}
+	}
+
+	// The warehouses are needed for the warehouse checks, but are no parameter of the plugin info
+	if flags.Lookup(constants.DatabricksSqlWarehouses) == nil {
+		flags.String(constants.DatabricksSqlWarehouses, "", "A list of workspaces and the SQL warehouse to use in each workspace, as JSON.")
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	path := *configFile
+	if path == "" {
+		if _, statErr := os.Stat(preflightDefaultConfigFile); statErr == nil {
+			path = preflightDefaultConfigFile
+		}
+	}
+
+	parameters := make(map[string]string)
+
+	if path != "" {
+		parameters, err = loadTargetParameters(path, *targetName)
+		if err != nil {
+			return nil, err
+		}
+	} else if *targetName != "" {
+		return nil, fmt.Errorf("--target requires a configuration file, but %s does not exist", preflightDefaultConfigFile)
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name != "config-file" && f.Name != "target" {
+			parameters[f.Name] = f.Value.String()
+		}
+	})
+
+	return parameters, nil
+}
+
+// loadTargetParameters returns the plugin parameters (databricks-*) of the Databricks target in the Raito CLI configuration file.
+// Like in the Raito CLI, values of the form {{ENV_VAR}} are read from the environment and non-string values are passed as JSON.
+func loadTargetParameters(path string, targetName string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read configuration file %q: %w", path, err)
+	}
+
+	var raitoConfig struct {
+		Targets []map[string]interface{} `yaml:"targets"`
+	}
+
+	err = yaml.Unmarshal(content, &raitoConfig)
+	if err != nil {
+		return nil, fmt.Errorf("parse configuration file %q: %w", path, err)
+	}
+
+	var targets []map[string]interface{}
+
+	for _, target := range raitoConfig.Targets {
+		name, _ := target["name"].(string)
+		connector, _ := target["connector-name"].(string)
+
+		if (targetName != "" && name == targetName) || (targetName == "" && strings.HasSuffix(connector, preflightConnectorSuffix)) {
+			targets = append(targets, target)
+		}
+	}
+
+	switch {
+	case len(targets) == 0 && targetName != "":
+		return nil, fmt.Errorf("no target %q found in configuration file %q", targetName, path)
+	case len(targets) == 0:
+		return nil, fmt.Errorf("no Databricks target found in configuration file %q", path)
+	case len(targets) > 1:
+		return nil, fmt.Errorf("multiple Databricks targets found in configuration file %q, select one with --target", path)
+	}
+
+	parameters := make(map[string]string)
+
+	for name, value := range targets[0] {
+		if !strings.HasPrefix(name, preflightParameterPrefix) || value == nil {
+			continue
+		}
+
+		parameters[name], err = targetParameterValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+	}
+
+	return parameters, nil
+}
+
+func targetParameterValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		trimmed := strings.TrimSpace(v)
+
+		if strings.HasPrefix(trimmed, "{{") && strings.HasSuffix(trimmed, "}}") {
+			envVar := trimmed[2 : len(trimmed)-2]
+
+			envValue, found := os.LookupEnv(envVar)
+			if !found {
+				return "", fmt.Errorf("no environment variable with name %s found", envVar)
+			}
+
+			return envValue, nil
+		}
+
+		return v, nil
+	case int:
+		return strconv.Itoa(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		jsonValue, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("marshal value: %w", err)
+		}
+
+		return string(jsonValue), nil
+	}
+}
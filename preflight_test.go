@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadPreflightParameters(t *testing.T) {
+	// Given
+	configFile := filepath.Join(t.TempDir(), "raito.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+targets:
+  - name: snowflake
+    connector-name: raito-io/cli-plugin-snowflake
+    sf-password: snowflake-secret
+  - name: databricks
+    connector-name: raito-io/cli-plugin-databricks
+    data-source-id: ds-1
+    databricks-account-id: account-1
+    databricks-platform: GCP
+    databricks-client-id: client-1
+    databricks-client-secret: "{{PREFLIGHT_TEST_CLIENT_SECRET}}"
+    databricks-link-by-external-id: true
+    databricks-sql-warehouses:
+      - workspace: "1234"
+        warehouse: warehouse-1
+`), 0600))
+
+	t.Setenv("PREFLIGHT_TEST_CLIENT_SECRET", "secret-1")
+
+	// When
+	parameters, err := loadPreflightParameters([]string{"--config-file", configFile, "--databricks-platform=AWS"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"databricks-account-id":          "account-1",
+		"databricks-platform":            "AWS",
+		"databricks-client-id":           "client-1",
+		"databricks-client-secret":       "secret-1",
+		"databricks-link-by-external-id": "true",
+		"databricks-sql-warehouses":      `[{"warehouse":"warehouse-1","workspace":"1234"}]`,
+	}, parameters)
+
+	// When
+	_, err = loadPreflightParameters([]string{"--config-file", configFile, "--databricks-client-secret=secret-2"})
+
+	// Then
+	require.ErrorContains(t, err, "flag provided but not defined: -databricks-client-secret")
+
+	// When
+	_, err = loadPreflightParameters([]string{"--config-file", configFile, "--target", "unknown"})
+
+	// Then
+	require.ErrorContains(t, err, `no target "unknown" found`)
+}
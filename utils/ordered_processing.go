@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"context"
+)
+
+// ProcessOrdered calls process for each input on a bounded number of goroutines and passes the results to handle in the order of the inputs.
+// All handle calls are executed on the calling goroutine, so handle does not need to be safe for concurrent use.
+// At most parallelism inputs are processed or waiting to be handled at the same time. Processing stops as soon as handle returns an error.
+func ProcessOrdered[I any, O any](ctx context.Context, parallelism int, inputs []I, process func(ctx context.Context, input I) O, handle func(input I, output O) error) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan O, len(inputs))
+	for i := range results {
+		results[i] = make(chan O, 1)
+	}
+
+	slots := make(chan struct{}, parallelism)
+
+	go func() {
+		for i := range inputs {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			go func() {
+				results[i] <- process(ctx, inputs[i])
+			}()
+		}
+	}()
+
+	for i := range inputs {
+		var output O
+
+		select {
+		case output = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		err := handle(inputs[i], output)
+
+		<-slots
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessOrdered(t *testing.T) {
+	inputs := []int{5, 1, 4, 2, 3, 0}
+
+	var running, maxRunning atomic.Int32
+
+	var handled []int
+
+	err := ProcessOrdered(context.Background(), 3, inputs, func(_ context.Context, input int) int {
+		current := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			previous := maxRunning.Load()
+			if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		time.Sleep(time.Duration(input) * time.Millisecond)
+
+		return input * 10
+	}, func(input int, output int) error {
+		assert.Equal(t, input*10, output)
+
+		handled = append(handled, input)
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, inputs, handled)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+}
+
+func TestProcessOrdered_HandleError(t *testing.T) {
+	var processed atomic.Int32
+
+	var handled []int
+
+	err := ProcessOrdered(context.Background(), 1, []int{1, 2, 3, 4}, func(_ context.Context, input int) int {
+		processed.Add(1)
+
+		return input
+	}, func(input int, _ int) error {
+		handled = append(handled, input)
+
+		if input == 2 {
+			return errors.New("boom")
+		}
+
+		return nil
+	})
+
+	require.EqualError(t, err, "boom")
+	assert.Equal(t, []int{1, 2}, handled)
+	assert.LessOrEqual(t, processed.Load(), int32(3))
+}